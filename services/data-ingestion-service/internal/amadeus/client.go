@@ -250,8 +250,10 @@ type AmadeusFareDetailsBySegment struct {
 	Cabin           string                     `json:"cabin"`
 	FareBasis       string                     `json:"fareBasis"`
 	BrandedFare     string                     `json:"brandedFare,omitempty"`
+	BrandedFareLabel string                    `json:"brandedFareLabel,omitempty"`
 	Class           string                     `json:"class"`
 	IncludedCheckedBags *AmadeusCheckedBags    `json:"includedCheckedBags,omitempty"`
+	IncludedCabinBags   *AmadeusCabinBags      `json:"includedCabinBags,omitempty"`
 	Amenities       []AmadeusAmenity           `json:"amenities,omitempty"`
 }
 
@@ -262,6 +264,11 @@ type AmadeusCheckedBags struct {
 	WeightUnit string `json:"weightUnit,omitempty"`
 }
 
+// AmadeusCabinBags represents Amadeus cabin bags
+type AmadeusCabinBags struct {
+	Quantity int `json:"quantity"`
+}
+
 // AmadeusAmenity represents Amadeus amenity
 type AmadeusAmenity struct {
 	Description string `json:"description"`
@@ -438,27 +445,46 @@ func (c *Client) SearchFlights(ctx context.Context, searchReq *models.FlightSear
 
 // convertToAmadeusRequest converts internal request to Amadeus format
 func (c *Client) convertToAmadeusRequest(req *models.FlightSearchRequest) *AmadeusFlightSearchRequest {
-	originDestinations := []OriginDestination{
-		{
+	var originDestinations []OriginDestination
+	if len(req.Legs) > 0 {
+		// Multi-city: one origin-destination per leg, in travel order
+		for i, leg := range req.Legs {
+			originDestinations = append(originDestinations, OriginDestination{
+				ID:                      strconv.Itoa(i + 1),
+				OriginLocationCode:      leg.OriginCode,
+				DestinationLocationCode: leg.DestinationCode,
+				DepartureDateTimeRange: DateTimeRange{
+					Date: leg.DepartureDate.Format("2006-01-02"),
+				},
+			})
+		}
+	} else {
+		originDestinations = append(originDestinations, OriginDestination{
 			ID:                      "1",
 			OriginLocationCode:      req.OriginCode,
 			DestinationLocationCode: req.DestinationCode,
 			DepartureDateTimeRange: DateTimeRange{
 				Date: req.DepartureDate.Format("2006-01-02"),
 			},
-		},
+		})
+
+		// Add return flight if round trip
+		if req.ReturnDate != nil {
+			originDestinations = append(originDestinations, OriginDestination{
+				ID:                      "2",
+				OriginLocationCode:      req.DestinationCode,
+				DestinationLocationCode: req.OriginCode,
+				DepartureDateTimeRange: DateTimeRange{
+					Date: req.ReturnDate.Format("2006-01-02"),
+				},
+			})
+		}
 	}
 
-	// Add return flight if round trip
-	if req.ReturnDate != nil {
-		originDestinations = append(originDestinations, OriginDestination{
-			ID:                      "2",
-			OriginLocationCode:      req.DestinationCode,
-			DestinationLocationCode: req.OriginCode,
-			DepartureDateTimeRange: DateTimeRange{
-				Date: req.ReturnDate.Format("2006-01-02"),
-			},
-		})
+	// The cabin restriction applies to every origin-destination
+	originDestinationIDs := make([]string, len(originDestinations))
+	for i, originDestination := range originDestinations {
+		originDestinationIDs[i] = originDestination.ID
 	}
 
 	travelers := []Traveler{}
@@ -499,7 +525,7 @@ func (c *Client) convertToAmadeusRequest(req *models.FlightSearchRequest) *Amade
 				{
 					Cabin:                req.CabinClass,
 					Coverage:             "MOST_SEGMENTS",
-					OriginDestinationIds: []string{"1"},
+					OriginDestinationIds: originDestinationIDs,
 				},
 			},
 		},
@@ -623,6 +649,7 @@ func (c *Client) convertFlightOffer(amadeusOffer *AmadeusFlightOffer, dictionari
 				Cabin:        amadeusFS.Cabin,
 				FareBasis:    amadeusFS.FareBasis,
 				BrandedFare:  amadeusFS.BrandedFare,
+				BrandedFareLabel: amadeusFS.BrandedFareLabel,
 				Class:        amadeusFS.Class,
 			}
 
@@ -634,6 +661,20 @@ func (c *Client) convertFlightOffer(amadeusOffer *AmadeusFlightOffer, dictionari
 				}
 			}
 
+			if amadeusFS.IncludedCabinBags != nil {
+				fs.IncludedCabinBags = &models.CabinBags{
+					Quantity: amadeusFS.IncludedCabinBags.Quantity,
+				}
+			}
+
+			for _, amenity := range amadeusFS.Amenities {
+				fs.Amenities = append(fs.Amenities, models.Amenity{
+					Description:  amenity.Description,
+					AmenityType:  amenity.AmenityType,
+					IsChargeable: amenity.IsChargeable,
+				})
+			}
+
 			tp.FareDetailsBySegment = append(tp.FareDetailsBySegment, fs)
 		}
 
@@ -561,7 +561,10 @@ func (c *Client) SearchFlights(ctx context.Context, filters SearchFilters, optio
 	}, nil
 }
 
-// DeleteExpiredDocuments deletes expired flight documents
+// DeleteExpiredDocuments deletes expired flight documents. It stays a delete-by-query because it
+// works on this service's own single index (ELASTICSEARCH_INDEX), which is not behind the
+// search-service aliases; expired offers in the search-service flight indices are dropped by
+// deleting whole rolled-over indices in its index lifecycle.
 func (c *Client) DeleteExpiredDocuments(ctx context.Context) error {
 	query := elastic.NewRangeQuery("expires_at").Lt(time.Now())
 	
@@ -20,9 +20,17 @@ type FlightSearchRequest struct {
 	CabinClass      string    `json:"cabin_class" validate:"oneof=ECONOMY PREMIUM_ECONOMY BUSINESS FIRST"`
 	Currency        string    `json:"currency" validate:"required,len=3"`
 	MaxResults      int       `json:"max_results" validate:"min=1,max=250"`
+	Legs            []SearchLeg `json:"legs,omitempty" validate:"omitempty,min=2,max=6,dive"` // multi-city legs in travel order
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// SearchLeg represents one leg of a multi-city flight search
+type SearchLeg struct {
+	OriginCode      string    `json:"origin_code" validate:"required,len=3"`
+	DestinationCode string    `json:"destination_code" validate:"required,len=3"`
+	DepartureDate   time.Time `json:"departure_date" validate:"required"`
+}
+
 // FlightSearchResponse represents the response from a flight search
 type FlightSearchResponse struct {
 	ID               string         `json:"id"`
@@ -184,8 +192,11 @@ type FareDetails struct {
 	FareBasis     string `json:"fare_basis"`
 	BookingClass  string `json:"booking_class"`
 	BrandedFare   string `json:"branded_fare,omitempty"`
+	BrandedFareLabel string `json:"branded_fare_label,omitempty"`
 	Class         string `json:"class"`
 	IncludedCheckedBags *CheckedBags `json:"included_checked_bags,omitempty"`
+	IncludedCabinBags   *CabinBags   `json:"included_cabin_bags,omitempty"`
+	Amenities     []Amenity `json:"amenities,omitempty"`
 }
 
 // CheckedBags represents checked baggage allowance
@@ -195,6 +206,18 @@ type CheckedBags struct {
 	WeightUnit string `json:"weight_unit,omitempty"`
 }
 
+// CabinBags represents cabin baggage allowance
+type CabinBags struct {
+	Quantity int `json:"quantity"`
+}
+
+// Amenity represents a service of a branded fare, e.g. a checked bag, seat selection or changes
+type Amenity struct {
+	Description  string `json:"description"`
+	AmenityType  string `json:"amenity_type"`
+	IsChargeable bool   `json:"is_chargeable"`
+}
+
 // PricingDetail represents pricing detail
 type PricingDetail struct {
 	TravelClass          string          `json:"travel_class"`
@@ -8,6 +8,7 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/lib/pq v1.10.9
 	github.com/shopspring/decimal v1.3.1
+	spontra/shared v0.0.0
 )
 
 require (
@@ -38,3 +39,6 @@ require (
 	google.golang.org/protobuf v1.30.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// shared lives in the monorepo rather than a module proxy
+replace spontra/shared => ../../shared
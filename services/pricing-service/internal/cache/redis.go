@@ -240,9 +240,9 @@ func NewCacheKeyBuilder(prefix string) *CacheKeyBuilder {
 }
 
 // PriceComparison builds a cache key for price comparison
-func (ckb *CacheKeyBuilder) PriceComparison(origin, destination, departureDate, tripType string, passengers int) string {
-	return fmt.Sprintf("%s:price_comparison:%s-%s:%s:%s:%d", 
-		ckb.prefix, origin, destination, departureDate, tripType, passengers)
+func (ckb *CacheKeyBuilder) PriceComparison(origin, destination, departureDate, tripType string, passengers int, currency string) string {
+	return fmt.Sprintf("%s:price_comparison:%s-%s:%s:%s:%d:%s", 
+		ckb.prefix, origin, destination, departureDate, tripType, passengers, currency)
 }
 
 // PriceHistory builds a cache key for price history
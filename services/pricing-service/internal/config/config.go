@@ -51,6 +51,11 @@ type Config struct {
 	SMTPUsername string
 	SMTPPassword string
 	FromEmail    string
+	
+	// Currency conversion
+	FXBaseCurrency string
+	FXRatesFile    string // JSON rates file used when data-ingestion has no cached rates; empty uses the built-in rates
+	FXRatesRefresh time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -98,6 +103,11 @@ func Load() (*Config, error) {
 		SMTPUsername: getEnv("SMTP_USERNAME", ""),
 		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 		FromEmail:    getEnv("FROM_EMAIL", "noreply@spontra.com"),
+		
+		// Currency conversion
+		FXBaseCurrency: getEnv("FX_BASE_CURRENCY", "EUR"),
+		FXRatesFile:    getEnv("FX_RATES_FILE", ""),
+		FXRatesRefresh: time.Minute * time.Duration(getEnvAsInt("FX_RATES_REFRESH_MINUTES", 60)),
 	}
 	
 	// Validate required configuration
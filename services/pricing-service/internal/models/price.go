@@ -16,6 +16,8 @@ type FlightPrice struct {
 	ReturnDate       *time.Time      `json:"return_date,omitempty" db:"return_date"`
 	Price            decimal.Decimal `json:"price" db:"price"`
 	Currency         string          `json:"currency" db:"currency"`
+	OriginalPrice    *decimal.Decimal `json:"original_price,omitempty" db:"-"`    // provider's amount, when converted
+	OriginalCurrency string           `json:"original_currency,omitempty" db:"-"` // provider's currency, when converted
 	TripType         string          `json:"trip_type" db:"trip_type"` // "oneway", "return"
 	PassengerCount   int             `json:"passenger_count" db:"passenger_count"`
 	CabinClass       string          `json:"cabin_class" db:"cabin_class"` // "economy", "premium", "business", "first"
@@ -124,6 +126,7 @@ type PriceComparisonRequest struct {
 	TripType           string     `json:"trip_type" binding:"required"`
 	Providers          []string   `json:"providers,omitempty"`
 	MaxResults         int        `json:"max_results,omitempty"`
+	Currency           string     `json:"currency,omitempty"` // prices are converted to and ranked in this currency
 }
 
 // PriceComparisonResponse represents the response with compared prices
@@ -8,6 +8,7 @@ import (
 	"spontra/pricing-service/internal/cache"
 	"spontra/pricing-service/internal/models"
 	"spontra/pricing-service/internal/repository"
+	"spontra/shared/fx"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -18,6 +19,7 @@ type AlertService struct {
 	priceRepo       *repository.PriceRepository
 	cache           *cache.RedisClient
 	cacheKeyBuilder *cache.CacheKeyBuilder
+	converter       *fx.Converter
 	maxAlertsPerUser int
 }
 
@@ -26,6 +28,7 @@ func NewAlertService(
 	alertRepo *repository.AlertRepository,
 	priceRepo *repository.PriceRepository,
 	redisClient *cache.RedisClient,
+	converter *fx.Converter,
 	maxAlertsPerUser int,
 ) *AlertService {
 	return &AlertService{
@@ -33,6 +36,7 @@ func NewAlertService(
 		priceRepo:        priceRepo,
 		cache:            redisClient,
 		cacheKeyBuilder:  cache.NewCacheKeyBuilder("alerts"),
+		converter:        converter,
 		maxAlertsPerUser: maxAlertsPerUser,
 	}
 }
@@ -216,17 +220,21 @@ func (s *AlertService) checkSingleAlert(alert *models.PriceAlert) error {
 		PassengerCount:     alert.PassengerCount,
 		CabinClass:         alert.CabinClass,
 		TripType:           alert.TripType,
-		MaxResults:         1, // We only need the best price
 	}
 	
-	// Get the best current price
-	bestPrice, err := s.priceRepo.GetBestPrice(req)
+	// Rank every current price in the alert's currency; providers quote in different currencies
+	prices, err := s.priceRepo.GetFlightPrices(req)
 	if err != nil {
+		return fmt.Errorf("failed to get flight prices: %w", err)
+	}
+	prices = localizePrices(s.converter, prices, displayCurrency(s.converter, alert.Currency))
+	if len(prices) == 0 {
 		// No prices available, skip this alert
 		return nil
 	}
 	
 	// Check if the price triggers the alert
+	bestPrice := &prices[0]
 	if bestPrice.Price.LessThanOrEqual(alert.MaxPrice) {
 		return s.triggerAlert(alert, bestPrice)
 	}
@@ -335,13 +343,8 @@ func (s *AlertService) ValidateAlertRequest(req *models.PriceAlertRequest) error
 	}
 	
 	// Validate currency
-	validCurrencies := map[string]bool{
-		"EUR": true,
-		"USD": true,
-		"GBP": true,
-	}
-	
-	if !validCurrencies[req.Currency] {
+	req.Currency = fx.NormalizeCurrency(req.Currency)
+	if req.Currency == "" || !s.converter.Supports(req.Currency) {
 		return fmt.Errorf("invalid currency: %s", req.Currency)
 	}
 	
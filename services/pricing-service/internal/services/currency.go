@@ -0,0 +1,46 @@
+package services
+
+import (
+	"log"
+	"sort"
+
+	"spontra/pricing-service/internal/models"
+	"spontra/shared/fx"
+)
+
+// displayCurrency returns the currency prices are compared in, defaulting to the FX base
+func displayCurrency(converter *fx.Converter, currency string) string {
+	if currency = fx.NormalizeCurrency(currency); currency != "" {
+		return currency
+	}
+	return converter.Base()
+}
+
+// localizePrices returns copies of prices converted to the currency, cheapest first. The
+// provider's amount is kept as the original price. Prices in a currency without a rate are
+// left out, since they cannot be ranked against the others.
+func localizePrices(converter *fx.Converter, prices []models.FlightPrice, currency string) []models.FlightPrice {
+	localized := make([]models.FlightPrice, 0, len(prices))
+	for _, price := range prices {
+		from := displayCurrency(converter, price.Currency)
+		if from != currency {
+			amount, err := converter.Convert(price.Price, from, currency)
+			if err != nil {
+				log.Printf("Skipping %s price %s: %v", price.ProviderName, price.ID, err)
+				continue
+			}
+			original := price.Price
+			price.OriginalPrice = &original
+			price.OriginalCurrency = from
+			price.Price = amount
+			price.Currency = currency
+		}
+		localized = append(localized, price)
+	}
+
+	sort.SliceStable(localized, func(i, j int) bool {
+		return localized[i].Price.LessThan(localized[j].Price)
+	})
+
+	return localized
+}
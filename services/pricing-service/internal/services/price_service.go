@@ -8,6 +8,7 @@ import (
 	"spontra/pricing-service/internal/cache"
 	"spontra/pricing-service/internal/models"
 	"spontra/pricing-service/internal/repository"
+	"spontra/shared/fx"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -17,6 +18,7 @@ type PriceService struct {
 	priceRepo   *repository.PriceRepository
 	cache       *cache.RedisClient
 	cacheKeyBuilder *cache.CacheKeyBuilder
+	converter   *fx.Converter
 	cacheTTL    time.Duration
 }
 
@@ -24,12 +26,14 @@ type PriceService struct {
 func NewPriceService(
 	priceRepo *repository.PriceRepository,
 	redisClient *cache.RedisClient,
+	converter *fx.Converter,
 	cacheTTL time.Duration,
 ) *PriceService {
 	return &PriceService{
 		priceRepo:       priceRepo,
 		cache:           redisClient,
 		cacheKeyBuilder: cache.NewCacheKeyBuilder("pricing"),
+		converter:       converter,
 		cacheTTL:        cacheTTL,
 	}
 }
@@ -37,6 +41,7 @@ func NewPriceService(
 // ComparePrices compares prices from multiple providers
 func (s *PriceService) ComparePrices(req *models.PriceComparisonRequest) (*models.PriceComparisonResponse, error) {
 	requestID := uuid.New().String()
+	currency := displayCurrency(s.converter, req.Currency)
 	
 	// Generate cache key
 	cacheKey := s.cacheKeyBuilder.PriceComparison(
@@ -45,6 +50,7 @@ func (s *PriceService) ComparePrices(req *models.PriceComparisonRequest) (*model
 		req.DepartureDate.Format("2006-01-02"),
 		req.TripType,
 		req.PassengerCount,
+		currency,
 	)
 	
 	// Try to get from cache first
@@ -58,9 +64,12 @@ func (s *PriceService) ComparePrices(req *models.PriceComparisonRequest) (*model
 	}
 	
 	// Get prices from database
-	prices, err := s.priceRepo.GetFlightPrices(req)
+	prices, err := s.rankedPrices(req, currency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get flight prices: %w", err)
+		return nil, err
+	}
+	if req.MaxResults > 0 && len(prices) > req.MaxResults {
+		prices = prices[:req.MaxResults]
 	}
 	
 	// Calculate statistics
@@ -68,16 +77,15 @@ func (s *PriceService) ComparePrices(req *models.PriceComparisonRequest) (*model
 		RequestID:     requestID,
 		Prices:        prices,
 		ProviderCount: len(s.getUniqueProviders(prices)),
-		Currency:      "EUR", // Default currency
+		Currency:      currency,
 		SearchTime:    time.Now(),
 		CacheHit:      cacheHit,
 	}
 	
 	if len(prices) > 0 {
-		response.BestPrice = &prices[0] // Prices are ordered by price ASC
+		response.BestPrice = &prices[0] // Prices are ranked cheapest first
 		response.AveragePrice = s.calculateAveragePrice(prices)
 		response.PriceSpread = s.calculatePriceSpread(prices)
-		response.Currency = prices[0].Currency
 	}
 	
 	// Cache the response
@@ -132,12 +140,15 @@ func (s *PriceService) GetPriceHistory(origin, destination string, days int) ([]
 
 // GetBestPrice returns the best price for given criteria
 func (s *PriceService) GetBestPrice(req *models.PriceComparisonRequest) (*models.FlightPrice, error) {
-	bestPrice, err := s.priceRepo.GetBestPrice(req)
+	prices, err := s.rankedPrices(req, displayCurrency(s.converter, req.Currency))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get best price: %w", err)
 	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("failed to get best price: no prices found")
+	}
 	
-	return bestPrice, nil
+	return &prices[0], nil
 }
 
 // GetPriceStatistics returns price statistics for a route
@@ -183,6 +194,20 @@ func (s *PriceService) CleanupExpiredPrices() error {
 
 // Helper methods
 
+// rankedPrices returns every matching price converted to the currency, cheapest first. The
+// database orders by raw amount, so the result limit is applied by the caller after ranking.
+func (s *PriceService) rankedPrices(req *models.PriceComparisonRequest, currency string) ([]models.FlightPrice, error) {
+	query := *req
+	query.MaxResults = 0
+	
+	prices, err := s.priceRepo.GetFlightPrices(&query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight prices: %w", err)
+	}
+	
+	return localizePrices(s.converter, prices, currency), nil
+}
+
 func (s *PriceService) getUniqueProviders(prices []models.FlightPrice) []string {
 	providerMap := make(map[string]bool)
 	for _, price := range prices {
@@ -215,7 +240,7 @@ func (s *PriceService) calculatePriceSpread(prices []models.FlightPrice) decimal
 		return decimal.Zero
 	}
 	
-	// Prices are already sorted, so first is min, last is max
+	// Prices are ranked, so first is min, last is max
 	minPrice := prices[0].Price
 	maxPrice := prices[len(prices)-1].Price
 	
@@ -256,6 +281,11 @@ func (s *PriceService) ValidateSearchRequest(req *models.PriceComparisonRequest)
 		return fmt.Errorf("max results must be between 0 and 100")
 	}
 	
+	req.Currency = fx.NormalizeCurrency(req.Currency)
+	if req.Currency != "" && !s.converter.Supports(req.Currency) {
+		return fmt.Errorf("unsupported currency: %s", req.Currency)
+	}
+	
 	// Validate cabin class
 	validCabinClasses := map[string]bool{
 		"economy":  true,
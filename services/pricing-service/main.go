@@ -11,6 +11,7 @@ import (
 	"spontra/pricing-service/internal/handlers"
 	"spontra/pricing-service/internal/repository"
 	"spontra/pricing-service/internal/services"
+	"spontra/shared/fx"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -52,10 +53,17 @@ func main() {
 	alertRepo := repository.NewAlertRepository(db)
 	trackingRepo := repository.NewTrackingRepository(db)
 
+	// Convert with the rates data-ingestion caches, falling back to the configured rates file
+	converter := fx.NewConverter(
+		fx.ChainSource{fx.NewCacheSource(redisClient), fx.NewFileSource(cfg.FXRatesFile)},
+		cfg.FXBaseCurrency,
+		cfg.FXRatesRefresh,
+	)
+
 	// Initialize services
-	priceService := services.NewPriceService(priceRepo, redisClient, cfg.PriceComparisonTTL)
+	priceService := services.NewPriceService(priceRepo, redisClient, converter, cfg.PriceComparisonTTL)
 	analyticsService := services.NewAnalyticsService(priceRepo, redisClient, cfg.TrendsCacheTTL)
-	alertService := services.NewAlertService(alertRepo, priceRepo, redisClient, cfg.MaxAlertsPerUser)
+	alertService := services.NewAlertService(alertRepo, priceRepo, redisClient, converter, cfg.MaxAlertsPerUser)
 	trackingService := services.NewTrackingService(trackingRepo, priceRepo, redisClient, cfg.MaxTrackingPerUser)
 
 	// Initialize handlers
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/database"
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/repository"
+	"spontra/search-service/internal/services"
+)
+
+// backfill-durations estimates every airport pair inside a region that is missing from
+// flight_durations and stores the estimates, flagged as estimated.
+//
+//	go run ./cmd/backfill-durations -countries=ES,PT,FR
+func main() {
+	countries := flag.String("countries", "", "comma-separated ISO country codes making up the region")
+	batchSize := flag.Int("batch", 500, "rows inserted per transaction")
+	dryRun := flag.Bool("dry-run", false, "report missing routes without inserting them")
+	flag.Parse()
+
+	var countryCodes []string
+	for _, code := range strings.Split(*countries, ",") {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			countryCodes = append(countryCodes, code)
+		}
+	}
+	if len(countryCodes) == 0 {
+		log.Fatal("At least one country code is required, e.g. -countries=ES,PT")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTables(); err != nil {
+		log.Fatal("Failed to create tables:", err)
+	}
+
+	esClient, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to Elasticsearch:", err)
+	}
+
+	durationRepo := repository.NewDurationRepository(db.DB)
+	estimator := services.NewDurationEstimator(cfg, esClient)
+
+	airports, err := esClient.AirportsInCountries(countryCodes, 0)
+	if err != nil {
+		log.Fatal("Failed to list airports:", err)
+	}
+
+	codes := make([]string, 0, len(airports))
+	for _, airport := range airports {
+		codes = append(codes, airport.Code)
+	}
+	log.Printf("Found %d airports in %s", len(airports), strings.Join(countryCodes, ", "))
+
+	existing, err := durationRepo.GetRouteKeys(codes)
+	if err != nil {
+		log.Fatal("Failed to load existing routes:", err)
+	}
+
+	var batch []repository.FlightDuration
+	missing, inserted := 0, 0
+	for _, origin := range airports {
+		for _, destination := range airports {
+			if origin.Code == destination.Code || existing[origin.Code+"-"+destination.Code] {
+				continue
+			}
+			if origin.Coordinates == nil || destination.Coordinates == nil {
+				log.Printf("Skipping %s -> %s: missing coordinates", origin.Code, destination.Code)
+				continue
+			}
+
+			missing++
+			if *dryRun {
+				continue
+			}
+
+			batch = append(batch, estimator.Estimate(origin.Code, destination.Code, *origin.Coordinates, *destination.Coordinates))
+			if len(batch) >= *batchSize {
+				if err := durationRepo.InsertFlightDurations(batch); err != nil {
+					log.Fatal("Failed to insert estimated durations:", err)
+				}
+				inserted += len(batch)
+				log.Printf("Inserted %d estimated durations...", inserted)
+				batch = batch[:0]
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := durationRepo.InsertFlightDurations(batch); err != nil {
+			log.Fatal("Failed to insert estimated durations:", err)
+		}
+		inserted += len(batch)
+	}
+
+	log.Printf("Backfill complete: %d missing routes, %d estimated durations inserted", missing, inserted)
+}
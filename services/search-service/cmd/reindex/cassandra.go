@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/providers"
+)
+
+// inventoryProvider marks flights rebuilt from flight_inventory
+const inventoryProvider = "data-ingestion"
+
+// inventorySource reads unexpired flight offers from the Cassandra flight_inventory table
+type inventorySource struct {
+	hosts    []string
+	keyspace string
+}
+
+// load pages through flight_inventory and hands flights to index in batches
+func (s *inventorySource) load(batchSize int, index func([]models.Flight) error) (int64, error) {
+	cluster := gocql.NewCluster(s.hosts...)
+	cluster.Keyspace = s.keyspace
+	cluster.Consistency = gocql.One
+	cluster.Timeout = 30 * time.Second
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Cassandra: %w", err)
+	}
+	defer session.Close()
+
+	query := `
+		SELECT id, origin_code, destination_code, departure_date, carrier_code, flight_number,
+			   price, currency, available_seats, cabin_class, duration, stops, expires_at
+		FROM flight_inventory`
+
+	iter := session.Query(query).PageSize(batchSize).Iter()
+
+	var (
+		id                        gocql.UUID
+		origin, destination       string
+		departureDate, expiresAt  time.Time
+		carrier, flightNumber     string
+		price                     float64
+		currency, cabin, duration string
+		seats, stops              int
+	)
+
+	now := time.Now()
+	batch := make([]models.Flight, 0, batchSize)
+	var indexed, skipped int64
+
+	for iter.Scan(&id, &origin, &destination, &departureDate, &carrier, &flightNumber,
+		&price, &currency, &seats, &cabin, &duration, &stops, &expiresAt) {
+		if expiresAt.Before(now) {
+			skipped++
+			continue
+		}
+
+		// flight_inventory keeps only the departure date, so times are relative to midnight UTC
+		minutes := providers.ParseISODuration(duration)
+		available := seats
+		batch = append(batch, models.Flight{
+			ID:                 uuid.UUID(id),
+			Provider:           inventoryProvider,
+			OriginAirport:      origin,
+			DestinationAirport: destination,
+			DepartureTime:      departureDate,
+			ArrivalTime:        departureDate.Add(time.Duration(minutes) * time.Minute),
+			Duration:           minutes,
+			Price:              decimal.NewFromFloat(price),
+			Currency:           currency,
+			CabinClass:         cabin,
+			Airline:            carrier,
+			FlightNumber:       flightNumber,
+			Stops:              stops,
+			ValidUntil:         expiresAt,
+			SeatsAvailable:     &available,
+		})
+
+		if len(batch) >= batchSize {
+			if err := index(batch); err != nil {
+				iter.Close()
+				return indexed, err
+			}
+			indexed += int64(len(batch))
+			batch = make([]models.Flight, 0, batchSize)
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return indexed, fmt.Errorf("failed to read flight_inventory: %w", err)
+	}
+
+	if len(batch) > 0 {
+		if err := index(batch); err != nil {
+			return indexed, err
+		}
+		indexed += int64(len(batch))
+	}
+
+	log.Printf("Read %d flights from flight_inventory, skipped %d expired", indexed, skipped)
+	return indexed, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/models"
+)
+
+// reindex builds a new version of an index and atomically swaps its aliases over to it.
+// Documents come from the index currently behind the read alias, or for flights from the
+// Cassandra flight_inventory table. Writes to the current indices are blocked from the start of
+// the copy until the swap, so nothing written meanwhile is lost; writers retry until the new
+// index takes over. Without -delete-old the previous version is kept for rollback, a legacy
+// unversioned index as a clone named <alias>_legacy_<timestamp>.
+//
+//	go run ./cmd/reindex -index=flights
+//	go run ./cmd/reindex -index=flights -source=cassandra -cassandra-hosts=cassandra:9042
+//	go run ./cmd/reindex -check
+func main() {
+	indexName := flag.String("index", "flights", "logical index to rebuild (flights or airports)")
+	source := flag.String("source", "index", "where documents come from: index or cassandra")
+	deleteOld := flag.Bool("delete-old", false, "delete the previous version after the alias swap")
+	check := flag.Bool("check", false, "report index versions and mapping drift without reindexing")
+	cassandraHosts := flag.String("cassandra-hosts", "localhost:9042", "comma-separated Cassandra hosts")
+	cassandraKeyspace := flag.String("cassandra-keyspace", "spontra", "Cassandra keyspace holding flight_inventory")
+	batchSize := flag.Int("batch", 1000, "flights indexed per bulk request when reading from Cassandra")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	esClient, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to Elasticsearch:", err)
+	}
+
+	if *check {
+		report(esClient)
+		return
+	}
+
+	def, err := esClient.IndexDefinition(*indexName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *source != "index" && *source != "cassandra" {
+		log.Fatalf("Unknown source %q, expected index or cassandra", *source)
+	}
+	if *source == "cassandra" && def.Name != cfg.ESFlightIndex {
+		log.Fatalf("Only the %s index can be rebuilt from Cassandra", cfg.ESFlightIndex)
+	}
+
+	state, err := esClient.IndexState(def)
+	if err != nil {
+		log.Fatal("Failed to resolve index:", err)
+	}
+
+	target, err := esClient.CreateVersionedIndex(def)
+	if err != nil {
+		log.Fatal("Failed to create index:", err)
+	}
+
+	if err := esClient.SetWriteBlock(state.Indices, true); err != nil {
+		abort(esClient, state.Indices, target)
+		log.Fatal("Failed to block writes:", err)
+	}
+	log.Printf("Blocked writes to %s until the alias swap", strings.Join(state.Indices, ", "))
+
+	var indexed int64
+	if *source == "cassandra" {
+		inventory := &inventorySource{
+			hosts:    strings.Split(*cassandraHosts, ","),
+			keyspace: *cassandraKeyspace,
+		}
+		indexed, err = inventory.load(*batchSize, func(flights []models.Flight) error {
+			return esClient.BulkIndexFlightsInto(target, flights)
+		})
+	} else {
+		indexed, err = esClient.ReindexFromAlias(def, target)
+	}
+	if err != nil {
+		abort(esClient, state.Indices, target)
+		log.Fatal("Reindex failed:", err)
+	}
+	log.Printf("Indexed %d documents into %s", indexed, target)
+
+	previous, err := esClient.SwapAliases(def, target)
+	if err != nil {
+		abort(esClient, state.Indices, target)
+		log.Fatal("Failed to swap aliases:", err)
+	}
+
+	if *deleteOld {
+		if err := esClient.DeleteIndices(previous); err != nil {
+			log.Fatal("Failed to delete previous versions:", err)
+		}
+		log.Printf("Deleted previous versions: %s", strings.Join(previous, ", "))
+	} else if len(previous) > 0 {
+		if err := esClient.SetWriteBlock(previous, false); err != nil {
+			log.Printf("Failed to unblock writes to previous versions: %v", err)
+		}
+		log.Printf("Previous versions kept for rollback: %s", strings.Join(previous, ", "))
+	}
+
+	log.Printf("Reindex of %s complete", def.Name)
+}
+
+// abort unblocks writes to the indices still behind the aliases and deletes the incomplete target
+func abort(esClient *elasticsearch.Client, sources []string, target string) {
+	if err := esClient.SetWriteBlock(sources, false); err != nil {
+		log.Printf("Failed to unblock writes to %s: %v", strings.Join(sources, ", "), err)
+	}
+	if err := esClient.DeleteIndices([]string{target}); err != nil {
+		log.Printf("Failed to delete incomplete index %s: %v", target, err)
+	}
+}
+
+// report prints the version and mapping drift of every index, and the ranges of the flight indices
+func report(esClient *elasticsearch.Client) {
+	for _, def := range esClient.IndexDefinitions() {
+		state, err := esClient.IndexState(def)
+		if err != nil {
+			log.Fatal("Failed to resolve index:", err)
+		}
+
+		status := "up to date"
+		switch {
+		case len(state.Indices) == 0:
+			status = "missing"
+		case state.Legacy:
+			status = "legacy, unversioned"
+		case state.Outdated():
+			status = "outdated"
+		}
+		log.Printf("%s: version %d of %d (%s), indices %s, write index %s",
+			def.Alias, state.Version, def.Version, status, strings.Join(state.Indices, ", "), state.WriteIndex)
+	}
+
+	ranges, err := esClient.FlightIndexRanges()
+	if err != nil {
+		log.Fatal("Failed to read flight index ranges:", err)
+	}
+	for _, r := range ranges {
+		log.Printf("%s: %d offers departing %s to %s, valid until %s, write index %t",
+			r.Index, r.Documents, r.FirstDeparture.Format(time.RFC3339), r.LastDeparture.Format(time.RFC3339),
+			r.LastValidUntil.Format(time.RFC3339), r.WriteIndex)
+	}
+
+	drift, err := esClient.CheckMappingDrift()
+	if err != nil {
+		log.Fatal("Failed to check mapping drift:", err)
+	}
+	if len(drift) == 0 {
+		log.Println("No mapping drift")
+	}
+	for _, field := range drift {
+		log.Printf("Mapping drift in %s: field %s expected %q, found %q", field.Index, field.Field, field.Expected, field.Actual)
+	}
+}
@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/config"
+)
+
+// Event types emitted by search-service, matching analytics-service's EventType values
+const (
+	EventFlightShared     = "flight_shared"
+	EventAutocompleteUsed = "autocomplete_used"
+)
+
+// eventSource and eventVersion identify search-service as the producer of an event
+const (
+	eventSource  = "search-service"
+	eventVersion = "1.0.0"
+)
+
+// Event mirrors analytics-service's Event payload
+type Event struct {
+	ID         uuid.UUID              `json:"id"`
+	Type       string                 `json:"type"`
+	UserID     *uuid.UUID             `json:"user_id,omitempty"`
+	SessionID  string                 `json:"session_id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Properties map[string]interface{} `json:"properties"`
+	Context    EventContext           `json:"context"`
+	Source     string                 `json:"source"`
+	Version    string                 `json:"version"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// EventContext carries the request context of an event
+type EventContext struct {
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	Referrer  string `json:"referrer,omitempty"`
+}
+
+// NewEvent creates an event with default values
+func NewEvent(eventType string, userID *uuid.UUID, sessionID string) *Event {
+	now := time.Now().UTC()
+	return &Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserID:     userID,
+		SessionID:  sessionID,
+		Timestamp:  now,
+		Properties: make(map[string]interface{}),
+		Source:     eventSource,
+		Version:    eventVersion,
+		CreatedAt:  now,
+	}
+}
+
+// Publisher buffers events and sends them to analytics-service in batches
+type Publisher struct {
+	cfg        *config.Config
+	httpClient *http.Client
+	events     chan *Event
+	stop       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewPublisher creates a publisher and starts its flush loop. Tracking is a no-op when
+// analytics are disabled.
+func NewPublisher(cfg *config.Config) *Publisher {
+	p := &Publisher{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		events: make(chan *Event, cfg.AnalyticsBuffer),
+		stop:   make(chan struct{}),
+	}
+
+	if cfg.EnableAnalytics {
+		p.wg.Add(1)
+		go p.run()
+	}
+
+	return p
+}
+
+// Track queues an event, dropping it when the buffer is full rather than blocking the caller
+func (p *Publisher) Track(event *Event) {
+	if !p.cfg.EnableAnalytics {
+		return
+	}
+
+	select {
+	case p.events <- event:
+	default:
+		log.Printf("Analytics buffer full, dropping %s event", event.Type)
+	}
+}
+
+// Close flushes buffered events and stops the publisher
+func (p *Publisher) Close() {
+	if !p.cfg.EnableAnalytics {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// run sends batches when the buffer fills up or the flush interval passes
+func (p *Publisher) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.AnalyticsFlushInterval)
+	defer ticker.Stop()
+
+	batchSize := p.cfg.AnalyticsBuffer / 10
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	batch := make([]*Event, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.send(batch); err != nil {
+			log.Printf("Failed to send %d analytics events: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.stop:
+			for {
+				select {
+				case event := <-p.events:
+					batch = append(batch, event)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send posts a batch of events to analytics-service
+func (p *Publisher) send(events []*Event) error {
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	resp, err := p.httpClient.Post(p.cfg.AnalyticsServiceURL+"/api/v1/events/batch", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// sessionSignatureSize is the number of HMAC-SHA256 bytes kept in a session token
+const sessionSignatureSize = 16
+
+// ErrInvalidSession is returned for session tokens that are malformed or were not signed by
+// this service
+var ErrInvalidSession = errors.New("invalid search session")
+
+// SessionSigner issues the search session tokens anonymous clients send back in X-Session-ID.
+// A token is the session ID followed by its HMAC, so a client can only present a session it
+// was issued, not one it read elsewhere.
+type SessionSigner struct {
+	secret []byte
+}
+
+// NewSessionSigner creates a session signer for the given secret
+func NewSessionSigner(secret string) *SessionSigner {
+	return &SessionSigner{secret: []byte(secret)}
+}
+
+// Issue starts a new search session and returns its ID and token
+func (s *SessionSigner) Issue() (string, string) {
+	sessionID := uuid.New()
+	return sessionID.String(), base64.RawURLEncoding.EncodeToString(sessionID[:]) + "." + s.sign(sessionID)
+}
+
+// Verify checks a session token's signature and returns its session ID
+func (s *SessionSigner) Verify(token string) (string, error) {
+	encodedID, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidSession
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil {
+		return "", ErrInvalidSession
+	}
+	sessionID, err := uuid.FromBytes(raw)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(sessionID))) {
+		return "", ErrInvalidSession
+	}
+
+	return sessionID.String(), nil
+}
+
+// sign returns the encoded, truncated HMAC-SHA256 of a session ID
+func (s *SessionSigner) sign(sessionID uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(sessionID[:])
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:sessionSignatureSize])
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Issuer is the issuer user-service sets on the access tokens it signs
+const Issuer = "spontra-user-service"
+
+// User roles carried in the role claim
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, expired, badly signed or not
+// issued by user-service
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Claims are the claims of a user-service access token
+type Claims struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Role   string    `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// HasRole reports whether the token grants a role. Tokens issued before roles existed carry
+// none and are treated as plain users.
+func (c *Claims) HasRole(role string) bool {
+	if c.Role == "" {
+		return role == RoleUser
+	}
+	return c.Role == role
+}
+
+// Verifier verifies access tokens signed by user-service with the shared JWT secret
+type Verifier struct {
+	secret []byte
+}
+
+// NewVerifier creates a verifier for the given secret
+func NewVerifier(secret string) *Verifier {
+	return &Verifier{secret: []byte(secret)}
+}
+
+// Verify checks a token's signature, issuer and expiry and returns its claims
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return v.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(Issuer))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.ExpiresAt == nil || claims.UserID == uuid.Nil {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
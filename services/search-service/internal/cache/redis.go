@@ -3,12 +3,18 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrKeyNotFound is returned by Get when the key does not exist
+var ErrKeyNotFound = errors.New("key not found")
+
 // RedisClient wraps the Redis client with application-specific methods
 type RedisClient struct {
 	client *redis.Client
@@ -51,7 +57,7 @@ func (r *RedisClient) Get(key string, dest interface{}) error {
 	data, err := r.client.Get(r.ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("key not found")
+			return ErrKeyNotFound
 		}
 		return fmt.Errorf("failed to get value: %w", err)
 	}
@@ -85,6 +91,86 @@ func (r *RedisClient) Increment(key string) (int64, error) {
 	return r.client.Incr(r.ctx, key).Result()
 }
 
+// IncrementHashField increments a numeric field of a hash
+func (r *RedisClient) IncrementHashField(key, field string) (int64, error) {
+	return r.client.HIncrBy(r.ctx, key, field, 1).Result()
+}
+
+// GetHashCounters returns the numeric fields of a hash, skipping fields that are not integers
+func (r *RedisClient) GetHashCounters(key string) (map[string]int64, error) {
+	values, err := r.client.HGetAll(r.ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash: %w", err)
+	}
+
+	counters := make(map[string]int64, len(values))
+	for field, value := range values {
+		if count, err := strconv.ParseInt(value, 10, 64); err == nil {
+			counters[field] = count
+		}
+	}
+	return counters, nil
+}
+
+// slidingWindowScript records a hit in a sorted set of hit timestamps unless the window is
+// full, and returns whether it was allowed, the hits in the window and the milliseconds until
+// the oldest hit leaves it
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// SlidingWindowResult is the outcome of a hit against a sliding window limit
+type SlidingWindowResult struct {
+	Allowed bool
+	Count   int64         // hits in the window, including this one when allowed
+	Reset   time.Duration // until the oldest hit leaves the window and frees a slot
+}
+
+// SlidingWindowHit atomically records a hit against a limit of hits per window, rejecting it
+// when the window is already full. Rejected hits are not recorded.
+func (r *RedisClient) SlidingWindowHit(key string, limit int, window time.Duration) (*SlidingWindowResult, error) {
+	now := time.Now()
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+
+	values, err := slidingWindowScript.Run(r.ctx, r.client, []string{key},
+		now.UnixMilli(), window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sliding window hit: %w", err)
+	}
+
+	return parseSlidingWindowReply(values)
+}
+
+// parseSlidingWindowReply maps the script's {allowed, count, reset in ms} reply onto a result
+func parseSlidingWindowReply(values []int64) (*SlidingWindowResult, error) {
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected sliding window reply: %v", values)
+	}
+
+	return &SlidingWindowResult{
+		Allowed: values[0] == 1,
+		Count:   values[1],
+		Reset:   time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
 // SetExpiration sets expiration for an existing key
 func (r *RedisClient) SetExpiration(key string, expiration time.Duration) error {
 	return r.client.Expire(r.ctx, key, expiration).Err()
@@ -129,9 +215,19 @@ func NewCacheKeyBuilder(prefix string) *CacheKeyBuilder {
 	return &CacheKeyBuilder{prefix: prefix}
 }
 
-// SearchResults builds a cache key for search results
-func (c *CacheKeyBuilder) SearchResults(origin, destination, date string, passengers int) string {
-	return fmt.Sprintf("%s:search:%s-%s:%s:pax%d", c.prefix, origin, destination, date, passengers)
+// SearchResults builds a cache key for provider results from a canonical request fingerprint
+func (c *CacheKeyBuilder) SearchResults(origin, destination, fingerprint string) string {
+	return fmt.Sprintf("%s:search:%s-%s:%s", c.prefix, origin, destination, fingerprint)
+}
+
+// SearchResponse builds a cache key for the full result set of a search
+func (c *CacheKeyBuilder) SearchResponse(searchID string) string {
+	return fmt.Sprintf("%s:search:results:%s", c.prefix, searchID)
+}
+
+// RefreshLock builds a lock key guarding the background refresh of a cache entry
+func (c *CacheKeyBuilder) RefreshLock(key string) string {
+	return fmt.Sprintf("%s:lock:refresh:%s", c.prefix, key)
 }
 
 // SearchSession builds a cache key for search sessions
@@ -144,9 +240,19 @@ func (c *CacheKeyBuilder) UserSearch(userID string) string {
 	return fmt.Sprintf("%s:user:%s:searches", c.prefix, userID)
 }
 
-// AirportSuggestions builds a cache key for airport suggestions
-func (c *CacheKeyBuilder) AirportSuggestions(query string) string {
-	return fmt.Sprintf("%s:airports:suggest:%s", c.prefix, query)
+// AirportSuggestions builds a cache key for airport suggestions of a normalized query
+func (c *CacheKeyBuilder) AirportSuggestions(query string, limit int) string {
+	return fmt.Sprintf("%s:airports:suggest:%d:%s", c.prefix, limit, query)
+}
+
+// AirportPicks builds the key of the hash counting picked airport suggestions
+func (c *CacheKeyBuilder) AirportPicks() string {
+	return fmt.Sprintf("%s:airports:picks", c.prefix)
+}
+
+// RateLimit builds the key of a client's sliding window under a rate limit policy
+func (c *CacheKeyBuilder) RateLimit(policy, client string) string {
+	return fmt.Sprintf("%s:ratelimit:%s:%s", c.prefix, policy, client)
 }
 
 // FlightDuration builds a cache key for flight durations
@@ -154,6 +260,16 @@ func (c *CacheKeyBuilder) FlightDuration(origin, destination string) string {
 	return fmt.Sprintf("%s:duration:%s-%s", c.prefix, origin, destination)
 }
 
+// DestinationActivities builds a cache key for a destination's activity scores
+func (c *CacheKeyBuilder) DestinationActivities(airportCode string) string {
+	return fmt.Sprintf("%s:destination:%s:activities", c.prefix, airportCode)
+}
+
+// ExploreAnywhere builds a cache key for an explore-anywhere result from a request fingerprint
+func (c *CacheKeyBuilder) ExploreAnywhere(origin, fingerprint string) string {
+	return fmt.Sprintf("%s:explore:%s:%s", c.prefix, origin, fingerprint)
+}
+
 // RouteStats builds a cache key for route statistics
 func (c *CacheKeyBuilder) RouteStats(route string) string {
 	return fmt.Sprintf("%s:stats:route:%s", c.prefix, route)
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSlidingWindowReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int64
+		want    SlidingWindowResult
+		wantErr bool
+	}{
+		{"allowed", []int64{1, 3, 42000}, SlidingWindowResult{Allowed: true, Count: 3, Reset: 42 * time.Second}, false},
+		{"rejected", []int64{0, 10, 1500}, SlidingWindowResult{Allowed: false, Count: 10, Reset: 1500 * time.Millisecond}, false},
+		{"empty window", []int64{1, 1, 0}, SlidingWindowResult{Allowed: true, Count: 1}, false},
+		{"short reply", []int64{1, 3}, SlidingWindowResult{}, true},
+		{"long reply", []int64{1, 3, 42000, 7}, SlidingWindowResult{}, true},
+		{"no reply", nil, SlidingWindowResult{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseSlidingWindowReply(tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseSlidingWindowReply(%v) = %+v, want an error", tt.values, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSlidingWindowReply(%v) error = %v", tt.values, err)
+			}
+			if *result != tt.want {
+				t.Errorf("parseSlidingWindowReply(%v) = %+v, want %+v", tt.values, *result, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ type Config struct {
 	DataIngestionServiceURL string
 	PricingServiceURL       string
 	UserServiceURL          string
+	AnalyticsServiceURL     string
 	
 	// Search configuration
 	DefaultMaxResults    int
@@ -27,26 +28,45 @@ type Config struct {
 	SearchTimeout        time.Duration
 	CacheTimeout         time.Duration
 	SessionTimeout       time.Duration
+	SessionSecret        string // signs the X-Session-ID tokens issued to anonymous search sessions
 	
 	// Elasticsearch configuration
 	ESIndexPrefix       string
 	ESFlightIndex       string
 	ESAirportIndex      string
+	ESSuggestionIndex   string
 	ESShards           int
 	ESReplicas         int
+
+	// Flight index lifecycle
+	EnableIndexLifecycle    bool
+	IndexLifecycleInterval  time.Duration
+	FlightRolloverMaxAge    time.Duration
+	FlightRolloverMaxDocs   int
+	FlightRolloverMaxSizeGB int
+	FlightRetentionGrace    time.Duration // fully expired flight indices are kept this long before deletion
 	
 	// Cache configuration
 	CacheTTL                time.Duration
 	SearchResultsCacheTTL   time.Duration
+	SearchResultsSoftTTL    time.Duration // cached results older than this are refreshed in the background
+	SearchRefreshLockTTL    time.Duration
 	AirportCacheTTL         time.Duration
+	AirportSuggestionRefresh time.Duration // how often the suggestion index is rebuilt with fresh traffic and picks
 	
 	// Rate limiting
+	EnableRateLimit   bool
 	RateLimitRequests int
 	RateLimitWindow   time.Duration
+	RateLimitPolicies map[string]int // per-route budgets per window, overriding RateLimitRequests
+	RateLimitAPIKeys  map[string]int // server-to-server API keys and their budget per window, replacing route budgets
+	TrustedProxies    []string       // proxies whose X-Forwarded-For is believed; none by default
 	
 	// Provider configuration
-	EnabledProviders     []string
+	EnabledProviders     []string                 // the legacy data-ingestion name is read as amadeus
 	ProviderTimeout      time.Duration
+	ProviderTimeouts     map[string]time.Duration // per-provider overrides of ProviderTimeout
+	ProviderPriorities   map[string]int           // lower values are queried and merged first
 	MaxRetries          int
 	
 	// Analytics
@@ -61,8 +81,51 @@ type Config struct {
 	// Flight search specific
 	FlexibleDatesMaxRange  int
 	DefaultFlexibleRange   int
+	FlexibleSearchConcurrency int // date searches run in parallel for a flexible search
+	FlexibleSearchTimeout     time.Duration // cells not searched by then are reported as timed out
+	StreamTopResults       int // flights sent in each running update of a streamed search
+	MaxExpandedAirports    int     // airports searched per side when expanding city codes and nearby airports
+	AirportPairConcurrency int     // airport pairs searched in parallel for an expanded search
+	MaxNearbyRadiusKm      float64
+	RelevanceWeights       map[string]float64 // price, duration, stops, departure and activity weights for relevance scoring
+	ActivityLookupTimeout  time.Duration // per destination; destinations not answered in time get no activity score
+	ActivityLookupConcurrency int        // destination lookups run in parallel for one scoring pass
 	MaxFlightDuration      int // hours
 	MinFlightDuration      int // hours
+
+	// Connection building
+	MinConnectionMinutes   int
+	MinConnectionTimes     map[string]int // per-hub overrides of MinConnectionMinutes
+	MaxItineraryMinutes    int            // longest total duration of a proposed connection
+	MaxConnectionPaths     int
+	DurationDatasetPath    string // CSV of route durations the duration estimator is fitted on
+
+	// Explore anywhere
+	ExploreMaxWindowDays   int
+	ExploreCandidateLimit  int // reachable destinations priced per explore request
+	ExploreHistoryDays     int // pricing history considered when no live fare is indexed
+	ExploreHistoryConcurrency int // pricing-service lookups run in parallel for one explore request
+
+	// Search history
+	SearchHistoryRetention   time.Duration
+	EnableResultSnapshots    bool          // persist full result sets so they outlive the Redis cache
+	HistoryCleanupInterval   time.Duration
+
+	// Share links
+	ShareTokenSecret       string
+	ShareTokenTTL          time.Duration
+	ShareBaseURL           string // frontend page a share token is appended to
+
+	// Currency conversion
+	FXBaseCurrency string        // provider fares are normalised to this currency before merging and ranking
+	FXRatesFile    string        // JSON rates file used when data-ingestion has no cached rates; empty uses the built-in rates
+	FXRatesRefresh time.Duration
+
+	// Trip pricing
+	AncillaryEstimates map[string]float64 // per-passenger extra prices in the FX base currency, used when a provider does not price an extra
+
+	// Admin access
+	JWTSecret string // shared with user-service, which signs the access tokens
 }
 
 // Load loads configuration from environment variables
@@ -78,6 +141,7 @@ func Load() (*Config, error) {
 		DataIngestionServiceURL: getEnv("DATA_INGESTION_SERVICE_URL", "http://localhost:8083"),
 		PricingServiceURL:       getEnv("PRICING_SERVICE_URL", "http://localhost:8082"),
 		UserServiceURL:          getEnv("USER_SERVICE_URL", "http://localhost:8080"),
+		AnalyticsServiceURL:     getEnv("ANALYTICS_SERVICE_URL", "http://localhost:8084"),
 		
 		// Search settings
 		DefaultMaxResults: getEnvAsInt("DEFAULT_MAX_RESULTS", 50),
@@ -85,26 +149,45 @@ func Load() (*Config, error) {
 		SearchTimeout:     time.Second * time.Duration(getEnvAsInt("SEARCH_TIMEOUT_SECONDS", 30)),
 		CacheTimeout:      time.Minute * time.Duration(getEnvAsInt("CACHE_TIMEOUT_MINUTES", 15)),
 		SessionTimeout:    time.Hour * time.Duration(getEnvAsInt("SESSION_TIMEOUT_HOURS", 24)),
+		SessionSecret:     getEnv("SESSION_SECRET", "change-this-session-secret"),
 		
 		// Elasticsearch
 		ESIndexPrefix:  getEnv("ES_INDEX_PREFIX", "spontra"),
 		ESFlightIndex:  getEnv("ES_FLIGHT_INDEX", "flights"),
 		ESAirportIndex: getEnv("ES_AIRPORT_INDEX", "airports"),
+		ESSuggestionIndex: getEnv("ES_SUGGESTION_INDEX", "airport_suggestions"),
 		ESShards:       getEnvAsInt("ES_SHARDS", 2),
 		ESReplicas:     getEnvAsInt("ES_REPLICAS", 1),
+
+		// Flight index lifecycle
+		EnableIndexLifecycle:    getEnvAsBool("ENABLE_INDEX_LIFECYCLE", true),
+		IndexLifecycleInterval:  time.Minute * time.Duration(getEnvAsInt("INDEX_LIFECYCLE_INTERVAL_MINUTES", 15)),
+		FlightRolloverMaxAge:    time.Hour * time.Duration(getEnvAsInt("FLIGHT_ROLLOVER_MAX_AGE_HOURS", 24)),
+		FlightRolloverMaxDocs:   getEnvAsInt("FLIGHT_ROLLOVER_MAX_DOCS", 10000000),
+		FlightRolloverMaxSizeGB: getEnvAsInt("FLIGHT_ROLLOVER_MAX_SIZE_GB", 30),
+		FlightRetentionGrace:    time.Hour * time.Duration(getEnvAsInt("FLIGHT_RETENTION_GRACE_HOURS", 6)),
 		
 		// Cache
 		CacheTTL:               time.Minute * time.Duration(getEnvAsInt("CACHE_TTL_MINUTES", 30)),
 		SearchResultsCacheTTL:  time.Minute * time.Duration(getEnvAsInt("SEARCH_RESULTS_CACHE_TTL_MINUTES", 15)),
+		SearchResultsSoftTTL:   time.Minute * time.Duration(getEnvAsInt("SEARCH_RESULTS_SOFT_TTL_MINUTES", 5)),
+		SearchRefreshLockTTL:   time.Second * time.Duration(getEnvAsInt("SEARCH_REFRESH_LOCK_SECONDS", 60)),
 		AirportCacheTTL:        time.Hour * time.Duration(getEnvAsInt("AIRPORT_CACHE_TTL_HOURS", 24)),
+		AirportSuggestionRefresh: time.Minute * time.Duration(getEnvAsInt("AIRPORT_SUGGESTION_REFRESH_MINUTES", 60)),
 		
 		// Rate limiting
+		EnableRateLimit:   getEnvAsBool("ENABLE_RATE_LIMIT", true),
 		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 		RateLimitWindow:   time.Minute * time.Duration(getEnvAsInt("RATE_LIMIT_WINDOW_MINUTES", 1)),
+		RateLimitPolicies: parseIntMap(getEnv("RATE_LIMIT_POLICIES", "search=20,autocomplete=600")),
+		RateLimitAPIKeys:  parseIntMap(getEnv("RATE_LIMIT_API_KEYS", "")),
+		TrustedProxies:    parseStringSlice(getEnv("TRUSTED_PROXIES", "")),
 		
 		// Providers
-		EnabledProviders: parseStringSlice(getEnv("ENABLED_PROVIDERS", "amadeus,data-ingestion")),
+		EnabledProviders: canonicalProviders(parseStringSlice(getEnv("ENABLED_PROVIDERS", "elasticsearch,amadeus"))),
 		ProviderTimeout:  time.Second * time.Duration(getEnvAsInt("PROVIDER_TIMEOUT_SECONDS", 20)),
+		ProviderTimeouts: canonicalProviderKeys(parseSecondsMap(getEnv("PROVIDER_TIMEOUTS_SECONDS", "elasticsearch=3"))),
+		ProviderPriorities: canonicalProviderKeys(parseIntMap(getEnv("PROVIDER_PRIORITIES", "elasticsearch=1,amadeus=2"))),
 		MaxRetries:       getEnvAsInt("MAX_RETRIES", 3),
 		
 		// Analytics
@@ -119,8 +202,51 @@ func Load() (*Config, error) {
 		// Flight search
 		FlexibleDatesMaxRange: getEnvAsInt("FLEXIBLE_DATES_MAX_RANGE", 7),
 		DefaultFlexibleRange:  getEnvAsInt("DEFAULT_FLEXIBLE_RANGE", 3),
+		FlexibleSearchConcurrency: getEnvAsInt("FLEXIBLE_SEARCH_CONCURRENCY", 4),
+		FlexibleSearchTimeout:     time.Second * time.Duration(getEnvAsInt("FLEXIBLE_SEARCH_TIMEOUT_SECONDS", 60)),
+		StreamTopResults:      getEnvAsInt("STREAM_TOP_RESULTS", 10),
+		MaxExpandedAirports:   getEnvAsInt("MAX_EXPANDED_AIRPORTS", 4),
+		AirportPairConcurrency: getEnvAsInt("AIRPORT_PAIR_CONCURRENCY", 4),
+		MaxNearbyRadiusKm:     float64(getEnvAsInt("MAX_NEARBY_RADIUS_KM", 300)),
+		RelevanceWeights:      parseFloatMap(getEnv("RELEVANCE_WEIGHTS", "price=0.35,duration=0.2,stops=0.15,departure=0.1,activity=0.2")),
+		ActivityLookupTimeout: time.Second * time.Duration(getEnvAsInt("ACTIVITY_LOOKUP_TIMEOUT_SECONDS", 2)),
+		ActivityLookupConcurrency: getEnvAsInt("ACTIVITY_LOOKUP_CONCURRENCY", 8),
 		MaxFlightDuration:     getEnvAsInt("MAX_FLIGHT_DURATION_HOURS", 24),
 		MinFlightDuration:     getEnvAsInt("MIN_FLIGHT_DURATION_HOURS", 0),
+
+		// Connection building
+		MinConnectionMinutes: getEnvAsInt("MIN_CONNECTION_MINUTES", 60),
+		MinConnectionTimes:   parseIntMap(getEnv("MIN_CONNECTION_TIMES", "LHR=90,CDG=90,FRA=60,AMS=50,IST=75")),
+		MaxItineraryMinutes:  getEnvAsInt("MAX_ITINERARY_MINUTES", 1440),
+		MaxConnectionPaths:   getEnvAsInt("MAX_CONNECTION_PATHS", 10),
+		DurationDatasetPath:  getEnv("DURATION_DATASET_PATH", "Estimated_European_Flight_Durations_10000.csv"),
+
+		// Explore anywhere
+		ExploreMaxWindowDays:  getEnvAsInt("EXPLORE_MAX_WINDOW_DAYS", 31),
+		ExploreCandidateLimit: getEnvAsInt("EXPLORE_CANDIDATE_LIMIT", 100),
+		ExploreHistoryDays:    getEnvAsInt("EXPLORE_HISTORY_DAYS", 30),
+		ExploreHistoryConcurrency: getEnvAsInt("EXPLORE_HISTORY_CONCURRENCY", 8),
+
+		// Search history
+		SearchHistoryRetention: 24 * time.Hour * time.Duration(getEnvAsInt("SEARCH_HISTORY_RETENTION_DAYS", 30)),
+		EnableResultSnapshots:  getEnvAsBool("ENABLE_RESULT_SNAPSHOTS", false),
+		HistoryCleanupInterval: time.Minute * time.Duration(getEnvAsInt("HISTORY_CLEANUP_INTERVAL_MINUTES", 60)),
+
+		// Share links
+		ShareTokenSecret: getEnv("SHARE_TOKEN_SECRET", "change-this-share-token-secret"),
+		ShareTokenTTL:    24 * time.Hour * time.Duration(getEnvAsInt("SHARE_TOKEN_TTL_DAYS", 7)),
+		ShareBaseURL:     getEnv("SHARE_BASE_URL", "http://localhost:3000/share/"),
+
+		// Currency conversion
+		FXBaseCurrency: getEnv("FX_BASE_CURRENCY", "EUR"),
+		FXRatesFile:    getEnv("FX_RATES_FILE", ""),
+		FXRatesRefresh: time.Minute * time.Duration(getEnvAsInt("FX_RATES_REFRESH_MINUTES", 60)),
+
+		// Trip pricing
+		AncillaryEstimates: parseFloatMap(getEnv("ANCILLARY_ESTIMATES", "checked_bag=40,cabin_bag=25,seat=15,change=70")),
+
+		// Admin access
+		JWTSecret: getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-this-in-production"),
 	}
 	
 	// Validate configuration
@@ -131,6 +257,15 @@ func Load() (*Config, error) {
 		if config.DataIngestionServiceURL == "http://localhost:8083" {
 			return nil, fmt.Errorf("DATA_INGESTION_SERVICE_URL must be set in production")
 		}
+		if config.ShareTokenSecret == "change-this-share-token-secret" {
+			return nil, fmt.Errorf("SHARE_TOKEN_SECRET must be set in production")
+		}
+		if config.JWTSecret == "your-super-secret-jwt-key-change-this-in-production" {
+			return nil, fmt.Errorf("JWT_SECRET must be set in production")
+		}
+		if config.SessionSecret == "change-this-session-secret" {
+			return nil, fmt.Errorf("SESSION_SECRET must be set in production")
+		}
 	}
 	
 	return config, nil
@@ -177,4 +312,75 @@ func parseStringSlice(s string) []string {
 		}
 	}
 	return result
+}
+
+// legacyProviderNames maps provider names from older configurations to the provider now serving
+// them. The data-ingestion client only ever returned Amadeus fares.
+var legacyProviderNames = map[string]string{
+	"data-ingestion": "amadeus",
+}
+
+// canonicalProviders replaces legacy provider names in a list of providers
+func canonicalProviders(names []string) []string {
+	for i, name := range names {
+		if current, ok := legacyProviderNames[name]; ok {
+			names[i] = current
+		}
+	}
+	return names
+}
+
+// canonicalProviderKeys replaces legacy provider names in per-provider settings. A setting under
+// the current name wins over one under the legacy name.
+func canonicalProviderKeys[V any](settings map[string]V) map[string]V {
+	for legacy, current := range legacyProviderNames {
+		value, ok := settings[legacy]
+		if !ok {
+			continue
+		}
+		delete(settings, legacy)
+		if _, exists := settings[current]; !exists {
+			settings[current] = value
+		}
+	}
+	return settings
+}
+
+// parseIntMap parses a comma-separated list of key=value pairs into a map of integers
+func parseIntMap(s string) map[string]int {
+	result := make(map[string]int)
+	for _, item := range parseStringSlice(s) {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if value, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+			result[strings.TrimSpace(parts[0])] = value
+		}
+	}
+	return result
+}
+
+// parseFloatMap parses a comma-separated list of key=value pairs into a map of floats
+func parseFloatMap(s string) map[string]float64 {
+	result := make(map[string]float64)
+	for _, item := range parseStringSlice(s) {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil {
+			result[strings.TrimSpace(parts[0])] = value
+		}
+	}
+	return result
+}
+
+// parseSecondsMap parses a comma-separated list of key=seconds pairs into a map of durations
+func parseSecondsMap(s string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for key, seconds := range parseIntMap(s) {
+		result[key] = time.Second * time.Duration(seconds)
+	}
+	return result
 }
\ No newline at end of file
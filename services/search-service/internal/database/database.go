@@ -50,10 +50,15 @@ func (d *Database) CreateTables() error {
 	queries := []string{
 		createSearchSessionsTable,
 		createSearchHistoryTable,
+		createSearchResultSnapshotsTable,
 		createFlightDurationsTable,
+		createAdminAuditLogTable,
+		addFlightDurationsEstimateColumns,
 		createFlightDurationsIndex,
 		createSearchSessionsIndex,
 		createSearchHistoryIndex,
+		createSearchResultSnapshotsIndex,
+		createAdminAuditLogIndex,
 	}
 
 	for _, query := range queries {
@@ -94,6 +99,30 @@ CREATE TABLE IF NOT EXISTS search_history (
 	expires_at TIMESTAMP WITH TIME ZONE NOT NULL
 );`
 
+// Snapshots hold the gzip-compressed JSON of a search's full result set
+const createSearchResultSnapshotsTable = `
+CREATE TABLE IF NOT EXISTS search_result_snapshots (
+	search_id UUID PRIMARY KEY,
+	payload BYTEA NOT NULL,
+	flight_count INTEGER DEFAULT 0,
+	uncompressed_size INTEGER DEFAULT 0,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);`
+
+// The admin audit log is append-only; entries are never updated or expired
+const createAdminAuditLogTable = `
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	actor_id UUID NOT NULL,
+	actor_email VARCHAR(255) NOT NULL,
+	action VARCHAR(100) NOT NULL,
+	parameters JSONB NOT NULL DEFAULT '{}',
+	status_code INTEGER NOT NULL,
+	ip_address INET,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);`
+
 const createFlightDurationsTable = `
 CREATE TABLE IF NOT EXISTS flight_durations (
 	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -107,6 +136,13 @@ CREATE TABLE IF NOT EXISTS flight_durations (
 	updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );`
 
+// Estimated rows are backfilled from the duration model for routes missing from the dataset.
+// An estimate says nothing about whether a direct flight exists, so it is never marked direct.
+const addFlightDurationsEstimateColumns = `
+ALTER TABLE flight_durations ADD COLUMN IF NOT EXISTS is_estimated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE flight_durations ADD COLUMN IF NOT EXISTS estimate_confidence REAL NOT NULL DEFAULT 1;
+UPDATE flight_durations SET is_direct = FALSE WHERE is_estimated AND is_direct;`
+
 const createFlightDurationsIndex = `
 CREATE INDEX IF NOT EXISTS idx_flight_durations_route 
 ON flight_durations(origin_airport, destination_airport);`
@@ -120,4 +156,11 @@ const createSearchHistoryIndex = `
 CREATE INDEX IF NOT EXISTS idx_search_history_user_id ON search_history(user_id);
 CREATE INDEX IF NOT EXISTS idx_search_history_session_id ON search_history(session_id);
 CREATE INDEX IF NOT EXISTS idx_search_history_search_id ON search_history(search_id);
-CREATE INDEX IF NOT EXISTS idx_search_history_created_at ON search_history(created_at);`
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_search_history_created_at ON search_history(created_at);`
+
+const createSearchResultSnapshotsIndex = `
+CREATE INDEX IF NOT EXISTS idx_search_result_snapshots_expires_at ON search_result_snapshots(expires_at);`
+
+const createAdminAuditLogIndex = `
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_actor_id ON admin_audit_log(actor_id);`
\ No newline at end of file
@@ -55,6 +55,14 @@ type RouteCount struct {
 	AvgPrice    float64 `json:"avg_price"`
 }
 
+// CheapestFare is the cheapest indexed fare to a destination
+type CheapestFare struct {
+	Destination   string    `json:"destination"`
+	Price         float64   `json:"price"`
+	Currency      string    `json:"currency"`
+	DepartureTime time.Time `json:"departure_time"`
+}
+
 // GetSearchAggregations returns aggregated data for search filters and insights
 func (c *Client) GetSearchAggregations(req *models.FlightSearchRequest) (*SearchAggregations, error) {
 	// Build base query (same as search but without pagination)
@@ -222,6 +230,71 @@ func (c *Client) GetPopularRoutes(limit int) ([]RouteCount, error) {
 	return routes, nil
 }
 
+// GetCheapestFares returns the cheapest indexed fare from an origin to each destination
+// departing within [from, to]
+func (c *Client) GetCheapestFares(origin string, destinations []string, from, to time.Time) (map[string]CheapestFare, error) {
+	fares := make(map[string]CheapestFare)
+	if len(destinations) == 0 {
+		return fares, nil
+	}
+
+	values := make([]interface{}, len(destinations))
+	for i, destination := range destinations {
+		values[i] = destination
+	}
+
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("origin_airport", origin)).
+		Filter(elastic.NewTermsQuery("destination_airport", values...)).
+		Filter(elastic.NewRangeQuery("departure_time").
+			Gte(from).
+			Lte(to))
+	if indexFilter := c.flightIndexFilter(from, to); indexFilter != nil {
+		query = query.MustNot(indexFilter)
+	}
+
+	cheapestAgg := elastic.NewTermsAggregation().
+		Field("destination_airport").
+		Size(len(destinations)).
+		SubAggregation("cheapest", elastic.NewTopHitsAggregation().
+			Sort("price", true).
+			Size(1).
+			FetchSourceContext(elastic.NewFetchSourceContext(true).Include("price", "currency", "departure_time")))
+
+	searchResult, err := c.client.Search().
+		Index(c.getFlightIndex()).
+		Query(query).
+		Aggregation("destinations", cheapestAgg).
+		Size(0).
+		Do(context.Background())
+
+	if err != nil {
+		return nil, fmt.Errorf("cheapest fares aggregation failed: %w", err)
+	}
+
+	if destinationsAgg, found := searchResult.Aggregations.Terms("destinations"); found {
+		for _, bucket := range destinationsAgg.Buckets {
+			destination, ok := bucket.Key.(string)
+			if !ok {
+				continue
+			}
+			topHits, found := bucket.TopHits("cheapest")
+			if !found || topHits.Hits == nil || len(topHits.Hits.Hits) == 0 {
+				continue
+			}
+
+			var fare CheapestFare
+			if err := json.Unmarshal(topHits.Hits.Hits[0].Source, &fare); err != nil {
+				continue
+			}
+			fare.Destination = destination
+			fares[destination] = fare
+		}
+	}
+
+	return fares, nil
+}
+
 // GetPriceInsights returns price analysis for a specific route
 func (c *Client) GetPriceInsights(origin, destination string, days int) (map[string]interface{}, error) {
 	if days <= 0 {
@@ -238,6 +311,9 @@ func (c *Client) GetPriceInsights(origin, destination string, days int) (map[str
 		Filter(elastic.NewRangeQuery("departure_time").
 			Gte(startDate).
 			Lte(endDate))
+	if indexFilter := c.flightIndexFilter(startDate, endDate); indexFilter != nil {
+		query = query.MustNot(indexFilter)
+	}
 
 	// Price statistics aggregations
 	searchResult, err := c.client.Search().
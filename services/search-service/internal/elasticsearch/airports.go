@@ -0,0 +1,115 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/olivere/elastic/v7"
+	"spontra/search-service/internal/models"
+)
+
+// ResolveAirports returns the airports an IATA code refers to: the airport itself,
+// or every member airport when the code is a city code such as "LON"
+func (c *Client) ResolveAirports(code string) ([]models.AirportSuggestion, error) {
+	query := elastic.NewBoolQuery().
+		Should(elastic.NewTermQuery("code.exact", code)).
+		Should(elastic.NewTermQuery("city_code", code)).
+		MinimumShouldMatch("1")
+
+	airports, err := c.searchAirportDocuments(query, nil, 20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve airport code %s: %w", code, err)
+	}
+	return airports, nil
+}
+
+// NearbyAirports returns the airports within radiusKm of a point, nearest first
+func (c *Client) NearbyAirports(point models.GeoPoint, radiusKm float64, limit int) ([]models.AirportSuggestion, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewGeoDistanceQuery("coordinates").
+			Lat(point.Lat).
+			Lon(point.Lon).
+			Distance(fmt.Sprintf("%.1fkm", radiusKm)),
+	)
+	sorter := elastic.NewGeoDistanceSort("coordinates").
+		Point(point.Lat, point.Lon).
+		Unit("km").
+		Asc()
+
+	airports, err := c.searchAirportDocuments(query, sorter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find nearby airports: %w", err)
+	}
+	return airports, nil
+}
+
+// GetAirports returns the airports with the given IATA codes
+func (c *Client) GetAirports(codes []string) ([]models.AirportSuggestion, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	query := elastic.NewTermsQuery("code.exact", stringValues(codes)...)
+	airports, err := c.searchAirportDocuments(query, nil, len(codes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get airports: %w", err)
+	}
+	return airports, nil
+}
+
+// AirportsInCountries returns every airport in the given ISO country codes
+func (c *Client) AirportsInCountries(countryCodes []string, limit int) ([]models.AirportSuggestion, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	query := elastic.NewBoolQuery().Filter(elastic.NewTermsQuery("country_code", stringValues(countryCodes)...))
+	airports, err := c.searchAirportDocuments(query, nil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list airports by country: %w", err)
+	}
+	return airports, nil
+}
+
+// stringValues converts strings for use as terms query values
+func stringValues(values []string) []interface{} {
+	converted := make([]interface{}, len(values))
+	for i, value := range values {
+		converted[i] = value
+	}
+	return converted
+}
+
+// searchAirportDocuments runs a query against the airport index
+func (c *Client) searchAirportDocuments(query elastic.Query, sorter elastic.Sorter, size int) ([]models.AirportSuggestion, error) {
+	search := c.client.Search().
+		Index(c.getAirportIndex()).
+		Query(query).
+		Size(size)
+	if sorter != nil {
+		search = search.SortBy(sorter)
+	}
+
+	searchResult, err := search.Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	airports := make([]models.AirportSuggestion, 0, len(searchResult.Hits.Hits))
+	for _, hit := range searchResult.Hits.Hits {
+		var airport models.AirportSuggestion
+		if err := json.Unmarshal(hit.Source, &airport); err != nil {
+			log.Printf("Failed to unmarshal airport: %v", err)
+			continue
+		}
+		airports = append(airports, airport)
+	}
+
+	return airports, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/olivere/elastic/v7"
@@ -16,6 +17,9 @@ import (
 type Client struct {
 	client *elastic.Client
 	cfg    *config.Config
+
+	rangesMu     sync.RWMutex
+	flightRanges []FlightIndexRange // refreshed by RunFlightIndexLifecycle, nil until the first run
 }
 
 // NewClient creates a new Elasticsearch client
@@ -47,40 +51,45 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to create indices: %w", err)
 	}
 
+	// Mapping drift is reported rather than fatal; cmd/reindex resolves it
+	drift, err := esClient.CheckMappingDrift()
+	if err != nil {
+		log.Printf("Failed to check mapping drift: %v", err)
+	}
+	for _, field := range drift {
+		log.Printf("Mapping drift in %s: field %s expected %q, found %q", field.Index, field.Field, field.Expected, field.Actual)
+	}
+
 	return esClient, nil
 }
 
-// createIndices creates the necessary Elasticsearch indices
+// createIndices creates the versioned indices and their aliases where missing
 func (c *Client) createIndices() error {
-	indices := map[string]string{
-		c.getFlightIndex():  flightIndexMapping,
-		c.getAirportIndex(): airportIndexMapping,
+	for _, def := range c.IndexDefinitions() {
+		if err := c.ensureIndex(def); err != nil {
+			return fmt.Errorf("failed to set up index %s: %w", def.Name, err)
+		}
 	}
 
-	for indexName, mapping := range indices {
-		exists, err := c.client.IndexExists(indexName).Do(context.Background())
-		if err != nil {
-			return fmt.Errorf("failed to check if index exists: %w", err)
-		}
+	return nil
+}
 
-		if !exists {
-			createIndex, err := c.client.CreateIndex(indexName).
-				Body(mapping).
-				Do(context.Background())
-			if err != nil {
-				return fmt.Errorf("failed to create index %s: %w", indexName, err)
-			}
-			log.Printf("Created Elasticsearch index: %s (acknowledged: %t)", indexName, createIndex.Acknowledged)
-		}
+// Health checks that the cluster is reachable and not red
+func (c *Client) Health(ctx context.Context) error {
+	health, err := c.client.ClusterHealth().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster health: %w", err)
+	}
+	if health.Status == "red" {
+		return fmt.Errorf("cluster %s is red", health.ClusterName)
 	}
-
 	return nil
 }
 
 // IndexFlight indexes a flight document
 func (c *Client) IndexFlight(flight *models.Flight) error {
 	_, err := c.client.Index().
-		Index(c.getFlightIndex()).
+		Index(c.getFlightWriteIndex()).
 		Id(flight.ID.String()).
 		BodyJson(flight).
 		Do(context.Background())
@@ -95,7 +104,7 @@ func (c *Client) IndexFlight(flight *models.Flight) error {
 // IndexAirport indexes an airport document
 func (c *Client) IndexAirport(airport *models.AirportSuggestion) error {
 	_, err := c.client.Index().
-		Index(c.getAirportIndex()).
+		Index(c.getAirportWriteIndex()).
 		Id(airport.Code).
 		BodyJson(airport).
 		Do(context.Background())
@@ -209,19 +218,25 @@ func (c *Client) buildFlightSearchQuery(req *models.FlightSearchRequest) elastic
 	boolQuery = boolQuery.Filter(elastic.NewTermQuery("destination_airport", req.DestinationAirport))
 
 	// Date range filter
+	var startDate, endDate time.Time
 	if req.FlexibleDates && req.FlexibleDatesRange > 0 {
-		startDate := req.DepartureDate.AddDate(0, 0, -req.FlexibleDatesRange)
-		endDate := req.DepartureDate.AddDate(0, 0, req.FlexibleDatesRange)
+		startDate = req.DepartureDate.AddDate(0, 0, -req.FlexibleDatesRange)
+		endDate = req.DepartureDate.AddDate(0, 0, req.FlexibleDatesRange)
 		boolQuery = boolQuery.Filter(elastic.NewRangeQuery("departure_time").
 			Gte(startDate).
 			Lte(endDate))
 	} else {
 		// Exact date (with some tolerance for time)
-		startOfDay := time.Date(req.DepartureDate.Year(), req.DepartureDate.Month(), req.DepartureDate.Day(), 0, 0, 0, 0, req.DepartureDate.Location())
-		endOfDay := startOfDay.Add(24 * time.Hour)
+		startDate = time.Date(req.DepartureDate.Year(), req.DepartureDate.Month(), req.DepartureDate.Day(), 0, 0, 0, 0, req.DepartureDate.Location())
+		endDate = startDate.Add(24 * time.Hour)
 		boolQuery = boolQuery.Filter(elastic.NewRangeQuery("departure_time").
-			Gte(startOfDay).
-			Lt(endOfDay))
+			Gte(startDate).
+			Lt(endDate))
+	}
+
+	// Skip rolled-over indices without departures in the range
+	if indexFilter := c.flightIndexFilter(startDate, endDate); indexFilter != nil {
+		boolQuery = boolQuery.MustNot(indexFilter)
 	}
 
 	// Cabin class filter
@@ -276,7 +291,7 @@ func (c *Client) getSortField(sortBy string) string {
 	}
 }
 
-// Helper functions
+// Helper functions. Index names are the read aliases; writes go through the write aliases.
 func (c *Client) getFlightIndex() string {
 	return fmt.Sprintf("%s_%s", c.cfg.ESIndexPrefix, c.cfg.ESFlightIndex)
 }
@@ -285,6 +300,22 @@ func (c *Client) getAirportIndex() string {
 	return fmt.Sprintf("%s_%s", c.cfg.ESIndexPrefix, c.cfg.ESAirportIndex)
 }
 
+func (c *Client) getSuggestionIndex() string {
+	return fmt.Sprintf("%s_%s", c.cfg.ESIndexPrefix, c.cfg.ESSuggestionIndex)
+}
+
+func (c *Client) getFlightWriteIndex() string {
+	return c.getFlightIndex() + "_write"
+}
+
+func (c *Client) getAirportWriteIndex() string {
+	return c.getAirportIndex() + "_write"
+}
+
+func (c *Client) getSuggestionWriteIndex() string {
+	return c.getSuggestionIndex() + "_write"
+}
+
 func stringSliceToInterface(slice []string) []interface{} {
 	result := make([]interface{}, len(slice))
 	for i, v := range slice {
@@ -416,6 +447,7 @@ const airportIndexMapping = `{
 			"country_code": {"type": "keyword"},
 			"type": {"type": "keyword"},
 			"popularity": {"type": "float"},
+			"city_code": {"type": "keyword"},
 			"coordinates": {"type": "geo_point"}
 		}
 	}
@@ -0,0 +1,389 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// Mapping versions of the indices. Bump a version whenever its mapping changes; startup then
+// reports the index as outdated until cmd/reindex builds the new version and swaps the aliases.
+const (
+	FlightIndexVersion     = 1
+	AirportIndexVersion    = 1
+	SuggestionIndexVersion = 1
+)
+
+// IndexDefinition describes a logical index. Queries read through Alias, writes go through
+// WriteAlias, and both point at a versioned physical index named <alias>_v<version>_<timestamp>.
+type IndexDefinition struct {
+	Name       string
+	Alias      string
+	WriteAlias string
+	Version    int
+	Mapping    string
+}
+
+// IndexState describes the physical indices currently behind an index's aliases
+type IndexState struct {
+	Definition IndexDefinition `json:"-"`
+	Indices    []string        `json:"indices"`
+	WriteIndex string          `json:"write_index,omitempty"`
+	Version    int             `json:"version"` // 0 for a legacy unversioned index
+	Legacy     bool            `json:"legacy"`  // the alias name is a concrete index created before versioning
+
+	mappings map[string]map[string]interface{}
+}
+
+// Outdated reports whether the live index predates the mapping version in code
+func (s *IndexState) Outdated() bool {
+	return len(s.Indices) > 0 && s.Version < s.Definition.Version
+}
+
+// MappingDrift is a field whose live mapping differs from the mapping in code
+type MappingDrift struct {
+	Index    string `json:"index"`
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"` // empty when the field is missing
+}
+
+// IndexDefinitions returns the definitions of every index managed by the service
+func (c *Client) IndexDefinitions() []IndexDefinition {
+	return []IndexDefinition{
+		{
+			Name:       c.cfg.ESFlightIndex,
+			Alias:      c.getFlightIndex(),
+			WriteAlias: c.getFlightWriteIndex(),
+			Version:    FlightIndexVersion,
+			Mapping:    flightIndexMapping,
+		},
+		{
+			Name:       c.cfg.ESAirportIndex,
+			Alias:      c.getAirportIndex(),
+			WriteAlias: c.getAirportWriteIndex(),
+			Version:    AirportIndexVersion,
+			Mapping:    airportIndexMapping,
+		},
+		{
+			Name:       c.cfg.ESSuggestionIndex,
+			Alias:      c.getSuggestionIndex(),
+			WriteAlias: c.getSuggestionWriteIndex(),
+			Version:    SuggestionIndexVersion,
+			Mapping:    suggestionIndexMapping,
+		},
+	}
+}
+
+// IndexDefinition returns the definition of a logical index by name
+func (c *Client) IndexDefinition(name string) (IndexDefinition, error) {
+	for _, def := range c.IndexDefinitions() {
+		if def.Name == name {
+			return def, nil
+		}
+	}
+	return IndexDefinition{}, fmt.Errorf("unknown index %q", name)
+}
+
+// IndexState resolves the read alias of an index to its physical indices
+func (c *Client) IndexState(def IndexDefinition) (*IndexState, error) {
+	state := &IndexState{
+		Definition: def,
+		mappings:   make(map[string]map[string]interface{}),
+	}
+
+	exists, err := c.client.IndexExists(def.Alias).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if index exists: %w", err)
+	}
+	if !exists {
+		return state, nil
+	}
+
+	indices, err := c.client.IndexGet(def.Alias).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve index %s: %w", def.Alias, err)
+	}
+
+	for index, info := range indices {
+		state.Indices = append(state.Indices, index)
+		state.mappings[index] = info.Mappings
+
+		if index == def.Alias {
+			state.Legacy = true
+		} else if version := parseIndexVersion(def.Alias, index); version > state.Version {
+			state.Version = version
+		}
+		if _, ok := info.Aliases[def.WriteAlias]; ok {
+			state.WriteIndex = index
+		}
+	}
+	sort.Strings(state.Indices)
+
+	return state, nil
+}
+
+// CreateVersionedIndex creates a new physical index for the definition's current version,
+// without aliases
+func (c *Client) CreateVersionedIndex(def IndexDefinition) (string, error) {
+	index := versionedIndexName(def)
+
+	_, err := c.client.CreateIndex(index).Body(def.Mapping).Do(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to create index %s: %w", index, err)
+	}
+
+	log.Printf("Created Elasticsearch index: %s", index)
+	return index, nil
+}
+
+// ReindexFromAlias copies every document behind an index's read alias into the target index
+func (c *Client) ReindexFromAlias(def IndexDefinition, target string) (int64, error) {
+	response, err := c.client.Reindex().
+		SourceIndex(def.Alias).
+		DestinationIndex(target).
+		WaitForCompletion(true).
+		Refresh("true").
+		Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to reindex %s into %s: %w", def.Alias, target, err)
+	}
+	if len(response.Failures) > 0 {
+		return response.Created, fmt.Errorf("reindex of %s into %s had %d failures", def.Alias, target, len(response.Failures))
+	}
+
+	return response.Created, nil
+}
+
+// SetWriteBlock sets or clears index.blocks.write on physical indices. Blocking the indices
+// behind an alias while they are copied makes writers fail and retry instead of writing
+// documents the copy has already passed, which the alias swap would then lose.
+func (c *Client) SetWriteBlock(indices []string, blocked bool) error {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	_, err := c.client.IndexPutSettings(indices...).
+		BodyJson(map[string]interface{}{"index.blocks.write": blocked}).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to set write block on %s: %w", strings.Join(indices, ", "), err)
+	}
+
+	return nil
+}
+
+// SwapAliases atomically points the read and write aliases at the target index. A legacy
+// unversioned index is removed in the same request, since its name is taken by the alias,
+// after cloning it to <alias>_legacy_<timestamp>; cloning requires its writes to be blocked.
+// The previously aliased versioned indices and the legacy copy are returned and left in place
+// for rollback. The write alias is added without is_write_index, so a rollover moves it to the
+// new index.
+func (c *Client) SwapAliases(def IndexDefinition, target string) ([]string, error) {
+	state, err := c.IndexState(def)
+	if err != nil {
+		return nil, err
+	}
+
+	var legacyCopy string
+	if state.Legacy {
+		legacyCopy, err = c.cloneLegacyIndex(def)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var actions []elastic.AliasAction
+	var previous []string
+	for _, index := range state.Indices {
+		if index == target {
+			continue
+		}
+		if index == def.Alias {
+			actions = append(actions, elastic.NewAliasRemoveIndexAction(index))
+			previous = append(previous, legacyCopy)
+			continue
+		}
+		actions = append(actions, elastic.NewAliasRemoveAction(def.Alias).Index(index))
+		if index == state.WriteIndex {
+			actions = append(actions, elastic.NewAliasRemoveAction(def.WriteAlias).Index(index))
+		}
+		previous = append(previous, index)
+	}
+	actions = append(actions,
+		elastic.NewAliasAddAction(def.Alias).Index(target),
+		elastic.NewAliasAddAction(def.WriteAlias).Index(target),
+	)
+
+	if _, err := c.client.Alias().Action(actions...).Do(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to swap aliases of %s to %s: %w", def.Alias, target, err)
+	}
+
+	log.Printf("Aliases %s and %s now point at %s", def.Alias, def.WriteAlias, target)
+	return previous, nil
+}
+
+// cloneLegacyIndex copies a legacy unversioned index to a name that does not clash with its
+// alias, so it survives the swap that removes it. The client has no clone service, so the
+// request is sent directly.
+func (c *Client) cloneLegacyIndex(def IndexDefinition) (string, error) {
+	index := fmt.Sprintf("%s_legacy_%s", def.Alias, time.Now().UTC().Format("20060102150405"))
+
+	_, err := c.client.PerformRequest(context.Background(), elastic.PerformRequestOptions{
+		Method: "PUT",
+		Path:   fmt.Sprintf("/%s/_clone/%s", def.Alias, index),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to clone legacy index %s to %s: %w", def.Alias, index, err)
+	}
+
+	log.Printf("Cloned legacy index %s to %s", def.Alias, index)
+	return index, nil
+}
+
+// DeleteIndices deletes physical indices, e.g. versions replaced by SwapAliases
+func (c *Client) DeleteIndices(indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	if _, err := c.client.DeleteIndex(indices...).Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to delete indices %s: %w", strings.Join(indices, ", "), err)
+	}
+	return nil
+}
+
+// CheckMappingDrift compares the live mapping of every aliased index with the mapping in code.
+// Fields added by dynamic mapping are ignored; only expected fields that are missing or have
+// a different type are reported.
+func (c *Client) CheckMappingDrift() ([]MappingDrift, error) {
+	var drift []MappingDrift
+	for _, def := range c.IndexDefinitions() {
+		state, err := c.IndexState(def)
+		if err != nil {
+			return nil, err
+		}
+
+		var mapping struct {
+			Mappings map[string]interface{} `json:"mappings"`
+		}
+		if err := json.Unmarshal([]byte(def.Mapping), &mapping); err != nil {
+			return nil, fmt.Errorf("failed to parse mapping of %s: %w", def.Name, err)
+		}
+
+		for _, index := range state.Indices {
+			drift = append(drift, compareProperties(index, "", properties(mapping.Mappings), properties(state.mappings[index]))...)
+		}
+	}
+
+	return drift, nil
+}
+
+// ensureIndex creates a missing index behind its aliases and reports outdated or drifted ones.
+// A legacy unversioned index is kept serving and given a write alias until it is reindexed.
+func (c *Client) ensureIndex(def IndexDefinition) error {
+	state, err := c.IndexState(def)
+	if err != nil {
+		return err
+	}
+
+	if len(state.Indices) == 0 {
+		index, err := c.CreateVersionedIndex(def)
+		if err != nil {
+			return err
+		}
+		_, err = c.SwapAliases(def, index)
+		return err
+	}
+
+	if state.WriteIndex == "" && len(state.Indices) == 1 {
+		_, err := c.client.Alias().Add(state.Indices[0], def.WriteAlias).Do(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to add write alias %s: %w", def.WriteAlias, err)
+		}
+	}
+
+	switch {
+	case state.Legacy:
+		log.Printf("Index %s is unversioned; run cmd/reindex -index=%s to move it behind aliases", def.Alias, def.Name)
+	case state.Outdated():
+		log.Printf("Index %s is at mapping version %d, code expects %d; run cmd/reindex -index=%s", def.Alias, state.Version, def.Version, def.Name)
+	}
+
+	return nil
+}
+
+// versionedIndexName names a new physical index for the definition's current version
+func versionedIndexName(def IndexDefinition) string {
+	return fmt.Sprintf("%s_v%d_%s", def.Alias, def.Version, time.Now().UTC().Format("20060102150405"))
+}
+
+// parseIndexVersion extracts the version of a physical index named <alias>_v<version>_<timestamp>
+func parseIndexVersion(alias, index string) int {
+	suffix := strings.TrimPrefix(index, alias+"_v")
+	if suffix == index {
+		return 0
+	}
+	if end := strings.Index(suffix, "_"); end != -1 {
+		suffix = suffix[:end]
+	}
+	version, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// properties returns the "properties" object of a mapping
+func properties(mapping map[string]interface{}) map[string]interface{} {
+	props, _ := mapping["properties"].(map[string]interface{})
+	return props
+}
+
+// compareProperties reports expected fields that are missing from, or typed differently in,
+// the live mapping, descending into object fields
+func compareProperties(index, prefix string, expected, actual map[string]interface{}) []MappingDrift {
+	var drift []MappingDrift
+	for field, expectedSpec := range expected {
+		path := prefix + field
+		expectedField, _ := expectedSpec.(map[string]interface{})
+		actualField, ok := actual[field].(map[string]interface{})
+		if !ok {
+			drift = append(drift, MappingDrift{Index: index, Field: path, Expected: fieldType(expectedField)})
+			continue
+		}
+
+		if expectedType, actualType := fieldType(expectedField), fieldType(actualField); expectedType != actualType {
+			drift = append(drift, MappingDrift{Index: index, Field: path, Expected: expectedType, Actual: actualType})
+			continue
+		}
+
+		if nested := properties(expectedField); nested != nil {
+			drift = append(drift, compareProperties(index, path+".", nested, properties(actualField))...)
+		}
+		if fields, ok := expectedField["fields"].(map[string]interface{}); ok {
+			actualFields, _ := actualField["fields"].(map[string]interface{})
+			drift = append(drift, compareProperties(index, path+".", fields, actualFields)...)
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Field < drift[j].Field })
+	return drift
+}
+
+// fieldType returns a field's mapping type; fields with properties and no type are objects
+func fieldType(field map[string]interface{}) string {
+	if fieldType, ok := field["type"].(string); ok {
+		return fieldType
+	}
+	if _, ok := field["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
@@ -0,0 +1,341 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// FlightIndexRange is the span of departures and offer validity held by one physical flight index
+type FlightIndexRange struct {
+	Index          string    `json:"index"`
+	WriteIndex     bool      `json:"write_index"`
+	Documents      int64     `json:"documents"`
+	FirstDeparture time.Time `json:"first_departure"`
+	LastDeparture  time.Time `json:"last_departure"`
+	LastValidUntil time.Time `json:"last_valid_until"` // zero when no offer has a valid_until
+}
+
+// expired reports whether every offer in the index stopped being valid before the cutoff.
+// Empty indices that are no longer written to are expired as well.
+func (r FlightIndexRange) expired(cutoff time.Time) bool {
+	if r.WriteIndex {
+		return false
+	}
+	if r.Documents == 0 {
+		return true
+	}
+	return !r.LastValidUntil.IsZero() && r.LastValidUntil.Before(cutoff)
+}
+
+// overlaps reports whether the index may hold departures within [from, to]
+func (r FlightIndexRange) overlaps(from, to time.Time) bool {
+	return r.Documents > 0 && !r.FirstDeparture.After(to) && !r.LastDeparture.Before(from)
+}
+
+// staleCopyBatch caps the re-indexed flights whose stale copies one lifecycle run deletes; the
+// rest are left for the next run
+const staleCopyBatch = 10000
+
+// LifecycleReport summarises one run of the flight index lifecycle
+type LifecycleReport struct {
+	RolledOver  string             `json:"rolled_over,omitempty"` // the new write index
+	Deleted     []string           `json:"deleted,omitempty"`
+	StaleCopies int64              `json:"stale_copies,omitempty"` // older copies of re-indexed flights deleted
+	Indices     []FlightIndexRange `json:"indices"`
+}
+
+// RunFlightIndexLifecycle rolls the flight index over when due, deletes indices whose offers
+// have all expired, deletes stale copies of re-indexed flights, and refreshes the departure
+// ranges used to target searches
+func (c *Client) RunFlightIndexLifecycle() (*LifecycleReport, error) {
+	def, err := c.IndexDefinition(c.cfg.ESFlightIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LifecycleReport{}
+	if report.RolledOver, err = c.RolloverFlightIndex(); err != nil {
+		return nil, err
+	}
+
+	ranges, err := c.FlightIndexRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-c.cfg.FlightRetentionGrace)
+	for _, r := range ranges {
+		// A legacy index is named after the alias and has to be reindexed before it can go
+		if r.Index != def.Alias && r.expired(cutoff) {
+			report.Deleted = append(report.Deleted, r.Index)
+		} else {
+			report.Indices = append(report.Indices, r)
+		}
+	}
+
+	if err := c.DeleteIndices(report.Deleted); err != nil {
+		return nil, err
+	}
+	if len(report.Deleted) > 0 {
+		log.Printf("Deleted expired flight indices: %v", report.Deleted)
+	}
+
+	c.rangesMu.Lock()
+	c.flightRanges = report.Indices
+	c.rangesMu.Unlock()
+
+	// Cleanup is retried on the next run, so a failure does not fail the lifecycle
+	if report.StaleCopies, err = c.deleteStaleFlightCopies(def.Alias, report.Indices); err != nil {
+		log.Printf("Failed to delete stale flight copies: %v", err)
+	} else if report.StaleCopies > 0 {
+		log.Printf("Deleted %d stale copies of re-indexed flights", report.StaleCopies)
+	}
+
+	return report, nil
+}
+
+// RolloverFlightIndex moves the flight write alias to a new index once the current write index
+// reaches the configured age, document count or size. It returns the new index, or "" when no
+// condition was met. Legacy and outdated indices are left for cmd/reindex.
+func (c *Client) RolloverFlightIndex() (string, error) {
+	def, err := c.IndexDefinition(c.cfg.ESFlightIndex)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := c.IndexState(def)
+	if err != nil {
+		return "", err
+	}
+	if state.WriteIndex == "" || state.Legacy || state.Outdated() {
+		return "", nil
+	}
+
+	conditions := make(map[string]interface{})
+	if c.cfg.FlightRolloverMaxAge > 0 {
+		conditions["max_age"] = fmt.Sprintf("%dm", int(c.cfg.FlightRolloverMaxAge.Minutes()))
+	}
+	if c.cfg.FlightRolloverMaxDocs > 0 {
+		conditions["max_docs"] = c.cfg.FlightRolloverMaxDocs
+	}
+	if c.cfg.FlightRolloverMaxSizeGB > 0 {
+		conditions["max_size"] = fmt.Sprintf("%dgb", c.cfg.FlightRolloverMaxSizeGB)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	// The new index gets the current mapping and joins the read alias; the write alias moves
+	body := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(def.Mapping), &body); err != nil {
+		return "", fmt.Errorf("failed to parse mapping of %s: %w", def.Name, err)
+	}
+	body["conditions"] = conditions
+	body["aliases"] = map[string]interface{}{def.Alias: map[string]interface{}{}}
+
+	response, err := c.client.RolloverIndex(def.WriteAlias).
+		NewIndex(versionedIndexName(def)).
+		BodyJson(body).
+		Do(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to roll over %s: %w", def.WriteAlias, err)
+	}
+	if !response.RolledOver {
+		return "", nil
+	}
+
+	log.Printf("Rolled %s over from %s to %s", def.WriteAlias, response.OldIndex, response.NewIndex)
+	return response.NewIndex, nil
+}
+
+// FlightIndexRanges returns the departure and validity range of every index behind the
+// flight read alias
+func (c *Client) FlightIndexRanges() ([]FlightIndexRange, error) {
+	def, err := c.IndexDefinition(c.cfg.ESFlightIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := c.IndexState(def)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Indices) == 0 {
+		return nil, nil
+	}
+
+	indexAgg := elastic.NewTermsAggregation().
+		Field("_index").
+		Size(len(state.Indices)).
+		SubAggregation("first_departure", elastic.NewMinAggregation().Field("departure_time")).
+		SubAggregation("last_departure", elastic.NewMaxAggregation().Field("departure_time")).
+		SubAggregation("last_valid_until", elastic.NewMaxAggregation().Field("valid_until"))
+
+	searchResult, err := c.client.Search().
+		Index(def.Alias).
+		Aggregation("indices", indexAgg).
+		Size(0).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate flight index ranges: %w", err)
+	}
+
+	ranges := make(map[string]*FlightIndexRange, len(state.Indices))
+	for _, index := range state.Indices {
+		ranges[index] = &FlightIndexRange{Index: index, WriteIndex: index == state.WriteIndex}
+	}
+
+	if indexAgg, found := searchResult.Aggregations.Terms("indices"); found {
+		for _, bucket := range indexAgg.Buckets {
+			index, _ := bucket.Key.(string)
+			r, ok := ranges[index]
+			if !ok {
+				continue
+			}
+			r.Documents = bucket.DocCount
+			r.FirstDeparture = metricTime(bucket.Min("first_departure"))
+			r.LastDeparture = metricTime(bucket.Max("last_departure"))
+			r.LastValidUntil = metricTime(bucket.Max("last_valid_until"))
+		}
+	}
+
+	result := make([]FlightIndexRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+
+	return result, nil
+}
+
+// flightIndexFilter matches the rolled-over flight indices that hold no departures within
+// [from, to], for searches to exclude. Searches keep reading through the alias, so indices
+// created or dropped since the last lifecycle run are handled correctly, and Elasticsearch
+// skips the excluded shards. Returns nil before the first run or when nothing can be excluded.
+func (c *Client) flightIndexFilter(from, to time.Time) elastic.Query {
+	c.rangesMu.RLock()
+	defer c.rangesMu.RUnlock()
+
+	var excluded []interface{}
+	for _, r := range c.flightRanges {
+		// The write index may have received departures outside its range since the last run
+		if !r.WriteIndex && !r.overlaps(from, to) {
+			excluded = append(excluded, r.Index)
+		}
+	}
+	if len(excluded) == 0 {
+		return nil
+	}
+
+	return elastic.NewTermsQuery("_index", excluded...)
+}
+
+// deleteStaleFlightCopies deletes the older copies of flights held by more than one index
+// behind the read alias. Once the write alias has rolled over, indexing a flight again by the
+// same ID adds a copy to the new write index while the earlier copy stays in the old one, and
+// searches through the alias would return both. Running here keeps deletes off the write path:
+// duplicates are found with one aggregation and removed with one delete-by-query per index.
+func (c *Client) deleteStaleFlightCopies(alias string, ranges []FlightIndexRange) (int64, error) {
+	var populated []FlightIndexRange
+	for _, r := range ranges {
+		if r.Documents > 0 {
+			populated = append(populated, r)
+		}
+	}
+	if len(populated) < 2 {
+		return 0, nil
+	}
+
+	duplicates := elastic.NewTermsAggregation().
+		Field("id").
+		MinDocCount(2).
+		Size(staleCopyBatch).
+		SubAggregation("indices", elastic.NewTermsAggregation().Field("_index").Size(len(populated)))
+
+	searchResult, err := c.client.Search().
+		Index(alias).
+		Aggregation("duplicates", duplicates).
+		Size(0).
+		Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to find re-indexed flights: %w", err)
+	}
+
+	// Rank indices newest first
+	sort.Slice(populated, func(i, j int) bool { return newerFlightIndex(alias, populated[i], populated[j]) })
+	rank := make(map[string]int, len(populated))
+	for i, r := range populated {
+		rank[r.Index] = i
+	}
+
+	// Keep the copy in the newest index and collect the others by the index holding them
+	stale := make(map[string][]string)
+	if agg, found := searchResult.Aggregations.Terms("duplicates"); found {
+		for _, bucket := range agg.Buckets {
+			id, _ := bucket.Key.(string)
+			indices, found := bucket.Terms("indices")
+			if id == "" || !found {
+				continue
+			}
+
+			// Skip flights in indices created since the ranges were taken; the next run has them
+			var held []string
+			for _, indexBucket := range indices.Buckets {
+				index, _ := indexBucket.Key.(string)
+				if _, ranked := rank[index]; !ranked {
+					held = nil
+					break
+				}
+				held = append(held, index)
+			}
+			if len(held) < 2 {
+				continue
+			}
+
+			sort.Slice(held, func(i, j int) bool { return rank[held[i]] < rank[held[j]] })
+			for _, index := range held[1:] {
+				stale[index] = append(stale[index], id)
+			}
+		}
+	}
+
+	var deleted int64
+	for index, ids := range stale {
+		response, err := c.client.DeleteByQuery(index).
+			Query(elastic.NewIdsQuery().Ids(ids...)).
+			ProceedOnVersionConflict().
+			Do(context.Background())
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete stale copies of %d flights from %s: %w", len(ids), index, err)
+		}
+		deleted += response.Deleted
+	}
+
+	return deleted, nil
+}
+
+// newerFlightIndex reports whether index a was created after index b. The write index is the
+// newest; others are ordered by mapping version and then by the creation time in their name.
+func newerFlightIndex(alias string, a, b FlightIndexRange) bool {
+	if a.WriteIndex != b.WriteIndex {
+		return a.WriteIndex
+	}
+	if va, vb := parseIndexVersion(alias, a.Index), parseIndexVersion(alias, b.Index); va != vb {
+		return va > vb
+	}
+	return a.Index > b.Index
+}
+
+// metricTime converts a date min/max aggregation to a time, zero when the field was missing
+func metricTime(metric *elastic.AggregationValueMetric, found bool) time.Time {
+	if !found || metric.Value == nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(*metric.Value)).UTC()
+}
@@ -0,0 +1,215 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+func TestFlightIndexFilter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 11, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		ranges []FlightIndexRange
+		want   []string // excluded indices, nil for no filter
+	}{
+		{
+			name: "indices outside the range",
+			ranges: []FlightIndexRange{
+				{Index: "flights_v2_a", Documents: 10, FirstDeparture: day(1), LastDeparture: day(5)},
+				{Index: "flights_v2_b", Documents: 10, FirstDeparture: day(6), LastDeparture: day(12)},
+				{Index: "flights_v2_c", Documents: 10, FirstDeparture: day(20), LastDeparture: day(25)},
+				{Index: "flights_v2_d", WriteIndex: true, Documents: 10, FirstDeparture: day(26), LastDeparture: day(30)},
+			},
+			want: []string{"flights_v2_a", "flights_v2_c"},
+		},
+		{
+			name: "empty index",
+			ranges: []FlightIndexRange{
+				{Index: "flights_v2_a"},
+				{Index: "flights_v2_b", WriteIndex: true},
+			},
+			want: []string{"flights_v2_a"},
+		},
+		{
+			name: "every index overlaps",
+			ranges: []FlightIndexRange{
+				{Index: "flights_v2_a", Documents: 10, FirstDeparture: day(1), LastDeparture: day(10)},
+				{Index: "flights_v2_b", WriteIndex: true},
+			},
+		},
+		{name: "before the first lifecycle run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{flightRanges: tt.ranges}
+
+			query := c.flightIndexFilter(day(8), day(15))
+			if tt.want == nil {
+				if query != nil {
+					t.Errorf("flightIndexFilter() = %v, want no filter", query)
+				}
+				return
+			}
+			if query == nil {
+				t.Fatalf("flightIndexFilter() = nil, want %v excluded", tt.want)
+			}
+
+			source, err := query.Source()
+			if err != nil {
+				t.Fatalf("query source error = %v", err)
+			}
+			want := map[string]interface{}{"terms": map[string]interface{}{"_index": stringsToInterfaces(tt.want)}}
+			if !reflect.DeepEqual(source, want) {
+				t.Errorf("flightIndexFilter() = %v, want %v", source, want)
+			}
+		})
+	}
+}
+
+func TestNewerFlightIndex(t *testing.T) {
+	tests := []struct {
+		a, b FlightIndexRange
+		want bool
+	}{
+		{FlightIndexRange{Index: "flights_v1_20260101000000", WriteIndex: true}, FlightIndexRange{Index: "flights_v2_20261101000000"}, true},
+		{FlightIndexRange{Index: "flights_v2_20260101000000"}, FlightIndexRange{Index: "flights_v1_20261101000000"}, true},
+		{FlightIndexRange{Index: "flights_v2_20261101000000"}, FlightIndexRange{Index: "flights_v2_20260101000000"}, true},
+		{FlightIndexRange{Index: "flights_legacy_20261101000000"}, FlightIndexRange{Index: "flights_v1_20260101000000"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := newerFlightIndex("flights", tt.a, tt.b); got != tt.want {
+			t.Errorf("newerFlightIndex(%s, %s) = %v, want %v", tt.a.Index, tt.b.Index, got, tt.want)
+		}
+	}
+}
+
+// fakeDuplicateCluster answers the duplicate aggregation with fixed buckets and records the ids
+// each delete-by-query removes, by index
+type fakeDuplicateCluster struct {
+	buckets map[string][]string // flight ID to the indices holding it
+
+	mu      sync.Mutex
+	deleted map[string][]string
+}
+
+func (f *fakeDuplicateCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/flights/_search":
+		buckets := []map[string]interface{}{}
+		for id, indices := range f.buckets {
+			indexBuckets := []map[string]interface{}{}
+			for _, index := range indices {
+				indexBuckets = append(indexBuckets, map[string]interface{}{"key": index, "doc_count": 1})
+			}
+			buckets = append(buckets, map[string]interface{}{
+				"key":       id,
+				"doc_count": len(indices),
+				"indices":   map[string]interface{}{"buckets": indexBuckets},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"hits":         map[string]interface{}{"hits": []interface{}{}},
+			"aggregations": map[string]interface{}{"duplicates": map[string]interface{}{"buckets": buckets}},
+		})
+
+	case strings.HasSuffix(r.URL.Path, "/_delete_by_query"):
+		var body struct {
+			Query struct {
+				IDs struct {
+					Values []string `json:"values"`
+				} `json:"ids"`
+			} `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		index := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/_delete_by_query")
+		f.mu.Lock()
+		f.deleted[index] = append(f.deleted[index], body.Query.IDs.Values...)
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"deleted": len(body.Query.IDs.Values)})
+
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+func TestDeleteStaleFlightCopies(t *testing.T) {
+	const (
+		current  = "flights_v2_20261001000000" // write index
+		previous = "flights_v2_20260901000000"
+		outdated = "flights_v1_20261101000000" // created later, but at an older mapping version
+		created  = "flights_v3_20261015000000" // created since the ranges were taken
+	)
+	ranges := []FlightIndexRange{
+		{Index: previous, Documents: 5},
+		{Index: current, WriteIndex: true, Documents: 10},
+		{Index: outdated, Documents: 5},
+		{Index: "flights_v2_20260801000000"}, // empty
+	}
+
+	cluster := &fakeDuplicateCluster{
+		buckets: map[string][]string{
+			"f1": {previous, current},
+			"f2": {outdated, previous},
+			"f3": {outdated, current, previous},
+			"f4": {created, current},
+		},
+		deleted: map[string][]string{},
+	}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	client, err := elastic.NewClient(elastic.SetURL(server.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	c := &Client{client: client}
+
+	deleted, err := c.deleteStaleFlightCopies("flights", ranges)
+	if err != nil {
+		t.Fatalf("deleteStaleFlightCopies() error = %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("deleteStaleFlightCopies() = %d, want 4", deleted)
+	}
+
+	// The newest copy of each flight stays; f4 waits for a run that knows the new index
+	want := map[string][]string{
+		previous: {"f1", "f3"},
+		outdated: {"f2", "f3"},
+	}
+	for index := range cluster.deleted {
+		sort.Strings(cluster.deleted[index])
+	}
+	if !reflect.DeepEqual(cluster.deleted, want) {
+		t.Errorf("deleted = %v, want %v", cluster.deleted, want)
+	}
+
+	// A single populated index cannot hold duplicates, so the cluster is not asked
+	c.client = nil
+	if deleted, err := c.deleteStaleFlightCopies("flights", ranges[1:2]); deleted != 0 || err != nil {
+		t.Errorf("deleteStaleFlightCopies() with one index = %d, %v, want 0, nil", deleted, err)
+	}
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
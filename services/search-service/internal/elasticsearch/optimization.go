@@ -33,18 +33,20 @@ func DefaultOptimizationConfig() *OptimizationConfig {
 	}
 }
 
-// OptimizeIndices applies performance optimizations to all indices
+// OptimizeIndices applies performance optimizations to the physical indices behind each alias
 func (c *Client) OptimizeIndices() error {
 	config := DefaultOptimizationConfig()
 	
-	indices := []string{
-		c.getFlightIndex(),
-		c.getAirportIndex(),
-	}
+	for _, def := range c.IndexDefinitions() {
+		state, err := c.IndexState(def)
+		if err != nil {
+			return err
+		}
 
-	for _, index := range indices {
-		if err := c.optimizeIndex(index, config); err != nil {
-			return fmt.Errorf("failed to optimize index %s: %w", index, err)
+		for _, index := range state.Indices {
+			if err := c.optimizeIndex(index, config); err != nil {
+				return fmt.Errorf("failed to optimize index %s: %w", index, err)
+			}
 		}
 	}
 
@@ -80,6 +82,11 @@ func (c *Client) optimizeIndex(index string, config *OptimizationConfig) error {
 
 // BulkIndexFlights efficiently bulk indexes multiple flights
 func (c *Client) BulkIndexFlights(flights []models.Flight) error {
+	return c.BulkIndexFlightsInto(c.getFlightWriteIndex(), flights)
+}
+
+// BulkIndexFlightsInto bulk indexes flights into a specific index, e.g. a version being built
+func (c *Client) BulkIndexFlightsInto(index string, flights []models.Flight) error {
 	if len(flights) == 0 {
 		return nil
 	}
@@ -89,7 +96,7 @@ func (c *Client) BulkIndexFlights(flights []models.Flight) error {
 	
 	for i, flight := range flights {
 		request := elastic.NewBulkIndexRequest().
-			Index(c.getFlightIndex()).
+			Index(index).
 			Id(flight.ID.String()).
 			Doc(flight)
 		
@@ -133,7 +140,7 @@ func (c *Client) BulkIndexAirports(airports []models.AirportSuggestion) error {
 	
 	for i, airport := range airports {
 		request := elastic.NewBulkIndexRequest().
-			Index(c.getAirportIndex()).
+			Index(c.getAirportWriteIndex()).
 			Id(airport.Code).
 			Doc(airport)
 		
@@ -376,7 +383,7 @@ func (c *Client) ForceRefresh() error {
 	return nil
 }
 
-// GetIndexStats returns performance statistics for indices
+// GetIndexStats returns performance statistics for the physical indices behind each alias
 func (c *Client) GetIndexStats() (map[string]interface{}, error) {
 	stats, err := c.client.IndexStats().
 		Index(c.getFlightIndex(), c.getAirportIndex()).
@@ -386,9 +393,21 @@ func (c *Client) GetIndexStats() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to get index stats: %w", err)
 	}
 
+	// Map each physical index back to the alias and version it serves
+	owners := make(map[string]*IndexState)
+	for _, def := range c.IndexDefinitions() {
+		state, err := c.IndexState(def)
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range state.Indices {
+			owners[index] = state
+		}
+	}
+
 	result := make(map[string]interface{})
 	for indexName, indexStats := range stats.Indices {
-		result[indexName] = map[string]interface{}{
+		indexResult := map[string]interface{}{
 			"docs_count":         indexStats.Total.Docs.Count,
 			"docs_deleted":       indexStats.Total.Docs.Deleted,
 			"store_size_bytes":   indexStats.Total.Store.SizeInBytes,
@@ -397,6 +416,13 @@ func (c *Client) GetIndexStats() (map[string]interface{}, error) {
 			"fetch_total":        indexStats.Total.Search.FetchTotal,
 			"fetch_time_millis":  indexStats.Total.Search.FetchTimeInMillis,
 		}
+		if state, ok := owners[indexName]; ok {
+			indexResult["alias"] = state.Definition.Alias
+			indexResult["version"] = parseIndexVersion(state.Definition.Alias, indexName)
+			indexResult["expected_version"] = state.Definition.Version
+			indexResult["is_write_index"] = indexName == state.WriteIndex
+		}
+		result[indexName] = indexResult
 	}
 
 	return result, nil
@@ -0,0 +1,285 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/olivere/elastic/v7"
+	"spontra/search-service/internal/models"
+)
+
+// Suggestion types stored in AirportSuggestion.Type
+const (
+	SuggestionTypeCity    = "city"
+	SuggestionTypeAirport = "airport"
+)
+
+// SuggestionDocument is an airport or city in the suggestion index. City documents exist for
+// metropolitan codes with several airports and carry those airports for grouping.
+type SuggestionDocument struct {
+	models.AirportSuggestion
+	Aliases  []string                   `json:"aliases,omitempty"` // city and country names in other languages
+	Traffic  int64                      `json:"traffic"`           // indexed flight offers from or to the airport
+	Picks    int64                      `json:"picks"`             // times users picked the suggestion
+	Airports []models.AirportSuggestion `json:"airports,omitempty"`
+}
+
+// SuggestionID is the document ID of a suggestion; city and airport codes can coincide
+func SuggestionID(suggestionType, code string) string {
+	return suggestionType + ":" + code
+}
+
+// SuggestAirports returns the suggestion documents matching a partially typed query. Prefixes
+// match through edge n-grams, whole words tolerate typos, and diacritics are folded on both
+// sides. Text relevance is topped up by traffic and picks, so busy and popular airports rank
+// first among similar matches.
+func (c *Client) SuggestAirports(query string, limit int) ([]SuggestionDocument, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	query = strings.TrimSpace(query)
+
+	textQuery := elastic.NewBoolQuery().
+		Should(elastic.NewTermQuery("code", strings.ToUpper(query)).Boost(20.0)).
+		Should(elastic.NewMultiMatchQuery(query, "name^3", "city^3", "aliases^2", "country").
+			Type("best_fields").
+			Operator("and")).
+		Should(elastic.NewMultiMatchQuery(query, "name.folded^2", "city.folded^2", "aliases.folded", "country.folded").
+			Type("best_fields").
+			Fuzziness("AUTO").
+			PrefixLength(1)).
+		MinimumShouldMatch("1")
+
+	scoredQuery := elastic.NewFunctionScoreQuery().
+		Query(textQuery).
+		AddScoreFunc(elastic.NewFieldValueFactorFunction().Field("traffic").Modifier("log1p").Missing(0)).
+		AddScoreFunc(elastic.NewFieldValueFactorFunction().Field("picks").Modifier("log1p").Factor(2).Missing(0)).
+		Add(elastic.NewTermQuery("type", SuggestionTypeCity), elastic.NewWeightFactorFunction(2)).
+		ScoreMode("sum").
+		BoostMode("sum")
+
+	// Fetch extra hits so airports listed under their city can be dropped without coming up short
+	searchResult, err := c.client.Search().
+		Index(c.getSuggestionIndex()).
+		Query(scoredQuery).
+		Size(limit * 2).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to search airport suggestions: %w", err)
+	}
+
+	suggestions := make([]SuggestionDocument, 0, len(searchResult.Hits.Hits))
+	for _, hit := range searchResult.Hits.Hits {
+		var suggestion SuggestionDocument
+		if err := json.Unmarshal(hit.Source, &suggestion); err != nil {
+			log.Printf("Failed to unmarshal airport suggestion: %v", err)
+			continue
+		}
+		if hit.Score != nil {
+			suggestion.Relevance = *hit.Score
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
+
+// RebuildAirportSuggestions loads the documents into a new suggestion index, swaps the aliases
+// over to it and deletes the previous version
+func (c *Client) RebuildAirportSuggestions(documents []SuggestionDocument) (string, error) {
+	def, err := c.IndexDefinition(c.cfg.ESSuggestionIndex)
+	if err != nil {
+		return "", err
+	}
+
+	index, err := c.CreateVersionedIndex(def)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.bulkIndexSuggestions(index, documents); err != nil {
+		if cleanupErr := c.DeleteIndices([]string{index}); cleanupErr != nil {
+			log.Printf("Failed to delete incomplete suggestion index %s: %v", index, cleanupErr)
+		}
+		return "", err
+	}
+
+	previous, err := c.SwapAliases(def, index)
+	if err != nil {
+		return "", err
+	}
+	if err := c.DeleteIndices(previous); err != nil {
+		log.Printf("Failed to delete previous suggestion indices: %v", err)
+	}
+
+	return index, nil
+}
+
+// SuggestionExists reports whether the suggestion index holds a suggestion
+func (c *Client) SuggestionExists(suggestionType, code string) (bool, error) {
+	exists, err := c.client.Exists().
+		Index(c.getSuggestionIndex()).
+		Id(SuggestionID(suggestionType, code)).
+		Do(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("failed to look up suggestion %s: %w", code, err)
+	}
+	return exists, nil
+}
+
+// RecordAirportPick increments the picks of a suggestion so ranking reflects it before the
+// next rebuild
+func (c *Client) RecordAirportPick(suggestionType, code string) error {
+	_, err := c.client.Update().
+		Index(c.getSuggestionWriteIndex()).
+		Id(SuggestionID(suggestionType, code)).
+		Script(elastic.NewScript("ctx._source.picks += params.picks").Param("picks", 1)).
+		RetryOnConflict(3).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to record pick of %s: %w", code, err)
+	}
+	return nil
+}
+
+// bulkIndexSuggestions indexes suggestion documents into a specific index and refreshes it
+func (c *Client) bulkIndexSuggestions(index string, documents []SuggestionDocument) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	bulkRequest := c.client.Bulk()
+	for _, document := range documents {
+		bulkRequest.Add(elastic.NewBulkIndexRequest().
+			Index(index).
+			Id(SuggestionID(document.Type, document.Code)).
+			Doc(document))
+	}
+
+	response, err := bulkRequest.Refresh("true").Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to index airport suggestions: %w", err)
+	}
+	if response.Errors {
+		failed := response.Failed()
+		return fmt.Errorf("failed to index %d airport suggestions: %s", len(failed), failed[0].Error.Reason)
+	}
+
+	return nil
+}
+
+// ListAirports returns every document in the airport index
+func (c *Client) ListAirports() ([]models.AirportSuggestion, error) {
+	airports, err := c.searchAirportDocuments(elastic.NewMatchAllQuery(), nil, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list airports: %w", err)
+	}
+	return airports, nil
+}
+
+// AirportTraffic counts the indexed flight offers departing from or arriving at each airport
+func (c *Client) AirportTraffic() (map[string]int64, error) {
+	searchResult, err := c.client.Search().
+		Index(c.getFlightIndex()).
+		Aggregation("origins", elastic.NewTermsAggregation().Field("origin_airport").Size(10000)).
+		Aggregation("destinations", elastic.NewTermsAggregation().Field("destination_airport").Size(10000)).
+		Size(0).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("airport traffic aggregation failed: %w", err)
+	}
+
+	traffic := make(map[string]int64)
+	for _, name := range []string{"origins", "destinations"} {
+		agg, found := searchResult.Aggregations.Terms(name)
+		if !found {
+			continue
+		}
+		for _, bucket := range agg.Buckets {
+			if code, ok := bucket.Key.(string); ok {
+				traffic[code] += bucket.DocCount
+			}
+		}
+	}
+
+	return traffic, nil
+}
+
+// suggestionIndexMapping indexes names as edge n-grams for prefix matching, with folded
+// whole-word subfields for fuzzy matching. Member airports of a city are stored, not indexed.
+const suggestionIndexMapping = `{
+	"settings": {
+		"number_of_shards": 1,
+		"number_of_replicas": 1,
+		"refresh_interval": "1s",
+		"analysis": {
+			"analyzer": {
+				"folding": {
+					"type": "custom",
+					"tokenizer": "standard",
+					"filter": ["lowercase", "asciifolding"]
+				},
+				"autocomplete": {
+					"type": "custom",
+					"tokenizer": "standard",
+					"filter": ["lowercase", "asciifolding", "autocomplete_edge_ngram"]
+				}
+			},
+			"filter": {
+				"autocomplete_edge_ngram": {
+					"type": "edge_ngram",
+					"min_gram": 1,
+					"max_gram": 20
+				}
+			}
+		}
+	},
+	"mappings": {
+		"dynamic": false,
+		"properties": {
+			"code": {"type": "keyword"},
+			"type": {"type": "keyword"},
+			"city_code": {"type": "keyword"},
+			"country_code": {"type": "keyword"},
+			"name": {
+				"type": "text",
+				"analyzer": "autocomplete",
+				"search_analyzer": "folding",
+				"fields": {
+					"folded": {"type": "text", "analyzer": "folding"}
+				}
+			},
+			"city": {
+				"type": "text",
+				"analyzer": "autocomplete",
+				"search_analyzer": "folding",
+				"fields": {
+					"folded": {"type": "text", "analyzer": "folding"}
+				}
+			},
+			"country": {
+				"type": "text",
+				"analyzer": "autocomplete",
+				"search_analyzer": "folding",
+				"fields": {
+					"folded": {"type": "text", "analyzer": "folding"}
+				}
+			},
+			"aliases": {
+				"type": "text",
+				"analyzer": "autocomplete",
+				"search_analyzer": "folding",
+				"fields": {
+					"folded": {"type": "text", "analyzer": "folding"}
+				}
+			},
+			"traffic": {"type": "long"},
+			"picks": {"type": "long"},
+			"coordinates": {"type": "geo_point"},
+			"airports": {"type": "object", "enabled": false}
+		}
+	}
+}`
@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Fallback block-time model used when no dataset is available: 850 km/h cruise plus
+// 35 minutes for taxi, climb and descent
+const (
+	fallbackInterceptMinutes = 35.0
+	fallbackMinutesPerKm     = 60.0 / 850.0
+	fallbackConfidence       = 0.5
+)
+
+// RouteDuration is one route of a duration dataset
+type RouteDuration struct {
+	OriginAirport      string
+	DestinationAirport string
+	DurationMinutes    int
+}
+
+// DurationSample pairs a route's great-circle distance with its block time
+type DurationSample struct {
+	DistanceKm      float64
+	DurationMinutes float64
+}
+
+// DurationModel predicts block time from great-circle distance with a linear fit
+type DurationModel struct {
+	InterceptMinutes float64
+	MinutesPerKm     float64
+	ResidualStdDev   float64 // minutes
+	MinDistanceKm    float64 // distance range covered by the fitted samples
+	MaxDistanceKm    float64
+	Samples          int
+}
+
+// FallbackDurationModel returns the fixed-speed model used when nothing could be fitted
+func FallbackDurationModel() *DurationModel {
+	return &DurationModel{
+		InterceptMinutes: fallbackInterceptMinutes,
+		MinutesPerKm:     fallbackMinutesPerKm,
+	}
+}
+
+// ReadRouteDurations parses a CSV of origin, destination, hours and minutes columns with a header row
+func ReadRouteDurations(r io.Reader) ([]RouteDuration, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read duration dataset: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("duration dataset has no rows")
+	}
+
+	routes := make([]RouteDuration, 0, len(records)-1)
+	for i, record := range records[1:] {
+		hours, err := strconv.Atoi(strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid hours %q", i+2, record[2])
+		}
+		minutes, err := strconv.Atoi(strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid minutes %q", i+2, record[3])
+		}
+
+		routes = append(routes, RouteDuration{
+			OriginAirport:      strings.ToUpper(strings.TrimSpace(record[0])),
+			DestinationAirport: strings.ToUpper(strings.TrimSpace(record[1])),
+			DurationMinutes:    hours*60 + minutes,
+		})
+	}
+
+	return routes, nil
+}
+
+// FitDurationModel fits block time against distance by ordinary least squares
+func FitDurationModel(samples []DurationSample) (*DurationModel, error) {
+	if len(samples) < 3 {
+		return nil, fmt.Errorf("need at least 3 samples to fit a duration model, got %d", len(samples))
+	}
+
+	n := float64(len(samples))
+	var sumX, sumY, sumXX, sumXY float64
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, sample := range samples {
+		sumX += sample.DistanceKm
+		sumY += sample.DurationMinutes
+		sumXX += sample.DistanceKm * sample.DistanceKm
+		sumXY += sample.DistanceKm * sample.DurationMinutes
+		minX = math.Min(minX, sample.DistanceKm)
+		maxX = math.Max(maxX, sample.DistanceKm)
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil, fmt.Errorf("duration samples do not cover a range of distances")
+	}
+
+	model := &DurationModel{
+		MinutesPerKm:  (n*sumXY - sumX*sumY) / denominator,
+		MinDistanceKm: minX,
+		MaxDistanceKm: maxX,
+		Samples:       len(samples),
+	}
+	model.InterceptMinutes = (sumY - model.MinutesPerKm*sumX) / n
+
+	var sumSquares float64
+	for _, sample := range samples {
+		residual := sample.DurationMinutes - model.Predict(sample.DistanceKm)
+		sumSquares += residual * residual
+	}
+	model.ResidualStdDev = math.Sqrt(sumSquares / (n - 2))
+
+	return model, nil
+}
+
+// Predict returns the expected block time in minutes for a distance
+func (m *DurationModel) Predict(distanceKm float64) float64 {
+	return m.InterceptMinutes + m.MinutesPerKm*distanceKm
+}
+
+// Estimate returns the predicted block time, rounded to whole minutes, and a confidence
+// between 0 and 1. Confidence falls with the model's error relative to the prediction and
+// is halved outside the distance range the model was fitted on.
+func (m *DurationModel) Estimate(distanceKm float64) (int, float64) {
+	minutes := math.Max(m.Predict(distanceKm), 1)
+
+	if m.Samples == 0 {
+		return int(math.Round(minutes)), fallbackConfidence
+	}
+
+	// A 95% prediction interval as a share of the predicted time
+	confidence := math.Max(0, 1-1.96*m.ResidualStdDev/minutes)
+	if distanceKm < m.MinDistanceKm || distanceKm > m.MaxDistanceKm {
+		confidence /= 2
+	}
+
+	return int(math.Round(minutes)), math.Round(confidence*100) / 100
+}
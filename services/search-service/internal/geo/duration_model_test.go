@@ -0,0 +1,159 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFitDurationModel(t *testing.T) {
+	tests := []struct {
+		name          string
+		samples       []DurationSample
+		wantErr       bool
+		wantIntercept float64
+		wantSlope     float64
+		wantResidual  float64
+	}{
+		{
+			name: "exact line",
+			samples: []DurationSample{
+				{DistanceKm: 0, DurationMinutes: 30},
+				{DistanceKm: 1000, DurationMinutes: 100},
+				{DistanceKm: 2000, DurationMinutes: 170},
+			},
+			wantIntercept: 30,
+			wantSlope:     0.07,
+			wantResidual:  0,
+		},
+		{
+			name: "noisy samples",
+			samples: []DurationSample{
+				{DistanceKm: 0, DurationMinutes: 30},
+				{DistanceKm: 1000, DurationMinutes: 110},
+				{DistanceKm: 2000, DurationMinutes: 170},
+			},
+			wantIntercept: 33.333333,
+			wantSlope:     0.07,
+			wantResidual:  math.Sqrt(66.666667),
+		},
+		{
+			name: "too few samples",
+			samples: []DurationSample{
+				{DistanceKm: 0, DurationMinutes: 30},
+				{DistanceKm: 1000, DurationMinutes: 100},
+			},
+			wantErr: true,
+		},
+		{
+			name: "single distance",
+			samples: []DurationSample{
+				{DistanceKm: 500, DurationMinutes: 60},
+				{DistanceKm: 500, DurationMinutes: 70},
+				{DistanceKm: 500, DurationMinutes: 80},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := FitDurationModel(tt.samples)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FitDurationModel() = %+v, want an error", model)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FitDurationModel() error = %v", err)
+			}
+
+			if !approx(model.InterceptMinutes, tt.wantIntercept) {
+				t.Errorf("InterceptMinutes = %f, want %f", model.InterceptMinutes, tt.wantIntercept)
+			}
+			if !approx(model.MinutesPerKm, tt.wantSlope) {
+				t.Errorf("MinutesPerKm = %f, want %f", model.MinutesPerKm, tt.wantSlope)
+			}
+			if !approx(model.ResidualStdDev, tt.wantResidual) {
+				t.Errorf("ResidualStdDev = %f, want %f", model.ResidualStdDev, tt.wantResidual)
+			}
+			if model.Samples != len(tt.samples) {
+				t.Errorf("Samples = %d, want %d", model.Samples, len(tt.samples))
+			}
+		})
+	}
+}
+
+func TestDurationModelEstimate(t *testing.T) {
+	model := &DurationModel{
+		InterceptMinutes: 30,
+		MinutesPerKm:     0.07,
+		ResidualStdDev:   10,
+		MinDistanceKm:    500,
+		MaxDistanceKm:    3000,
+		Samples:          100,
+	}
+
+	tests := []struct {
+		name           string
+		model          *DurationModel
+		distanceKm     float64
+		wantMinutes    int
+		wantConfidence float64
+	}{
+		{"inside the fitted range", model, 1000, 100, 0.8},
+		{"outside the fitted range", model, 4000, 310, 0.47},
+		{"never below a minute", &DurationModel{InterceptMinutes: -50, MinutesPerKm: 0.07, MaxDistanceKm: 1000, Samples: 3}, 100, 1, 1},
+		{"fallback model", FallbackDurationModel(), 850, 95, fallbackConfidence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minutes, confidence := tt.model.Estimate(tt.distanceKm)
+			if minutes != tt.wantMinutes {
+				t.Errorf("minutes = %d, want %d", minutes, tt.wantMinutes)
+			}
+			if !approx(confidence, tt.wantConfidence) {
+				t.Errorf("confidence = %f, want %f", confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestReadRouteDurations(t *testing.T) {
+	dataset := "origin,destination,hours,minutes\nlhr, cdg ,1,15\nLHR,JFK,8,5\n"
+
+	routes, err := ReadRouteDurations(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatalf("ReadRouteDurations() error = %v", err)
+	}
+
+	want := []RouteDuration{
+		{OriginAirport: "LHR", DestinationAirport: "CDG", DurationMinutes: 75},
+		{OriginAirport: "LHR", DestinationAirport: "JFK", DurationMinutes: 485},
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("routes = %+v, want %+v", routes, want)
+	}
+	for i := range want {
+		if routes[i] != want[i] {
+			t.Errorf("route %d = %+v, want %+v", i, routes[i], want[i])
+		}
+	}
+
+	for _, invalid := range []string{
+		"origin,destination,hours,minutes\n",
+		"origin,destination,hours,minutes\nLHR,CDG,one,15\n",
+		"origin,destination,hours,minutes\nLHR,CDG,1\n",
+	} {
+		if _, err := ReadRouteDurations(strings.NewReader(invalid)); err == nil {
+			t.Errorf("ReadRouteDurations(%q) succeeded, want an error", invalid)
+		}
+	}
+}
+
+// approx compares floats to six decimal places
+func approx(got, want float64) bool {
+	return math.Abs(got-want) < 1e-6
+}
@@ -7,15 +7,17 @@ import (
 
     "github.com/gin-gonic/gin"
     "spontra/search-service/internal/repository"
+    "spontra/search-service/internal/services"
 )
 
 // DurationHandler serves flight duration data
 type DurationHandler struct {
-    durations *repository.DurationRepository
+    durations   *repository.DurationRepository
+    connections *services.ConnectionBuilder
 }
 
-func NewDurationHandler(repo *repository.DurationRepository) *DurationHandler {
-    return &DurationHandler{durations: repo}
+func NewDurationHandler(repo *repository.DurationRepository, connections *services.ConnectionBuilder) *DurationHandler {
+    return &DurationHandler{durations: repo, connections: connections}
 }
 
 // GetRouteDuration returns duration for a specific route
@@ -102,6 +104,34 @@ func (h *DurationHandler) PopularDestinations(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"items": list, "count": len(list)})
 }
 
+// Paths proposes one- and two-stop itineraries through hub airports
+func (h *DurationHandler) Paths(c *gin.Context) {
+    from := strings.ToUpper(strings.TrimSpace(c.Query("from")))
+    to := strings.ToUpper(strings.TrimSpace(c.Query("to")))
+    if from == "" || to == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+        return
+    }
+    if from == to {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "from and to cannot be the same"})
+        return
+    }
+    maxStops, err := strconv.Atoi(c.DefaultQuery("max_stops", "2"))
+    if err != nil || maxStops < 0 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "max_stops must be a non-negative integer"})
+        return
+    }
+    maxDuration, _ := strconv.Atoi(c.DefaultQuery("max_duration", "0"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+    paths, err := h.connections.FindPaths(from, to, maxStops, maxDuration, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+    c.JSON(http.StatusOK, paths)
+}
+
 // RouteStats returns aggregated route statistics
 func (h *DurationHandler) RouteStats(c *gin.Context) {
     stats, err := h.durations.GetRouteStatistics()
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"spontra/search-service/internal/auth"
+	"spontra/search-service/internal/models"
+	sharedErrors "spontra/shared/errors"
+)
+
+// claimsKey is the context key RequireRole stores verified claims under
+const claimsKey = "claims"
+
+// maxAuditBodySize caps the request body copied into an audit entry
+const maxAuditBodySize = 64 << 10
+
+// RequireAuth only lets through requests bearing a valid user-service access token. Verified
+// claims are available to later handlers through ClaimsFromContext.
+func RequireAuth(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := verifyBearerToken(c, verifier)
+		if !ok {
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuth verifies the bearer token of requests that send one, so handlers can attribute
+// them to the user through ClaimsFromContext. Requests without a token pass through anonymously;
+// an invalid or expired token is rejected rather than silently treated as anonymous.
+func OptionalAuth(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		claims, ok := verifyBearerToken(c, verifier)
+		if !ok {
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequireRole only lets through requests bearing a user-service access token that grants the
+// role. Verified claims are available to later handlers through ClaimsFromContext, and are kept
+// for a token without the role too so Audit can name the denied user.
+func RequireRole(verifier *auth.Verifier, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := verifyBearerToken(c, verifier)
+		if !ok {
+			return
+		}
+		c.Set(claimsKey, claims)
+
+		if !claims.HasRole(role) {
+			log.Printf("Denied %s %s to user %s without role %s", c.Request.Method, c.FullPath(), claims.UserID, role)
+			abortWithError(c, sharedErrors.AuthorizationError("The "+role+" role is required for this operation"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditRecorder appends entries to the admin audit log
+type AuditRecorder interface {
+	RecordAdminAction(entry *models.AdminAuditEntry) error
+}
+
+// Audit writes the action to the admin audit log once the request has been handled, with the
+// actor from RequireRole, the request's parameters and the response status. It runs ahead of
+// RequireRole so denied attempts are recorded too; a request without a valid token is recorded
+// with the nil actor ID. A failed write is logged and does not change the response.
+func Audit(recorder AuditRecorder, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parameters := auditParameters(c)
+
+		c.Next()
+
+		entry := &models.AdminAuditEntry{
+			Action:     action,
+			Parameters: parameters,
+			StatusCode: c.Writer.Status(),
+			IPAddress:  c.ClientIP(),
+			CreatedAt:  time.Now(),
+		}
+		if claims := ClaimsFromContext(c); claims != nil {
+			entry.ActorID = claims.UserID
+			entry.ActorEmail = claims.Email
+		}
+		if err := recorder.RecordAdminAction(entry); err != nil {
+			log.Printf("Failed to write audit entry for %s by %s: %v", action, entry.ActorID, err)
+		}
+	}
+}
+
+// verifyBearerToken verifies the request's bearer token, aborting the request if it is missing
+// or invalid
+func verifyBearerToken(c *gin.Context, verifier *auth.Verifier) (*auth.Claims, bool) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		abortWithError(c, sharedErrors.AuthenticationError("Authorization header must be in 'Bearer <token>' format"))
+		return nil, false
+	}
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		abortWithError(c, sharedErrors.AuthenticationError("Invalid or expired token"))
+		return nil, false
+	}
+	return claims, true
+}
+
+// ClaimsFromContext returns the claims verified by RequireAuth, OptionalAuth or RequireRole, or nil
+func ClaimsFromContext(c *gin.Context) *auth.Claims {
+	value, exists := c.Get(claimsKey)
+	if !exists {
+		return nil
+	}
+	claims, _ := value.(*auth.Claims)
+	return claims
+}
+
+// auditParameters collects the path parameters, query string and JSON body of a request. The
+// body is restored for the handler.
+func auditParameters(c *gin.Context) map[string]interface{} {
+	parameters := make(map[string]interface{})
+
+	for _, param := range c.Params {
+		parameters[param.Key] = param.Value
+	}
+	for key, values := range c.Request.URL.Query() {
+		if len(values) == 1 {
+			parameters[key] = values[0]
+		} else {
+			parameters[key] = values
+		}
+	}
+
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return parameters
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodySize+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil || len(body) == 0 {
+		return parameters
+	}
+	if len(body) > maxAuditBodySize {
+		parameters["body_truncated"] = true
+		return parameters
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		parameters["body"] = payload
+	}
+
+	return parameters
+}
+
+// abortWithError ends the request with an AppError as the response body
+func abortWithError(c *gin.Context, appErr *sharedErrors.AppError) {
+	appErr.Service = "search-service"
+	appErr.Operation = c.Request.Method + " " + c.FullPath()
+	c.AbortWithStatusJSON(appErr.StatusCode, appErr)
+}
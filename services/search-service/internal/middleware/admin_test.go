@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"spontra/search-service/internal/auth"
+	"spontra/search-service/internal/models"
+)
+
+// memoryAuditLog keeps audit entries in memory, optionally failing every write
+type memoryAuditLog struct {
+	entries []models.AdminAuditEntry
+	err     error
+}
+
+func (m *memoryAuditLog) RecordAdminAction(entry *models.AdminAuditEntry) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// adminRouter serves DELETE /admin/cache/:region the way main.go wires admin routes
+func adminRouter(auditLog AuditRecorder, handled *int) *gin.Engine {
+	router := gin.New()
+	verifier := auth.NewVerifier(testJWTSecret)
+	router.DELETE("/admin/cache/:region", Audit(auditLog, "cache.clear"), RequireRole(verifier, auth.RoleAdmin), func(c *gin.Context) {
+		*handled++
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestAdminRoleCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin, user := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		authHeader  string
+		wantStatus  int
+		wantHandled bool
+		wantActor   uuid.UUID // the nil ID for requests without a valid token
+	}{
+		{"admin", "Bearer " + signTestToken(t, admin, auth.RoleAdmin), http.StatusNoContent, true, admin},
+		{"user without the role", "Bearer " + signTestToken(t, user, auth.RoleUser), http.StatusForbidden, false, user},
+		{"token without a role", "Bearer " + signTestToken(t, user, ""), http.StatusForbidden, false, user},
+		{"invalid token", "Bearer not-a-token", http.StatusUnauthorized, false, uuid.Nil},
+		{"no token", "", http.StatusUnauthorized, false, uuid.Nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog := &memoryAuditLog{}
+			handled := 0
+			request := httptest.NewRequest("DELETE", "/admin/cache/eu?pattern=search:*", nil)
+			if tt.authHeader != "" {
+				request.Header.Set("Authorization", tt.authHeader)
+			}
+
+			recorder := httptest.NewRecorder()
+			adminRouter(auditLog, &handled).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if (handled == 1) != tt.wantHandled {
+				t.Errorf("handler ran %d times, want handled = %v", handled, tt.wantHandled)
+			}
+
+			// Every attempt is audited, denied ones included
+			if len(auditLog.entries) != 1 {
+				t.Fatalf("got %d audit entries, want 1", len(auditLog.entries))
+			}
+			entry := auditLog.entries[0]
+			if entry.ActorID != tt.wantActor || entry.Action != "cache.clear" || entry.StatusCode != tt.wantStatus {
+				t.Errorf("audit entry = %s %s %d, want %s cache.clear %d",
+					entry.ActorID, entry.Action, entry.StatusCode, tt.wantActor, tt.wantStatus)
+			}
+			if entry.Parameters["region"] != "eu" || entry.Parameters["pattern"] != "search:*" {
+				t.Errorf("audit parameters = %v, want the region and pattern", entry.Parameters)
+			}
+		})
+	}
+}
+
+func TestAuditWriteFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A failed audit write does not change the response
+	handled := 0
+	request := httptest.NewRequest("DELETE", "/admin/cache/eu", nil)
+	request.Header.Set("Authorization", "Bearer "+signTestToken(t, uuid.New(), auth.RoleAdmin))
+
+	recorder := httptest.NewRecorder()
+	adminRouter(&memoryAuditLog{err: errors.New("database unavailable")}, &handled).ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent || handled != 1 {
+		t.Errorf("status = %d with %d handler runs, want 204 after one run", recorder.Code, handled)
+	}
+}
+
+func TestAuditParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        map[string]interface{}
+	}{
+		{
+			name:        "json body",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"index":"flights","replicas":2}`,
+			want: map[string]interface{}{
+				"body": map[string]interface{}{"index": "flights", "replicas": float64(2)},
+			},
+		},
+		{
+			name:        "form body is not recorded",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "index=flights",
+			want:        map[string]interface{}{},
+		},
+		{
+			name:        "invalid json is not recorded",
+			contentType: "application/json",
+			body:        `{"index":`,
+			want:        map[string]interface{}{},
+		},
+		{
+			name:        "oversized body",
+			contentType: "application/json",
+			body:        `"` + strings.Repeat("a", maxAuditBodySize) + `"`,
+			want:        map[string]interface{}{"body_truncated": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/admin/indices/warmup?dry_run=true&tag=a&tag=b", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+			c.Params = gin.Params{{Key: "index", Value: "flights-v2"}}
+
+			parameters := auditParameters(c)
+
+			// Path and query parameters are always recorded
+			tt.want["index"] = "flights-v2"
+			tt.want["dry_run"] = "true"
+			tt.want["tag"] = []string{"a", "b"}
+			if !reflect.DeepEqual(parameters, tt.want) {
+				t.Errorf("auditParameters() = %v, want %v", parameters, tt.want)
+			}
+
+			// The handler still reads the whole body
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil || string(body) != tt.body {
+				t.Errorf("body after auditing = %d bytes, %v, want the original %d bytes", len(body), err, len(tt.body))
+			}
+		})
+	}
+}
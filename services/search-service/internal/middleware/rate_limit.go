@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"spontra/search-service/internal/auth"
+	"spontra/search-service/internal/cache"
+	"spontra/search-service/internal/config"
+	sharedErrors "spontra/shared/errors"
+)
+
+// Rate limit policies. Routes without a policy share the default budget.
+const (
+	PolicyDefault      = "default"
+	PolicySearch       = "search"       // provider-backed searches
+	PolicyAutocomplete = "autocomplete" // called on every keystroke
+)
+
+// apiKeyHeader carries the API key of a server-to-server client
+const apiKeyHeader = "X-API-Key"
+
+// RateLimiter limits requests per client over a sliding window kept in Redis. Clients are
+// identified by a configured API key, the user of a verified access token, or otherwise by IP
+// address; headers a caller can simply change are never used.
+type RateLimiter struct {
+	cfg             *config.Config
+	cache           *cache.RedisClient
+	cacheKeyBuilder *cache.CacheKeyBuilder
+	verifier        *auth.Verifier
+	routes          map[string]string // "METHOD /full/path" to policy
+	apiKeys         map[string]int    // hashed API key to its budget per window
+}
+
+// NewRateLimiter creates a rate limiter applying the given route policies
+func NewRateLimiter(cfg *config.Config, redisClient *cache.RedisClient, verifier *auth.Verifier, routes map[string]string) *RateLimiter {
+	return &RateLimiter{
+		cfg:             cfg,
+		cache:           redisClient,
+		cacheKeyBuilder: cache.NewCacheKeyBuilder("search"),
+		verifier:        verifier,
+		routes:          routes,
+		apiKeys:         hashAPIKeys(cfg.RateLimitAPIKeys),
+	}
+}
+
+// Middleware enforces the budget of the matched route's policy. Every response carries
+// RateLimit-* headers; rejected requests get a 429 with a RateLimitError body. Requests are
+// let through when Redis is unavailable.
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.cfg.EnableRateLimit || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		policy := l.policy(c)
+		client, budget := l.clientIdentity(c)
+		limit := l.limit(policy)
+		if budget > 0 {
+			limit = budget
+		}
+		window := l.cfg.RateLimitWindow
+		if limit <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		key := l.cacheKeyBuilder.RateLimit(policy, client)
+		result, err := l.cache.SlidingWindowHit(key, limit, window)
+		if err != nil {
+			log.Printf("Rate limiting unavailable, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		reset := ceilSeconds(result.Reset)
+		remaining := int64(limit) - result.Count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, int(window.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("RateLimit-Reset", strconv.Itoa(reset))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(reset))
+			appErr := sharedErrors.RateLimitError(
+				fmt.Sprintf("Rate limit of %d requests per %s exceeded", limit, window),
+				time.Duration(reset)*time.Second,
+			)
+			appErr.Details["policy"] = policy
+			abortWithError(c, appErr)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// policy returns the policy of the matched route
+func (l *RateLimiter) policy(c *gin.Context) string {
+	if policy, ok := l.routes[c.Request.Method+" "+c.FullPath()]; ok {
+		return policy
+	}
+	return PolicyDefault
+}
+
+// limit returns the requests per window allowed under a policy
+func (l *RateLimiter) limit(policy string) int {
+	if limit, ok := l.cfg.RateLimitPolicies[policy]; ok {
+		return limit
+	}
+	return l.cfg.RateLimitRequests
+}
+
+// clientIdentity identifies the caller by a configured API key, the user of a valid access
+// token, or by IP address. API keys come with their own budget; it is 0 for other callers. An
+// unknown key or invalid token falls back to the next identity, so made-up credentials buy no
+// fresh budget.
+func (l *RateLimiter) clientIdentity(c *gin.Context) (string, int) {
+	if key := c.GetHeader(apiKeyHeader); key != "" {
+		hashed := hashAPIKey(key)
+		if budget, ok := l.apiKeys[hashed]; ok {
+			return "key:" + hashed, budget
+		}
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && token != "" {
+		if claims, err := l.verifier.Verify(token); err == nil {
+			return "user:" + claims.UserID.String(), 0
+		}
+	}
+	return "ip:" + c.ClientIP(), 0
+}
+
+// hashAPIKeys indexes API key budgets by hashed key, so raw keys never reach Redis keys or logs
+func hashAPIKeys(budgets map[string]int) map[string]int {
+	hashed := make(map[string]int, len(budgets))
+	for key, budget := range budgets {
+		hashed[hashAPIKey(key)] = budget
+	}
+	return hashed
+}
+
+// hashAPIKey returns the hex SHA-256 of an API key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ceilSeconds rounds a duration up to whole seconds, so clients never retry too early
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"spontra/search-service/internal/auth"
+	"spontra/search-service/internal/config"
+)
+
+const testJWTSecret = "test-secret"
+
+// signTestToken signs a user-service access token for the user with the role, "" for none
+func signTestToken(t *testing.T, userID uuid.UUID, role string) string {
+	t.Helper()
+	claims := auth.Claims{
+		UserID: userID,
+		Email:  userID.String() + "@example.com",
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    auth.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestClientIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{RateLimitAPIKeys: map[string]int{"partner-key": 1000}}
+	limiter := NewRateLimiter(cfg, nil, auth.NewVerifier(testJWTSecret), nil)
+
+	userID := uuid.New()
+	token := signTestToken(t, userID, "")
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		authHeader string
+		want       string
+		wantBudget int
+	}{
+		{"api key", "partner-key", "", "key:" + hashAPIKey("partner-key"), 1000},
+		{"api key wins over token", "partner-key", "Bearer " + token, "key:" + hashAPIKey("partner-key"), 1000},
+		{"unknown api key", "made-up-key", "", "ip:192.0.2.1", 0},
+		{"unknown api key with token", "made-up-key", "Bearer " + token, "user:" + userID.String(), 0},
+		{"verified token", "", "Bearer " + token, "user:" + userID.String(), 0},
+		{"invalid token", "", "Bearer not-a-token", "ip:192.0.2.1", 0},
+		{"anonymous", "", "", "ip:192.0.2.1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/search/flights", nil)
+			c.Request.RemoteAddr = "192.0.2.1:51234"
+			if tt.apiKey != "" {
+				c.Request.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			if tt.authHeader != "" {
+				c.Request.Header.Set("Authorization", tt.authHeader)
+			}
+
+			got, budget := limiter.clientIdentity(c)
+			if got != tt.want || budget != tt.wantBudget {
+				t.Errorf("clientIdentity() = %q, %d, want %q, %d", got, budget, tt.want, tt.wantBudget)
+			}
+			if strings.Contains(got, tt.apiKey) && tt.apiKey != "" {
+				t.Errorf("clientIdentity() = %q exposes the raw API key", got)
+			}
+		})
+	}
+}
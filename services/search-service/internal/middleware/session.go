@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"spontra/search-service/internal/auth"
+)
+
+// sessionHeader carries the search session token in both directions
+const sessionHeader = "X-Session-ID"
+
+// sessionKey is the context key SearchSession stores the request's session under
+const sessionKey = "search_session"
+
+// searchSession is the search session of a request
+type searchSession struct {
+	id        string
+	presented bool // the client sent a valid token for it rather than starting it now
+}
+
+// SearchSession resolves the request's search session from the token in X-Session-ID. A request
+// without a valid token starts a new session, whose token is returned in the X-Session-ID
+// response header for the client to send on later requests.
+func SearchSession(signer *auth.SessionSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetHeader(sessionHeader); token != "" {
+			if sessionID, err := signer.Verify(token); err == nil {
+				c.Set(sessionKey, searchSession{id: sessionID, presented: true})
+				c.Next()
+				return
+			}
+		}
+
+		sessionID, token := signer.Issue()
+		c.Header(sessionHeader, token)
+		c.Set(sessionKey, searchSession{id: sessionID})
+		c.Next()
+	}
+}
+
+// SessionFromContext returns the ID of the request's search session, or "" outside SearchSession
+func SessionFromContext(c *gin.Context) string {
+	value, _ := c.Get(sessionKey)
+	session, _ := value.(searchSession)
+	return session.id
+}
+
+// PresentedSession returns the ID of the search session the client presented a token for, or ""
+// when the request started a new one. Only a presented session may be read or claimed.
+func PresentedSession(c *gin.Context) string {
+	value, _ := c.Get(sessionKey)
+	session, _ := value.(searchSession)
+	if !session.presented {
+		return ""
+	}
+	return session.id
+}
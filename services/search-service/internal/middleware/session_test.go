@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"spontra/search-service/internal/auth"
+)
+
+func TestSearchSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := auth.NewSessionSigner("session-secret")
+	sessionID, token := signer.Issue()
+	_, foreignToken := auth.NewSessionSigner("other-secret").Issue()
+
+	tests := []struct {
+		name          string
+		header        string
+		wantSession   string // "" for a newly started session
+		wantPresented bool
+	}{
+		{"no token", "", "", false},
+		{"issued token", token, sessionID, true},
+		{"bare session ID", sessionID, "", false},
+		{"token signed elsewhere", foreignToken, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("POST", "/api/v1/search/history/claim", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(sessionHeader, tt.header)
+			}
+
+			SearchSession(signer)(c)
+
+			current, presented := SessionFromContext(c), PresentedSession(c)
+			if tt.wantPresented {
+				if current != tt.wantSession || presented != tt.wantSession {
+					t.Errorf("session = %q, presented %q, want %q for both", current, presented, tt.wantSession)
+				}
+				if issued := recorder.Header().Get(sessionHeader); issued != "" {
+					t.Errorf("issued token %q for a presented session", issued)
+				}
+				return
+			}
+
+			if presented != "" {
+				t.Errorf("PresentedSession() = %q, want none for a new session", presented)
+			}
+			if current == "" || current == sessionID {
+				t.Errorf("SessionFromContext() = %q, want a new session", current)
+			}
+			issued, err := signer.Verify(recorder.Header().Get(sessionHeader))
+			if err != nil || issued != current {
+				t.Errorf("issued token verifies to %q, %v, want %q", issued, err, current)
+			}
+		})
+	}
+}
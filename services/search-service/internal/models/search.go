@@ -10,13 +10,18 @@ import (
 type FlightSearchRequest struct {
 	ID                     uuid.UUID  `json:"id"`
 	UserID                 *uuid.UUID `json:"user_id,omitempty"`
-	OriginAirport          string     `json:"origin_airport" binding:"required"`
-	DestinationAirport     string     `json:"destination_airport" binding:"required"`
-	DepartureDate          time.Time  `json:"departure_date" binding:"required"`
+	OriginAirport          string     `json:"origin_airport" binding:"required_without=Legs"`
+	DestinationAirport     string     `json:"destination_airport" binding:"required_without=Legs"`
+	DepartureDate          time.Time  `json:"departure_date" binding:"required_without=Legs"`
 	ReturnDate             *time.Time `json:"return_date,omitempty"`
 	PassengerCount         int        `json:"passenger_count" binding:"min=1,max=9"`
 	CabinClass             string     `json:"cabin_class"`
-	TripType               string     `json:"trip_type" binding:"required"` // "oneway", "return"
+	TripType               string     `json:"trip_type" binding:"required"` // "oneway", "return", "multicity"
+	Legs                   []SearchLeg `json:"legs,omitempty"` // multi-city legs in travel order
+	ExpandAirports         bool       `json:"expand_airports"` // resolve city codes to their airports
+	NearbyRadiusKm         float64    `json:"nearby_radius_km,omitempty"` // also search airports within this radius
+	PreferredDepartureWindow string   `json:"preferred_departure_window,omitempty"` // "morning", "afternoon", "evening", "night"
+	IncludeScoreBreakdown  bool       `json:"include_score_breakdown"`
 	FlexibleDates          bool       `json:"flexible_dates"`
 	FlexibleDatesRange     int        `json:"flexible_dates_range"` // days
 	MaxResults             int        `json:"max_results"`
@@ -30,10 +35,28 @@ type FlightSearchRequest struct {
 	MaxStops               *int       `json:"max_stops,omitempty"`
 	PreferredAirlines      []string   `json:"preferred_airlines,omitempty"`
 	ExcludedAirlines       []string   `json:"excluded_airlines,omitempty"`
+	Currency               string     `json:"currency,omitempty"` // ISO 4217 display currency; defaults to the FX base currency
+	TravelNeeds            *TravelNeeds `json:"travel_needs,omitempty"` // extras priced into each flight's trip price
 	CreatedAt              time.Time  `json:"created_at"`
 	SearchSessionID        string     `json:"search_session_id"`
 }
 
+// TravelNeeds are the extras each passenger needs. Flights are ranked and filtered by their
+// trip price: the fare plus whatever these extras cost on top of what the fare includes.
+type TravelNeeds struct {
+	CheckedBags   int  `json:"checked_bags"`
+	CabinBags     int  `json:"cabin_bags"`
+	SeatSelection bool `json:"seat_selection"`
+	Changes       bool `json:"changes"` // the booking may need changing, so a change fee counts
+}
+
+// SearchLeg represents one leg of a multi-city search
+type SearchLeg struct {
+	OriginAirport      string    `json:"origin_airport"`
+	DestinationAirport string    `json:"destination_airport"`
+	DepartureDate      time.Time `json:"departure_date"`
+}
+
 // FlightSearchResponse represents the search response
 type FlightSearchResponse struct {
 	SearchID        uuid.UUID     `json:"search_id"`
@@ -41,10 +64,42 @@ type FlightSearchResponse struct {
 	SearchRequest   FlightSearchRequest `json:"search_request"`
 	Flights         []Flight      `json:"flights"`
 	SearchMetadata  SearchMetadata `json:"search_metadata"`
+	PriceMatrix     *PriceMatrix  `json:"price_matrix,omitempty"`
+	Legs            []LegGroup    `json:"legs,omitempty"` // multi-city results grouped per leg
 	CreatedAt       time.Time     `json:"created_at"`
 	ExpiresAt       time.Time     `json:"expires_at"`
 }
 
+// LegGroup lists the distinct flights offered for one leg of a multi-city search.
+// Leg flights are priced by the cheapest combined itinerary that contains them.
+type LegGroup struct {
+	Index              int             `json:"index"`
+	OriginAirport      string          `json:"origin_airport"`
+	DestinationAirport string          `json:"destination_airport"`
+	DepartureDate      time.Time       `json:"departure_date"`
+	Flights            []Flight        `json:"flights"`
+	CheapestPrice      decimal.Decimal `json:"cheapest_price"`
+}
+
+// PriceMatrix holds the cheapest fare for each departure and return date of a flexible search
+type PriceMatrix struct {
+	DepartureDates []string            `json:"departure_dates"`
+	ReturnDates    []string            `json:"return_dates,omitempty"`
+	Cells          [][]PriceMatrixCell `json:"cells"` // indexed [departure][return]; one column for one-way trips
+	Cheapest       *PriceMatrixCell    `json:"cheapest,omitempty"`
+	Currency       string              `json:"currency"`
+}
+
+// PriceMatrixCell is the cheapest fare found for one date combination
+type PriceMatrixCell struct {
+	DepartureDate string           `json:"departure_date"`
+	ReturnDate    string           `json:"return_date,omitempty"`
+	SearchID      *uuid.UUID       `json:"search_id,omitempty"` // filter this cell's flights via /flights/filter
+	Price         *decimal.Decimal `json:"price,omitempty"`     // nil when no flights were found
+	FromCache     bool             `json:"from_cache"`
+	Error         string           `json:"error,omitempty"`
+}
+
 // Flight represents a flight option
 type Flight struct {
 	ID              uuid.UUID       `json:"id"`
@@ -56,6 +111,8 @@ type Flight struct {
 	Duration        int             `json:"duration_minutes"`
 	Price           decimal.Decimal `json:"price"`
 	Currency        string          `json:"currency"`
+	OriginalPrice    *decimal.Decimal `json:"original_price,omitempty"`    // fare as quoted by the provider, when converted
+	OriginalCurrency string           `json:"original_currency,omitempty"` // currency the provider quoted in
 	CabinClass      string          `json:"cabin_class"`
 	Airline         string          `json:"airline"`
 	FlightNumber    string          `json:"flight_number"`
@@ -64,14 +121,101 @@ type Flight struct {
 	StopDetails     []Stop          `json:"stop_details,omitempty"`
 	IsRefundable    bool            `json:"is_refundable"`
 	BaggageIncluded bool            `json:"baggage_included"`
+	FareFamily      *FareFamily     `json:"fare_family,omitempty"`
+	Ancillaries     []Ancillary     `json:"ancillaries,omitempty"` // extras the provider sells with the fare
+	TripPrice       *TripPrice      `json:"trip_price,omitempty"`  // set when the search declares travel needs
 	BookingURL      string          `json:"booking_url"`
 	BookingDeepLink string          `json:"booking_deep_link,omitempty"`
 	ValidUntil      time.Time       `json:"valid_until"`
 	SeatsAvailable  *int            `json:"seats_available,omitempty"`
 	PriceBreakdown  PriceBreakdown  `json:"price_breakdown,omitempty"`
 	ReturnFlight    *Flight         `json:"return_flight,omitempty"`
+	Legs            []Flight        `json:"legs,omitempty"` // every leg of a multi-city itinerary, in travel order
+	OriginDistanceKm      float64   `json:"origin_distance_km,omitempty"`      // from the requested origin, for expanded searches
+	DestinationDistanceKm float64   `json:"destination_distance_km,omitempty"` // from the requested destination, for expanded searches
 	RelevanceScore  float64         `json:"relevance_score"`
 	ActivityMatch   float64         `json:"activity_match,omitempty"`
+	ScoreBreakdown  *ScoreBreakdown `json:"score_breakdown,omitempty"`
+	Tags            []string        `json:"tags,omitempty"` // "cheapest", "fastest", "best"
+	Dominated       bool            `json:"dominated"`      // another flight is at least as cheap, fast and direct
+	ProviderOffers  []ProviderOffer `json:"provider_offers,omitempty"`
+}
+
+// Flight tags set on the Pareto frontier of a result set
+const (
+	FlightTagCheapest = "cheapest"
+	FlightTagFastest  = "fastest"
+	FlightTagBest     = "best"
+)
+
+// FareFamily describes what a fare includes for each passenger on every segment
+type FareFamily struct {
+	Code          string `json:"code,omitempty"` // branded fare, e.g. "LIGHT"
+	Name          string `json:"name,omitempty"`
+	CheckedBags   int    `json:"checked_bags"`
+	CabinBags     int    `json:"cabin_bags"`
+	SeatSelection string `json:"seat_selection,omitempty"` // "included" or "chargeable"; empty when unknown
+	Changes       string `json:"changes,omitempty"`        // as SeatSelection
+	Refunds       string `json:"refunds,omitempty"`        // as SeatSelection
+}
+
+// Fare conditions for the services of a fare family
+const (
+	FareConditionIncluded   = "included"
+	FareConditionChargeable = "chargeable"
+)
+
+// Ancillary is an extra sold with a fare, priced per passenger for the whole trip in the
+// flight's currency
+type Ancillary struct {
+	Type  string          `json:"type"`
+	Price decimal.Decimal `json:"price"`
+}
+
+// Ancillary types
+const (
+	AncillaryCheckedBag = "checked_bag"
+	AncillaryCabinBag   = "cabin_bag"
+	AncillarySeat       = "seat"
+	AncillaryChange     = "change" // one change of the booking
+)
+
+// TripPrice is what all passengers pay for a flight once the extras they need are added
+type TripPrice struct {
+	Fare      decimal.Decimal `json:"fare"`
+	Extras    []TripExtra     `json:"extras,omitempty"`
+	Total     decimal.Decimal `json:"total"`
+	Estimated  bool            `json:"estimated"`            // an extra was priced from defaults, not by the provider
+	Incomplete bool            `json:"incomplete,omitempty"` // an extra needed could not be priced and is missing from the total
+}
+
+// TripExtra is one extra added to a trip price
+type TripExtra struct {
+	Type      string          `json:"type"`
+	Quantity  int             `json:"quantity"` // across all passengers
+	Price     decimal.Decimal `json:"price"`    // for the whole quantity
+	Estimated bool            `json:"estimated"`
+}
+
+// ScoreBreakdown explains how a flight's relevance score was computed.
+// Component scores are between 0 and 1; weights only cover components with a signal.
+type ScoreBreakdown struct {
+	Price         float64            `json:"price"`
+	Duration      float64            `json:"duration"`
+	Stops         float64            `json:"stops"`
+	DepartureFit  float64            `json:"departure_fit"`
+	ActivityMatch float64            `json:"activity_match"`
+	Weights       map[string]float64 `json:"weights"`
+}
+
+// ProviderOffer records one provider's fare for a merged flight
+type ProviderOffer struct {
+	Provider        string          `json:"provider"`
+	Price           decimal.Decimal `json:"price"`
+	Currency        string          `json:"currency"`
+	BookingURL      string          `json:"booking_url,omitempty"`
+	IsRefundable    bool            `json:"is_refundable"`
+	BaggageIncluded bool            `json:"baggage_included"`
 }
 
 // Stop represents a flight stop/layover
@@ -105,10 +249,48 @@ type SearchMetadata struct {
 	ProvidersErrors map[string]string `json:"providers_errors,omitempty"`
 	CacheHit        bool          `json:"cache_hit"`
 	FromCache       bool          `json:"from_cache"`
+	Stale           bool          `json:"stale"`     // served past the soft TTL while a refresh runs
+	Coalesced       bool          `json:"coalesced"` // shared the result of a concurrent identical search
+	FromSnapshot    bool          `json:"from_snapshot,omitempty"` // restored from the persisted result snapshot
 	Currency        string        `json:"currency"`
 	PriceRange      PriceRange    `json:"price_range"`
 	DurationRange   DurationRange `json:"duration_range"`
 	FilterCriteria  FilterCriteria `json:"filter_criteria"`
+	DuplicatesRemoved     int                             `json:"duplicates_removed"`
+	ProviderContributions map[string]ProviderContribution `json:"provider_contributions,omitempty"`
+	FaresWithoutRate      int                             `json:"fares_without_rate,omitempty"` // provider fares left out because they could not be converted to the base currency
+	TaggedFlights         map[string]uuid.UUID            `json:"tagged_flights,omitempty"` // flight tag to flight ID
+	ParetoFrontierSize    int                             `json:"pareto_frontier_size"`
+}
+
+// StreamProviderEvent reports one provider's answer during a streamed search
+type StreamProviderEvent struct {
+	Provider    string `json:"provider"`
+	ResultCount int    `json:"result_count"`
+	Error       string `json:"error,omitempty"`
+	ElapsedMs   int64  `json:"elapsed_ms"`
+}
+
+// StreamResultsEvent carries the merged and sorted results of a streamed search so far
+type StreamResultsEvent struct {
+	SearchID     uuid.UUID `json:"search_id"`
+	Flights      []Flight  `json:"flights"`
+	TotalResults int       `json:"total_results"`
+	Final        bool      `json:"final"`
+}
+
+// StreamSummaryEvent closes a streamed search
+type StreamSummaryEvent struct {
+	SearchID       uuid.UUID      `json:"search_id"`
+	SearchMetadata SearchMetadata `json:"search_metadata"`
+	Resumed        bool           `json:"resumed"`
+}
+
+// ProviderContribution summarises what a provider added to a merged result set
+type ProviderContribution struct {
+	Offers   int `json:"offers"`   // flights the provider returned
+	Unique   int `json:"unique"`   // merged flights only this provider offered
+	Cheapest int `json:"cheapest"` // merged flights where this provider had the winning fare
 }
 
 // PriceRange represents the price range of search results
@@ -147,6 +329,7 @@ type TimeRange struct {
 
 // SearchFilter represents search result filters
 type SearchFilter struct {
+	SearchID          uuid.UUID        `json:"search_id" binding:"required"`
 	MaxPrice          *decimal.Decimal `json:"max_price,omitempty"`
 	MinPrice          *decimal.Decimal `json:"min_price,omitempty"`
 	MaxDuration       *int             `json:"max_duration_minutes,omitempty"`
@@ -160,6 +343,7 @@ type SearchFilter struct {
 	ArrivalTimeTo     *time.Time       `json:"arrival_time_to,omitempty"`
 	SortBy            string           `json:"sort_by"` // "price", "duration", "departure_time", "relevance"
 	SortOrder         string           `json:"sort_order"` // "asc", "desc"
+	Currency          string           `json:"currency,omitempty"` // display currency; defaults to the search's, prices filter in it
 	Limit             int              `json:"limit"`
 	Offset            int              `json:"offset"`
 }
@@ -173,6 +357,22 @@ type AirportSuggestion struct {
 	CountryCode string  `json:"country_code"`
 	Relevance   float64 `json:"relevance"`
 	Type        string  `json:"type"` // "airport", "city", "country"
+	CityCode    string    `json:"city_code,omitempty"` // IATA metropolitan code, e.g. "LON"
+	Coordinates *GeoPoint `json:"coordinates,omitempty"`
+}
+
+// AirportPickRequest records which autocomplete suggestion a user picked
+type AirportPickRequest struct {
+	Query    string `json:"query" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Type     string `json:"type"`     // "airport" (default) or "city"
+	Position int    `json:"position"` // zero-based position in the suggestion list
+}
+
+// GeoPoint is a latitude/longitude pair stored as an Elasticsearch geo_point
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 // SearchSession represents a user's search session
@@ -203,6 +403,73 @@ type SearchHistory struct {
 	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
 }
 
+// ShareLinkRequest asks for a share link to a search, or to one flight within it
+type ShareLinkRequest struct {
+	SearchID uuid.UUID  `json:"search_id" binding:"required"`
+	FlightID *uuid.UUID `json:"flight_id,omitempty"`
+}
+
+// ShareLink is a signed, expiring link to a search or flight
+type ShareLink struct {
+	Token     string     `json:"token"`
+	URL       string     `json:"url"`
+	SearchID  uuid.UUID  `json:"search_id"`
+	FlightID  *uuid.UUID `json:"flight_id,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// SharedSearch is what a share link resolves to. Flight links carry only the shared flight.
+type SharedSearch struct {
+	SearchID  uuid.UUID             `json:"search_id"`
+	FlightID  *uuid.UUID            `json:"flight_id,omitempty"`
+	ExpiresAt time.Time             `json:"expires_at"`
+	Search    *FlightSearchResponse `json:"search,omitempty"`
+	Request   *FlightSearchRequest  `json:"request,omitempty"`
+	Flight    *Flight               `json:"flight,omitempty"`
+}
+
+// SearchReplay is a past search run again, with its differences from the original
+type SearchReplay struct {
+	OriginalSearchID uuid.UUID            `json:"original_search_id"`
+	Search           *FlightSearchResponse `json:"search"`
+	Diff             *SearchDiff           `json:"diff"`
+}
+
+// SearchDiff compares the results of a replayed search with the original search
+type SearchDiff struct {
+	OriginalSearchID         uuid.UUID          `json:"original_search_id"`
+	ReplaySearchID           uuid.UUID          `json:"replay_search_id"`
+	OriginalBestPrice        *decimal.Decimal   `json:"original_best_price,omitempty"`
+	ReplayBestPrice          *decimal.Decimal   `json:"replay_best_price,omitempty"`
+	BestPriceChange          *decimal.Decimal   `json:"best_price_change,omitempty"` // replay minus original
+	Currency                 string             `json:"currency"`
+	OriginalFlightsAvailable bool               `json:"original_flights_available"` // false once the original results have expired
+	NewFlights               []Flight           `json:"new_flights"`
+	RemovedFlights           []Flight           `json:"removed_flights"`
+	PriceChanges             []FlightPriceDelta `json:"price_changes"`
+	Unchanged                int                `json:"unchanged"`
+}
+
+// FlightPriceDelta is the price change of an itinerary present in both searches
+type FlightPriceDelta struct {
+	Flight        Flight          `json:"flight"`
+	OriginalPrice decimal.Decimal `json:"original_price"`
+	ReplayPrice   decimal.Decimal `json:"replay_price"`
+	Delta         decimal.Decimal `json:"delta"`
+}
+
+// AdminAuditEntry records an admin action, who performed it and how it ended
+type AdminAuditEntry struct {
+	ID         uuid.UUID              `json:"id" db:"id"`
+	ActorID    uuid.UUID              `json:"actor_id" db:"actor_id"`
+	ActorEmail string                 `json:"actor_email" db:"actor_email"`
+	Action     string                 `json:"action" db:"action"`
+	Parameters map[string]interface{} `json:"parameters" db:"parameters"` // path, query and body parameters
+	StatusCode int                    `json:"status_code" db:"status_code"`
+	IPAddress  string                 `json:"ip_address" db:"ip_address"`
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+}
+
 // CacheStats represents cache statistics
 type CacheStats struct {
 	TotalKeys       int64   `json:"total_keys"`
@@ -254,4 +521,61 @@ type FlightSuggestion struct {
 	DistanceKM         int    `json:"distance_km"`
 	IsDirect           bool   `json:"is_direct"`
 	TypicalStops       int    `json:"typical_stops"`
+}
+
+// ExploreRequest asks for the cheapest destinations reachable from an origin
+type ExploreRequest struct {
+	OriginAirport      string          `json:"origin_airport"`
+	DateFrom           time.Time       `json:"date_from"`
+	DateTo             time.Time       `json:"date_to"`
+	MaxBudget          decimal.Decimal `json:"max_budget"`
+	Currency           string          `json:"currency"` // of the budget and the returned prices, defaults to the FX base
+	MinDurationMinutes int             `json:"min_duration_minutes"`
+	MaxDurationMinutes int             `json:"max_duration_minutes"`
+	DirectOnly         bool            `json:"direct_only"`
+	Limit              int             `json:"limit"`
+}
+
+// ExploreDestination is a destination within budget and its cheapest known fare
+type ExploreDestination struct {
+	DestinationAirport string          `json:"destination_airport"`
+	DurationMinutes    int             `json:"duration_minutes"`
+	DistanceKM         int             `json:"distance_km"`
+	IsDirect           bool            `json:"is_direct"`
+	Price              decimal.Decimal `json:"price"`
+	Currency           string          `json:"currency"`
+	BestDate           *time.Time      `json:"best_date,omitempty"` // nil when only an indicative historical price is known
+	PriceSource        string          `json:"price_source"`        // "live" or "history"
+}
+
+// ExploreResponse lists destinations ranked by price
+type ExploreResponse struct {
+	OriginAirport string               `json:"origin_airport"`
+	DateFrom      time.Time            `json:"date_from"`
+	DateTo        time.Time            `json:"date_to"`
+	MaxBudget     decimal.Decimal      `json:"max_budget"`
+	Currency      string               `json:"currency"`
+	Destinations  []ExploreDestination `json:"destinations"`
+	Candidates    int                  `json:"candidates"` // reachable destinations considered
+	FromCache     bool                 `json:"from_cache"`
+}
+
+// ConnectionPath is an itinerary built from direct routes, changing planes at each hub
+type ConnectionPath struct {
+	Via               []string           `json:"via"`
+	Stops             int                `json:"stops"`
+	Segments          []FlightSuggestion `json:"segments"`
+	FlightMinutes     int                `json:"flight_minutes"`
+	ConnectionMinutes int                `json:"connection_minutes"` // minimum connection time at every hub
+	TotalMinutes      int                `json:"total_minutes"`
+	DistanceKM        int                `json:"distance_km"`
+}
+
+// ConnectionPaths lists the itineraries found between two airports, shortest first
+type ConnectionPaths struct {
+	OriginAirport      string           `json:"origin_airport"`
+	DestinationAirport string           `json:"destination_airport"`
+	MaxStops           int              `json:"max_stops"`
+	DirectAvailable    bool             `json:"direct_available"`
+	Paths              []ConnectionPath `json:"paths"`
 }
\ No newline at end of file
@@ -0,0 +1,741 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+	sharedErrors "spontra/shared/errors"
+)
+
+const dataIngestionSearchPath = "/api/v1/search/flights"
+
+// DataIngestionClient calls data-ingestion-service's flight search endpoint
+type DataIngestionClient struct {
+	name       string
+	baseURL    string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewDataIngestionClient creates a new data-ingestion provider client.
+// name is the provider label reported on flights and in search metadata.
+func NewDataIngestionClient(name, baseURL string, timeout time.Duration) *DataIngestionClient {
+	return &DataIngestionClient{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		timeout: timeout,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// diSearchRequest mirrors data-ingestion-service's FlightSearchRequest
+type diSearchRequest struct {
+	ID              string     `json:"id"`
+	OriginCode      string     `json:"origin_code"`
+	DestinationCode string     `json:"destination_code"`
+	DepartureDate   time.Time  `json:"departure_date"`
+	ReturnDate      *time.Time `json:"return_date,omitempty"`
+	Adults          int        `json:"adults"`
+	CabinClass      string     `json:"cabin_class"`
+	Currency        string     `json:"currency"`
+	MaxResults      int        `json:"max_results"`
+	Legs            []diSearchLeg `json:"legs,omitempty"`
+}
+
+// diSearchLeg mirrors data-ingestion-service's SearchLeg
+type diSearchLeg struct {
+	OriginCode      string    `json:"origin_code"`
+	DestinationCode string    `json:"destination_code"`
+	DepartureDate   time.Time `json:"departure_date"`
+}
+
+// diSearchResponse mirrors data-ingestion-service's FlightSearchResponse
+type diSearchResponse struct {
+	ID           string          `json:"id"`
+	Provider     string          `json:"provider"`
+	FlightOffers []diFlightOffer `json:"flight_offers"`
+	TotalResults int             `json:"total_results"`
+	Currency     string          `json:"currency"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	Errors       []diSearchError `json:"errors,omitempty"`
+}
+
+type diFlightOffer struct {
+	ID                     string              `json:"id"`
+	Source                 string              `json:"source"`
+	LastTicketingDate      *time.Time          `json:"last_ticketing_date,omitempty"`
+	NumberOfBookableSeats  int                 `json:"number_of_bookable_seats"`
+	Itineraries            []diItinerary       `json:"itineraries"`
+	Price                  diPrice             `json:"price"`
+	PricingOptions         diPricingOptions    `json:"pricing_options"`
+	ValidatingAirlineCodes []string            `json:"validating_airline_codes"`
+	TravelerPricings       []diTravelerPricing `json:"traveler_pricings"`
+	BookingUrl             string              `json:"booking_url,omitempty"`
+	DeepLink               string              `json:"deep_link,omitempty"`
+}
+
+type diItinerary struct {
+	Duration string      `json:"duration"`
+	Segments []diSegment `json:"segments"`
+}
+
+type diSegment struct {
+	ID          string           `json:"id"`
+	Departure   diFlightEndpoint `json:"departure"`
+	Arrival     diFlightEndpoint `json:"arrival"`
+	CarrierCode string           `json:"carrier_code"`
+	Number      string           `json:"number"`
+	Aircraft    diAircraft       `json:"aircraft"`
+	Duration    string           `json:"duration"`
+	Stops       []diStop         `json:"stops,omitempty"`
+}
+
+type diFlightEndpoint struct {
+	IataCode string    `json:"iata_code"`
+	Terminal string    `json:"terminal,omitempty"`
+	At       time.Time `json:"at"`
+	Airport  diAirport `json:"airport"`
+}
+
+type diAirport struct {
+	IataCode string `json:"iata_code"`
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	Country  string `json:"country"`
+}
+
+type diAircraft struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+type diStop struct {
+	IataCode    string    `json:"iata_code"`
+	Duration    string    `json:"duration"`
+	ArrivalAt   time.Time `json:"arrival_at"`
+	DepartureAt time.Time `json:"departure_at"`
+	Airport     diAirport `json:"airport"`
+}
+
+type diPrice struct {
+	Currency   string          `json:"currency"`
+	Total      decimal.Decimal `json:"total"`
+	Base       decimal.Decimal `json:"base"`
+	Taxes      []diAmount      `json:"taxes"`
+	Fees       []diAmount      `json:"fees,omitempty"`
+	GrandTotal decimal.Decimal `json:"grand_total"`
+	AdditionalServices []diAdditionalService `json:"additional_services,omitempty"`
+}
+
+type diAmount struct {
+	Amount decimal.Decimal `json:"amount"`
+}
+
+type diAdditionalService struct {
+	Amount decimal.Decimal `json:"amount"`
+	Type   string          `json:"type"`
+}
+
+type diPricingOptions struct {
+	IncludedCheckedBagsOnly bool `json:"included_checked_bags_only"`
+}
+
+type diTravelerPricing struct {
+	TravelerID           string          `json:"traveler_id"`
+	FareDetailsBySegment []diFareDetails `json:"fare_details_by_segment"`
+}
+
+type diFareDetails struct {
+	SegmentID           string         `json:"segment_id"`
+	Cabin               string         `json:"cabin"`
+	BrandedFare         string         `json:"branded_fare,omitempty"`
+	BrandedFareLabel    string         `json:"branded_fare_label,omitempty"`
+	IncludedCheckedBags *diCheckedBags `json:"included_checked_bags,omitempty"`
+	IncludedCabinBags   *diCabinBags   `json:"included_cabin_bags,omitempty"`
+	Amenities           []diAmenity    `json:"amenities,omitempty"`
+}
+
+type diCheckedBags struct {
+	Quantity int `json:"quantity"`
+}
+
+type diCabinBags struct {
+	Quantity int `json:"quantity"`
+}
+
+type diAmenity struct {
+	Description  string `json:"description"`
+	AmenityType  string `json:"amenity_type"`
+	IsChargeable bool   `json:"is_chargeable"`
+}
+
+type diSearchError struct {
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+	Status int    `json:"status"`
+}
+
+// Name returns the provider name
+func (c *DataIngestionClient) Name() string {
+	return c.name
+}
+
+// Capabilities reports that data-ingestion prices return trips as a single offer
+func (c *DataIngestionClient) Capabilities() Capabilities {
+	return Capabilities{MultiCity: true, RoundTrip: true}
+}
+
+// Health checks data-ingestion-service's health endpoint
+func (c *DataIngestionClient) Health(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return sharedErrors.InternalError("failed to create data-ingestion health request", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return c.unavailableError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return sharedErrors.UnavailableError(c.name, fmt.Sprintf("%s health check returned HTTP %d", c.name, resp.StatusCode))
+	}
+	return nil
+}
+
+// Search queries data-ingestion-service and converts its offers into flights
+func (c *DataIngestionClient) Search(ctx context.Context, req *models.FlightSearchRequest) ([]models.Flight, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(c.buildRequest(req))
+	if err != nil {
+		return nil, sharedErrors.InternalError("failed to encode data-ingestion search request", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+dataIngestionSearchPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, sharedErrors.InternalError("failed to create data-ingestion search request", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, c.timeoutError(err)
+		}
+		return nil, c.unavailableError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, c.timeoutError(err)
+		}
+		return nil, sharedErrors.ExternalServiceError(c.name, "failed to read data-ingestion response", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.statusError(resp.StatusCode, respBody)
+	}
+
+	var searchResp diSearchResponse
+	if err := json.Unmarshal(respBody, &searchResp); err != nil {
+		return nil, sharedErrors.NewError(sharedErrors.ErrorTypeBadGateway, "INVALID_PROVIDER_RESPONSE", "data-ingestion returned an invalid response").
+			WithDetail("upstream_service", c.name).
+			WithCause(err).
+			Build()
+	}
+
+	if len(searchResp.FlightOffers) == 0 && len(searchResp.Errors) > 0 {
+		first := searchResp.Errors[0]
+		return nil, sharedErrors.NewError(sharedErrors.ErrorTypeExternal, sharedErrors.ErrCodeAmadeusUnavailable,
+			fmt.Sprintf("%s reported error %s: %s", c.name, first.Code, first.Title)).
+			WithDetail("external_service", c.name).
+			WithDetail("provider_errors", searchResp.Errors).
+			Build()
+	}
+
+	return c.convertOffers(&searchResp, req), nil
+}
+
+// buildRequest converts a search-service request into data-ingestion's format
+func (c *DataIngestionClient) buildRequest(req *models.FlightSearchRequest) *diSearchRequest {
+	maxResults := req.MaxResults
+	if maxResults <= 0 || maxResults > 250 {
+		maxResults = 250
+	}
+
+	diReq := &diSearchRequest{
+		ID:              req.ID.String(),
+		OriginCode:      strings.ToUpper(req.OriginAirport),
+		DestinationCode: strings.ToUpper(req.DestinationAirport),
+		DepartureDate:   req.DepartureDate,
+		Adults:          req.PassengerCount,
+		CabinClass:      toProviderCabin(req.CabinClass),
+		Currency:        "EUR",
+		MaxResults:      maxResults,
+	}
+	if req.TripType == "return" && req.ReturnDate != nil {
+		diReq.ReturnDate = req.ReturnDate
+	}
+	for _, leg := range req.Legs {
+		diReq.Legs = append(diReq.Legs, diSearchLeg{
+			OriginCode:      strings.ToUpper(leg.OriginAirport),
+			DestinationCode: strings.ToUpper(leg.DestinationAirport),
+			DepartureDate:   leg.DepartureDate,
+		})
+	}
+	if diReq.Adults < 1 {
+		diReq.Adults = 1
+	}
+
+	return diReq
+}
+
+// convertOffers converts data-ingestion flight offers into search-service flights
+func (c *DataIngestionClient) convertOffers(resp *diSearchResponse, req *models.FlightSearchRequest) []models.Flight {
+	flights := make([]models.Flight, 0, len(resp.FlightOffers))
+	for i := range resp.FlightOffers {
+		offer := &resp.FlightOffers[i]
+		if len(offer.Itineraries) == 0 || len(offer.Itineraries[0].Segments) == 0 {
+			continue
+		}
+
+		flight := c.convertItinerary(&offer.Itineraries[0], offer)
+		flight.Price = offerTotal(&offer.Price)
+		flight.Currency = offerCurrency(&offer.Price, resp.Currency)
+		flight.PriceBreakdown = buildPriceBreakdown(&offer.Price, flight.Currency, req.PassengerCount)
+		flight.BaggageIncluded = offer.PricingOptions.IncludedCheckedBagsOnly || hasCheckedBags(offer)
+		flight.FareFamily = offerFareFamily(offer)
+		flight.Ancillaries = offerAncillaries(&offer.Price, req.PassengerCount)
+		flight.IsRefundable = flight.FareFamily != nil && flight.FareFamily.Refunds == models.FareConditionIncluded
+		flight.BookingURL = offer.BookingUrl
+		flight.BookingDeepLink = offer.DeepLink
+		flight.ValidUntil = resp.ExpiresAt
+		if offer.LastTicketingDate != nil && (flight.ValidUntil.IsZero() || offer.LastTicketingDate.Before(flight.ValidUntil)) {
+			flight.ValidUntil = *offer.LastTicketingDate
+		}
+		if offer.NumberOfBookableSeats > 0 {
+			seats := offer.NumberOfBookableSeats
+			flight.SeatsAvailable = &seats
+		}
+
+		if len(req.Legs) > 0 {
+			if !c.attachLegs(&flight, offer, len(req.Legs)) {
+				continue
+			}
+		} else if len(offer.Itineraries) > 1 && len(offer.Itineraries[1].Segments) > 0 {
+			returnFlight := c.convertItinerary(&offer.Itineraries[1], offer)
+			returnFlight.Currency = flight.Currency
+			returnFlight.BaggageIncluded = flight.BaggageIncluded
+			returnFlight.ValidUntil = flight.ValidUntil
+			flight.ReturnFlight = &returnFlight
+		}
+
+		flights = append(flights, flight)
+	}
+
+	return flights
+}
+
+// attachLegs turns a multi-city offer into one flight spanning every leg.
+// It returns false if the offer does not cover each requested leg.
+func (c *DataIngestionClient) attachLegs(flight *models.Flight, offer *diFlightOffer, legCount int) bool {
+	if len(offer.Itineraries) != legCount {
+		return false
+	}
+
+	legs := make([]models.Flight, 0, legCount)
+	for i := range offer.Itineraries {
+		if len(offer.Itineraries[i].Segments) == 0 {
+			return false
+		}
+		leg := c.convertItinerary(&offer.Itineraries[i], offer)
+		leg.Currency = flight.Currency
+		leg.BaggageIncluded = flight.BaggageIncluded
+		leg.ValidUntil = flight.ValidUntil
+		legs = append(legs, leg)
+	}
+
+	// The itinerary runs from the first leg's departure to the last leg's arrival
+	last := legs[len(legs)-1]
+	flight.DestinationAirport = last.DestinationAirport
+	flight.ArrivalTime = last.ArrivalTime
+	flight.Duration, flight.Stops, flight.StopDetails = 0, 0, nil
+	for _, leg := range legs {
+		flight.Duration += leg.Duration
+		flight.Stops += leg.Stops
+		flight.StopDetails = append(flight.StopDetails, leg.StopDetails...)
+	}
+	flight.Legs = legs
+
+	return true
+}
+
+// convertItinerary converts a single itinerary into a flight without pricing
+func (c *DataIngestionClient) convertItinerary(itinerary *diItinerary, offer *diFlightOffer) models.Flight {
+	segments := itinerary.Segments
+	first := segments[0]
+	last := segments[len(segments)-1]
+
+	flightNumbers := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		flightNumbers = append(flightNumbers, segment.CarrierCode+segment.Number)
+	}
+
+	airline := first.CarrierCode
+	if len(offer.ValidatingAirlineCodes) > 0 {
+		airline = offer.ValidatingAirlineCodes[0]
+	}
+
+	duration := ParseISODuration(itinerary.Duration)
+	if duration == 0 {
+		duration = int(last.Arrival.At.Sub(first.Departure.At).Minutes())
+	}
+
+	stopDetails := buildStopDetails(segments)
+
+	return models.Flight{
+		ID:                 uuid.New(),
+		Provider:           c.name,
+		OriginAirport:      first.Departure.IataCode,
+		DestinationAirport: last.Arrival.IataCode,
+		DepartureTime:      first.Departure.At,
+		ArrivalTime:        last.Arrival.At,
+		Duration:           duration,
+		CabinClass:         offerCabin(offer, first.ID),
+		Airline:            airline,
+		FlightNumber:       strings.Join(flightNumbers, "/"),
+		Aircraft:           aircraftLabel(first.Aircraft),
+		Stops:              len(stopDetails),
+		StopDetails:        stopDetails,
+	}
+}
+
+// buildStopDetails lists connections between segments and technical stops within them
+func buildStopDetails(segments []diSegment) []models.Stop {
+	var stops []models.Stop
+	for i, segment := range segments {
+		for _, technical := range segment.Stops {
+			stops = append(stops, models.Stop{
+				Airport:       technical.IataCode,
+				City:          technical.Airport.City,
+				Country:       technical.Airport.Country,
+				ArrivalTime:   technical.ArrivalAt,
+				DepartureTime: technical.DepartureAt,
+				Duration:      stopMinutes(technical.Duration, technical.ArrivalAt, technical.DepartureAt),
+			})
+		}
+
+		if i == len(segments)-1 {
+			continue
+		}
+		next := segments[i+1]
+		stops = append(stops, models.Stop{
+			Airport:       segment.Arrival.IataCode,
+			City:          segment.Arrival.Airport.City,
+			Country:       segment.Arrival.Airport.Country,
+			ArrivalTime:   segment.Arrival.At,
+			DepartureTime: next.Departure.At,
+			Duration:      int(next.Departure.At.Sub(segment.Arrival.At).Minutes()),
+			Terminal:      segment.Arrival.Terminal,
+		})
+	}
+
+	return stops
+}
+
+// buildPriceBreakdown splits an offer price into base fare, taxes and fees
+func buildPriceBreakdown(price *diPrice, currency string, passengers int) models.PriceBreakdown {
+	taxes := decimal.Zero
+	for _, tax := range price.Taxes {
+		taxes = taxes.Add(tax.Amount)
+	}
+	fees := decimal.Zero
+	for _, fee := range price.Fees {
+		fees = fees.Add(fee.Amount)
+	}
+
+	total := offerTotal(price)
+	// Amadeus only itemises some taxes; attribute the remainder to taxes so the parts add up
+	if remainder := total.Sub(price.Base).Sub(taxes).Sub(fees); remainder.IsPositive() {
+		taxes = taxes.Add(remainder)
+	}
+
+	if passengers < 1 {
+		passengers = 1
+	}
+
+	return models.PriceBreakdown{
+		BaseFare:    price.Base,
+		Taxes:       taxes,
+		Fees:        fees,
+		Total:       total,
+		Currency:    currency,
+		PricePerPax: total.Div(decimal.NewFromInt(int64(passengers))).Round(2),
+	}
+}
+
+// offerTotal returns the amount the traveller pays for an offer
+func offerTotal(price *diPrice) decimal.Decimal {
+	if price.GrandTotal.IsPositive() {
+		return price.GrandTotal
+	}
+	return price.Total
+}
+
+// offerCurrency returns the offer currency, falling back to the response currency
+func offerCurrency(price *diPrice, fallback string) string {
+	if price.Currency != "" {
+		return price.Currency
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return "EUR"
+}
+
+// offerCabin returns the lower-case cabin of the given segment for the first traveller
+func offerCabin(offer *diFlightOffer, segmentID string) string {
+	if len(offer.TravelerPricings) == 0 {
+		return "economy"
+	}
+	details := offer.TravelerPricings[0].FareDetailsBySegment
+	for _, detail := range details {
+		if detail.SegmentID == segmentID && detail.Cabin != "" {
+			return strings.ToLower(detail.Cabin)
+		}
+	}
+	if len(details) > 0 && details[0].Cabin != "" {
+		return strings.ToLower(details[0].Cabin)
+	}
+	return "economy"
+}
+
+// hasCheckedBags reports whether every priced segment includes a checked bag
+func hasCheckedBags(offer *diFlightOffer) bool {
+	if len(offer.TravelerPricings) == 0 || len(offer.TravelerPricings[0].FareDetailsBySegment) == 0 {
+		return false
+	}
+	for _, detail := range offer.TravelerPricings[0].FareDetailsBySegment {
+		if detail.IncludedCheckedBags == nil || detail.IncludedCheckedBags.Quantity < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// offerFareFamily describes the first traveller's fare. Bag allowances are the smallest on any
+// segment, so they hold for the whole trip.
+func offerFareFamily(offer *diFlightOffer) *models.FareFamily {
+	if len(offer.TravelerPricings) == 0 || len(offer.TravelerPricings[0].FareDetailsBySegment) == 0 {
+		return nil
+	}
+	details := offer.TravelerPricings[0].FareDetailsBySegment
+
+	family := &models.FareFamily{
+		Code:        details[0].BrandedFare,
+		Name:        details[0].BrandedFareLabel,
+		CheckedBags: checkedBagAllowance(&details[0]),
+		CabinBags:   cabinBagAllowance(&details[0]),
+	}
+	for i := range details[1:] {
+		detail := &details[i+1]
+		if bags := checkedBagAllowance(detail); bags < family.CheckedBags {
+			family.CheckedBags = bags
+		}
+		if bags := cabinBagAllowance(detail); bags < family.CabinBags {
+			family.CabinBags = bags
+		}
+	}
+
+	for _, amenity := range details[0].Amenities {
+		condition := models.FareConditionIncluded
+		if amenity.IsChargeable {
+			condition = models.FareConditionChargeable
+		}
+
+		description := strings.ToUpper(amenity.Description)
+		switch {
+		case amenity.AmenityType == "PRE_RESERVED_SEAT":
+			family.SeatSelection = mergeFareCondition(family.SeatSelection, condition)
+		case strings.Contains(description, "REFUND"):
+			family.Refunds = mergeFareCondition(family.Refunds, condition)
+		case strings.Contains(description, "CHANGE"):
+			family.Changes = mergeFareCondition(family.Changes, condition)
+		}
+	}
+
+	return family
+}
+
+// checkedBagAllowance returns the checked bags a segment's fare includes
+func checkedBagAllowance(detail *diFareDetails) int {
+	if detail.IncludedCheckedBags == nil {
+		return 0
+	}
+	return detail.IncludedCheckedBags.Quantity
+}
+
+// cabinBagAllowance returns the cabin bags a segment's fare includes. Amadeus only reports the
+// allowance for some carriers; without it the usual single cabin bag is assumed.
+func cabinBagAllowance(detail *diFareDetails) int {
+	if detail.IncludedCabinBags == nil {
+		return 1
+	}
+	return detail.IncludedCabinBags.Quantity
+}
+
+// mergeFareCondition keeps the better of two conditions for the same service
+func mergeFareCondition(current, next string) string {
+	if current == models.FareConditionIncluded {
+		return current
+	}
+	return next
+}
+
+// offerAncillaries lists the extras priced with an offer. Amadeus quotes each extra for all
+// travellers, so prices are split per passenger.
+func offerAncillaries(price *diPrice, passengers int) []models.Ancillary {
+	if passengers < 1 {
+		passengers = 1
+	}
+
+	var ancillaries []models.Ancillary
+	for _, service := range price.AdditionalServices {
+		var ancillaryType string
+		switch service.Type {
+		case "CHECKED_BAGS":
+			ancillaryType = models.AncillaryCheckedBag
+		case "SEATS":
+			ancillaryType = models.AncillarySeat
+		default:
+			continue
+		}
+		ancillaries = append(ancillaries, models.Ancillary{
+			Type:  ancillaryType,
+			Price: service.Amount.Div(decimal.NewFromInt(int64(passengers))).Round(2),
+		})
+	}
+
+	return ancillaries
+}
+
+// aircraftLabel prefers the aircraft name over its code
+func aircraftLabel(aircraft diAircraft) string {
+	if aircraft.Name != "" {
+		return aircraft.Name
+	}
+	return aircraft.Code
+}
+
+// toProviderCabin maps search-service cabin names onto data-ingestion's enum
+func toProviderCabin(cabin string) string {
+	switch strings.ToLower(strings.TrimSpace(cabin)) {
+	case "premium_economy", "premium economy", "premium":
+		return "PREMIUM_ECONOMY"
+	case "business":
+		return "BUSINESS"
+	case "first":
+		return "FIRST"
+	default:
+		return "ECONOMY"
+	}
+}
+
+// stopMinutes returns a stop duration, preferring the ISO duration when present
+func stopMinutes(isoDuration string, arrival, departure time.Time) int {
+	if minutes := ParseISODuration(isoDuration); minutes > 0 {
+		return minutes
+	}
+	return int(departure.Sub(arrival).Minutes())
+}
+
+// ParseISODuration parses ISO 8601 flight durations (e.g. "PT2H30M", "P1DT1H") into minutes
+func ParseISODuration(duration string) int {
+	duration = strings.TrimPrefix(strings.ToUpper(duration), "P")
+	totalMinutes := 0
+
+	if dIndex := strings.Index(duration, "D"); dIndex != -1 {
+		if days, err := strconv.Atoi(duration[:dIndex]); err == nil {
+			totalMinutes += days * 24 * 60
+		}
+		duration = duration[dIndex+1:]
+	}
+	duration = strings.TrimPrefix(duration, "T")
+
+	if hIndex := strings.Index(duration, "H"); hIndex != -1 {
+		if hours, err := strconv.Atoi(duration[:hIndex]); err == nil {
+			totalMinutes += hours * 60
+		}
+		duration = duration[hIndex+1:]
+	}
+	if mIndex := strings.Index(duration, "M"); mIndex != -1 {
+		if minutes, err := strconv.Atoi(duration[:mIndex]); err == nil {
+			totalMinutes += minutes
+		}
+	}
+
+	return totalMinutes
+}
+
+// timeoutError builds the error returned when the provider exceeds its timeout
+func (c *DataIngestionClient) timeoutError(cause error) *sharedErrors.AppError {
+	err := sharedErrors.TimeoutError(c.name+" search", c.timeout)
+	err.Cause = cause
+	return err
+}
+
+// unavailableError builds the error returned when the provider cannot be reached
+func (c *DataIngestionClient) unavailableError(cause error) *sharedErrors.AppError {
+	err := sharedErrors.UnavailableError(c.name, fmt.Sprintf("%s is unreachable", c.name))
+	err.Cause = cause
+	return err
+}
+
+// statusError maps a non-200 provider response onto a typed error
+func (c *DataIngestionClient) statusError(statusCode int, body []byte) *sharedErrors.AppError {
+	message := fmt.Sprintf("%s returned HTTP %d", c.name, statusCode)
+	var errBody struct {
+		Error   string `json:"error"`
+		Details string `json:"details"`
+	}
+	if json.Unmarshal(body, &errBody) == nil && errBody.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, errBody.Error)
+		if errBody.Details != "" {
+			message = fmt.Sprintf("%s (%s)", message, errBody.Details)
+		}
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return sharedErrors.NewError(sharedErrors.ErrorTypeRateLimit, sharedErrors.ErrCodeAmadeusRateLimit, message).
+			WithDetail("upstream_service", c.name).
+			WithRetryable(true).
+			Build()
+	case statusCode == http.StatusBadRequest:
+		return sharedErrors.ValidationError(message, map[string]interface{}{"upstream_service": c.name})
+	case statusCode == http.StatusServiceUnavailable || statusCode == http.StatusGatewayTimeout:
+		return sharedErrors.UnavailableError(c.name, message)
+	default:
+		return sharedErrors.BadGatewayError(c.name, message)
+	}
+}
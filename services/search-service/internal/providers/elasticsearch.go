@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"context"
+
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/models"
+)
+
+// ElasticsearchProvider serves previously indexed flight offers
+type ElasticsearchProvider struct {
+	client *elasticsearch.Client
+}
+
+// NewElasticsearchProvider creates a provider backed by the flight index
+func NewElasticsearchProvider(client *elasticsearch.Client) *ElasticsearchProvider {
+	return &ElasticsearchProvider{client: client}
+}
+
+// Name returns the provider name
+func (p *ElasticsearchProvider) Name() string {
+	return "elasticsearch"
+}
+
+// Capabilities reports that the index answers date-window queries natively
+func (p *ElasticsearchProvider) Capabilities() Capabilities {
+	return Capabilities{FlexibleDates: true}
+}
+
+// Health checks that the cluster is reachable and not red
+func (p *ElasticsearchProvider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+// Search queries the flight index
+func (p *ElasticsearchProvider) Search(ctx context.Context, req *models.FlightSearchRequest) ([]models.Flight, error) {
+	response, err := p.client.SearchFlights(req)
+	if err != nil {
+		return nil, err
+	}
+	return response.Flights, nil
+}
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"spontra/search-service/internal/models"
+)
+
+// FakeProvider is an in-memory FlightProvider for exercising orchestration without network access
+type FakeProvider struct {
+	name         string
+	capabilities Capabilities
+
+	mu        sync.RWMutex
+	flights   []models.Flight
+	latency   time.Duration
+	err       error
+	healthErr error
+	calls     int
+}
+
+// NewFakeProvider creates a fake provider serving the given flights
+func NewFakeProvider(name string, flights ...models.Flight) *FakeProvider {
+	return &FakeProvider{
+		name:         name,
+		capabilities: Capabilities{FlexibleDates: true, RoundTrip: true},
+		flights:      flights,
+	}
+}
+
+// Name returns the provider name
+func (f *FakeProvider) Name() string {
+	return f.name
+}
+
+// Capabilities returns the configured capabilities
+func (f *FakeProvider) Capabilities() Capabilities {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.capabilities
+}
+
+// Health returns the configured health error
+func (f *FakeProvider) Health(ctx context.Context) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.healthErr
+}
+
+// Search returns the stored flights matching the request route and departure date
+func (f *FakeProvider) Search(ctx context.Context, req *models.FlightSearchRequest) ([]models.Flight, error) {
+	f.mu.Lock()
+	f.calls++
+	latency, err := f.latency, f.err
+	f.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var matched []models.Flight
+	for _, flight := range f.flights {
+		if !strings.EqualFold(flight.OriginAirport, req.OriginAirport) ||
+			!strings.EqualFold(flight.DestinationAirport, req.DestinationAirport) {
+			continue
+		}
+		if !fakeDateMatches(flight.DepartureTime, req) {
+			continue
+		}
+		flight.Provider = f.name
+		matched = append(matched, flight)
+	}
+
+	return matched, nil
+}
+
+// SetFlights replaces the flights served by the provider
+func (f *FakeProvider) SetFlights(flights ...models.Flight) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flights = flights
+}
+
+// SetLatency delays every search by the given duration
+func (f *FakeProvider) SetLatency(latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = latency
+}
+
+// SetError makes every search fail with err; pass nil to clear it
+func (f *FakeProvider) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// SetHealthError makes health checks fail with err; pass nil to clear it
+func (f *FakeProvider) SetHealthError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.healthErr = err
+}
+
+// SetCapabilities overrides the advertised capabilities
+func (f *FakeProvider) SetCapabilities(capabilities Capabilities) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.capabilities = capabilities
+}
+
+// Calls returns how many searches the provider has served
+func (f *FakeProvider) Calls() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.calls
+}
+
+// fakeDateMatches checks a departure against the requested date or flexible window
+func fakeDateMatches(departure time.Time, req *models.FlightSearchRequest) bool {
+	if req.DepartureDate.IsZero() {
+		return true
+	}
+
+	day := time.Date(req.DepartureDate.Year(), req.DepartureDate.Month(), req.DepartureDate.Day(), 0, 0, 0, 0, req.DepartureDate.Location())
+	from, to := day, day.Add(24*time.Hour)
+	if req.FlexibleDates && req.FlexibleDatesRange > 0 {
+		from = day.AddDate(0, 0, -req.FlexibleDatesRange)
+		to = day.AddDate(0, 0, req.FlexibleDatesRange+1)
+	}
+
+	return !departure.Before(from) && departure.Before(to)
+}
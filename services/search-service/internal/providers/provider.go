@@ -0,0 +1,26 @@
+package providers
+
+import (
+	"context"
+
+	"spontra/search-service/internal/models"
+)
+
+// FlightProvider is a source of flight offers that the search orchestrator can query
+type FlightProvider interface {
+	// Name returns the provider identifier used in config and search metadata
+	Name() string
+	// Search returns the flights matching the request
+	Search(ctx context.Context, req *models.FlightSearchRequest) ([]models.Flight, error)
+	// Health returns an error if the provider cannot currently serve searches
+	Health(ctx context.Context) error
+	// Capabilities describes which request features the provider handles natively
+	Capabilities() Capabilities
+}
+
+// Capabilities describes the search features a provider supports
+type Capabilities struct {
+	FlexibleDates bool `json:"flexible_dates"`
+	MultiCity     bool `json:"multi_city"`
+	RoundTrip     bool `json:"round_trip"`
+}
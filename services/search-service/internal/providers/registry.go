@@ -0,0 +1,223 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"spontra/search-service/internal/models"
+	"spontra/shared/circuit"
+	sharedErrors "spontra/shared/errors"
+)
+
+// Options configures how the registry runs a provider
+type Options struct {
+	Timeout  time.Duration
+	Priority int // lower values are queried and merged first
+}
+
+// ProviderStatus reports the health of a registered provider
+type ProviderStatus struct {
+	Name         string                 `json:"name"`
+	Healthy      bool                   `json:"healthy"`
+	Error        string                 `json:"error,omitempty"`
+	Priority     int                    `json:"priority"`
+	Timeout      string                 `json:"timeout"`
+	Capabilities Capabilities           `json:"capabilities"`
+	Breaker      map[string]interface{} `json:"circuit_breaker"`
+}
+
+type registeredProvider struct {
+	provider FlightProvider
+	options  Options
+	breaker  *circuit.CircuitBreaker
+}
+
+// Registry holds the flight providers available to the search orchestrator
+type Registry struct {
+	mu             sync.RWMutex
+	providers      map[string]*registeredProvider
+	breakers       *circuit.CircuitBreakerManager
+	defaultTimeout time.Duration
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry(defaultTimeout time.Duration) *Registry {
+	return &Registry{
+		providers:      make(map[string]*registeredProvider),
+		breakers:       circuit.NewManager(),
+		defaultTimeout: defaultTimeout,
+	}
+}
+
+// Register adds a provider, replacing any provider already registered under the same name
+func (r *Registry) Register(provider FlightProvider, options Options) {
+	if options.Timeout <= 0 {
+		options.Timeout = r.defaultTimeout
+	}
+
+	name := provider.Name()
+	breakerConfig := circuit.DefaultConfig(name)
+	breakerConfig.OnStateChange = func(name string, from circuit.State, to circuit.State) {
+		log.Printf("Provider %s circuit breaker changed from %s to %s", name, from, to)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breakers.RemoveBreaker(name)
+	r.providers[name] = &registeredProvider{
+		provider: provider,
+		options:  options,
+		breaker:  r.breakers.GetBreaker(name, breakerConfig),
+	}
+}
+
+// Get returns a registered provider by name
+func (r *Registry) Get(name string) (FlightProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.providers[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.provider, true
+}
+
+// Enabled returns the registered providers among names ordered by priority,
+// along with any names that have no registered provider
+func (r *Registry) Enabled(names []string) ([]FlightProvider, []string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []*registeredProvider
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		entry, ok := r.providers[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].options.Priority < entries[j].options.Priority
+	})
+
+	enabled := make([]FlightProvider, len(entries))
+	for i, entry := range entries {
+		enabled[i] = entry.provider
+	}
+	return enabled, unknown
+}
+
+// Search runs a provider search through its circuit breaker and timeout
+func (r *Registry) Search(ctx context.Context, name string, req *models.FlightSearchRequest) ([]models.Flight, error) {
+	r.mu.RLock()
+	entry, ok := r.providers[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s", name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, entry.options.Timeout)
+	defer cancel()
+
+	type searchResult struct {
+		flights []models.Flight
+		err     error
+	}
+
+	var flights []models.Flight
+	err := entry.breaker.ExecuteWithContext(ctx, func(ctx context.Context) error {
+		// Run the search in its own goroutine so providers that ignore ctx still honour the timeout
+		done := make(chan searchResult, 1)
+		go func() {
+			found, err := entry.provider.Search(ctx, req)
+			done <- searchResult{flights: found, err: err}
+		}()
+
+		select {
+		case result := <-done:
+			flights = result.flights
+			return result.err
+		case <-ctx.Done():
+			timeoutErr := sharedErrors.TimeoutError(name+" search", entry.options.Timeout)
+			timeoutErr.Cause = ctx.Err()
+			return timeoutErr
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flights, nil
+}
+
+// Priority returns the configured priority of a provider
+func (r *Registry) Priority(name string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if entry, ok := r.providers[name]; ok {
+		return entry.options.Priority
+	}
+	return 0
+}
+
+// Status checks the health of every registered provider
+func (r *Registry) Status(ctx context.Context) []ProviderStatus {
+	r.mu.RLock()
+	entries := make([]*registeredProvider, 0, len(r.providers))
+	for _, entry := range r.providers {
+		entries = append(entries, entry)
+	}
+	r.mu.RUnlock()
+
+	statuses := make([]ProviderStatus, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry *registeredProvider) {
+			defer wg.Done()
+
+			healthCtx, cancel := context.WithTimeout(ctx, entry.options.Timeout)
+			defer cancel()
+
+			status := ProviderStatus{
+				Name:         entry.provider.Name(),
+				Healthy:      true,
+				Priority:     entry.options.Priority,
+				Timeout:      entry.options.Timeout.String(),
+				Capabilities: entry.provider.Capabilities(),
+				Breaker:      entry.breaker.Stats(),
+			}
+			if err := entry.provider.Health(healthCtx); err != nil {
+				status.Healthy = false
+				status.Error = err.Error()
+			}
+			statuses[i] = status
+		}(i, entry)
+	}
+	wg.Wait()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Priority != statuses[j].Priority {
+			return statuses[i].Priority < statuses[j].Priority
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
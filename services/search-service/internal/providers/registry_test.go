@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+	sharedErrors "spontra/shared/errors"
+)
+
+var testDeparture = time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+
+// testRequest searches LHR-MAD on the test departure date
+func testRequest() *models.FlightSearchRequest {
+	return &models.FlightSearchRequest{
+		OriginAirport:      "LHR",
+		DestinationAirport: "MAD",
+		DepartureDate:      testDeparture,
+	}
+}
+
+// testFlight builds an LHR-MAD flight on the test departure date
+func testFlight(flightNumber string, price int64) models.Flight {
+	return models.Flight{
+		FlightNumber:       flightNumber,
+		OriginAirport:      "LHR",
+		DestinationAirport: "MAD",
+		DepartureTime:      testDeparture,
+		Price:              decimal.NewFromInt(price),
+		Currency:           "EUR",
+	}
+}
+
+// appErrorCode returns the code of an AppError, or "" for any other error
+func appErrorCode(err error) string {
+	var appErr *sharedErrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+func TestRegistrySearch(t *testing.T) {
+	fake := NewFakeProvider("fake", testFlight("BA456", 120), testFlight("IB3163", 95))
+	registry := NewRegistry(time.Second)
+	registry.Register(fake, Options{})
+
+	flights, err := registry.Search(context.Background(), "fake", testRequest())
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(flights) != 2 {
+		t.Fatalf("Search() returned %d flights, want 2", len(flights))
+	}
+	for _, flight := range flights {
+		if flight.Provider != "fake" {
+			t.Errorf("flight %s Provider = %q, want %q", flight.FlightNumber, flight.Provider, "fake")
+		}
+	}
+
+	if _, err := registry.Search(context.Background(), "missing", testRequest()); err == nil {
+		t.Error("Search() of an unregistered provider returned no error")
+	}
+}
+
+func TestRegistrySearchTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults time.Duration
+		options  Options
+	}{
+		{"provider timeout", time.Minute, Options{Timeout: 20 * time.Millisecond}},
+		{"default timeout", 20 * time.Millisecond, Options{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeProvider("slow", testFlight("BA456", 120))
+			fake.SetLatency(time.Second)
+			registry := NewRegistry(tt.defaults)
+			registry.Register(fake, tt.options)
+
+			start := time.Now()
+			_, err := registry.Search(context.Background(), "slow", testRequest())
+			if code := appErrorCode(err); code != "TIMEOUT" {
+				t.Fatalf("Search() error = %v, want a TIMEOUT error", err)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Search() took %v, want it cut off at the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestRegistrySearchFailure(t *testing.T) {
+	failure := errors.New("upstream unavailable")
+	fake := NewFakeProvider("failing")
+	fake.SetError(failure)
+	registry := NewRegistry(time.Second)
+	registry.Register(fake, Options{})
+
+	flights, err := registry.Search(context.Background(), "failing", testRequest())
+	if !errors.Is(err, failure) {
+		t.Fatalf("Search() error = %v, want %v", err, failure)
+	}
+	if flights != nil {
+		t.Errorf("Search() flights = %v, want none", flights)
+	}
+}
+
+func TestRegistryBreakerOpens(t *testing.T) {
+	fake := NewFakeProvider("flaky", testFlight("BA456", 120))
+	fake.SetError(errors.New("upstream unavailable"))
+	registry := NewRegistry(time.Second)
+	registry.Register(fake, Options{})
+
+	// The default breaker trips after five consecutive failures
+	for i := 0; i < 5; i++ {
+		if _, err := registry.Search(context.Background(), "flaky", testRequest()); err == nil {
+			t.Fatalf("Search() %d returned no error", i+1)
+		}
+	}
+
+	// Once open, the breaker rejects searches without calling the provider, even if it recovered
+	fake.SetError(nil)
+	_, err := registry.Search(context.Background(), "flaky", testRequest())
+	if code := appErrorCode(err); code != "CIRCUIT_BREAKER_OPEN" {
+		t.Fatalf("Search() error = %v, want CIRCUIT_BREAKER_OPEN", err)
+	}
+	if calls := fake.Calls(); calls != 5 {
+		t.Errorf("provider served %d searches, want 5", calls)
+	}
+
+	// Registering the provider again starts it with a closed breaker
+	registry.Register(fake, Options{})
+	if _, err := registry.Search(context.Background(), "flaky", testRequest()); err != nil {
+		t.Errorf("Search() after re-registering error = %v", err)
+	}
+}
+
+func TestRegistryEnabled(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register(NewFakeProvider("amadeus"), Options{Priority: 2})
+	registry.Register(NewFakeProvider("elasticsearch"), Options{Priority: 1})
+	registry.Register(NewFakeProvider("kiwi"), Options{Priority: 2})
+
+	tests := []struct {
+		name        string
+		names       []string
+		wantEnabled []string
+		wantUnknown []string
+	}{
+		{"priority order", []string{"amadeus", "elasticsearch"}, []string{"elasticsearch", "amadeus"}, nil},
+		{"config order on equal priority", []string{"kiwi", "amadeus"}, []string{"kiwi", "amadeus"}, nil},
+		{"unknown and repeated names", []string{"amadeus", "skyscanner", "amadeus"}, []string{"amadeus"}, []string{"skyscanner"}},
+		{"nothing enabled", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabled, unknown := registry.Enabled(tt.names)
+
+			var names []string
+			for _, provider := range enabled {
+				names = append(names, provider.Name())
+			}
+			if !equalNames(names, tt.wantEnabled) {
+				t.Errorf("Enabled(%v) providers = %v, want %v", tt.names, names, tt.wantEnabled)
+			}
+			if !equalNames(unknown, tt.wantUnknown) {
+				t.Errorf("Enabled(%v) unknown = %v, want %v", tt.names, unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestRegistryStatus(t *testing.T) {
+	healthy := NewFakeProvider("healthy")
+	down := NewFakeProvider("down")
+	down.SetHealthError(errors.New("connection refused"))
+
+	registry := NewRegistry(time.Second)
+	registry.Register(down, Options{Priority: 1})
+	registry.Register(healthy, Options{Priority: 0})
+
+	statuses := registry.Status(context.Background())
+	if len(statuses) != 2 {
+		t.Fatalf("Status() returned %d providers, want 2", len(statuses))
+	}
+	if statuses[0].Name != "healthy" || !statuses[0].Healthy {
+		t.Errorf("Status()[0] = %+v, want healthy provider first", statuses[0])
+	}
+	if statuses[1].Name != "down" || statuses[1].Healthy || statuses[1].Error != "connection refused" {
+		t.Errorf("Status()[1] = %+v, want unhealthy provider with its error", statuses[1])
+	}
+}
+
+func equalNames(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/models"
+)
+
+// AuditRepository handles admin audit log data access
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// RecordAdminAction appends an entry to the admin audit log
+func (r *AuditRepository) RecordAdminAction(entry *models.AdminAuditEntry) error {
+	parametersJSON, err := json.Marshal(entry.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit parameters: %w", err)
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO admin_audit_log (id, actor_id, actor_email, action, parameters, status_code, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::inet, $8)`
+
+	_, err = r.db.Exec(query,
+		entry.ID,
+		entry.ActorID,
+		entry.ActorEmail,
+		entry.Action,
+		parametersJSON,
+		entry.StatusCode,
+		entry.IPAddress,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record admin action: %w", err)
+	}
+
+	return nil
+}
+
+// ListAdminActions returns audit log entries, newest first, optionally only those of one actor
+func (r *AuditRepository) ListAdminActions(actorID *uuid.UUID, limit, offset int) ([]models.AdminAuditEntry, error) {
+	query := `
+		SELECT id, actor_id, actor_email, action, parameters, status_code, COALESCE(host(ip_address), ''), created_at
+		FROM admin_audit_log
+		WHERE $1::uuid IS NULL OR actor_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, actorID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admin actions: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AdminAuditEntry{}
+	for rows.Next() {
+		var entry models.AdminAuditEntry
+		var parametersJSON []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ActorEmail,
+			&entry.Action,
+			&parametersJSON,
+			&entry.StatusCode,
+			&entry.IPAddress,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan admin action: %w", err)
+		}
+
+		if err := json.Unmarshal(parametersJSON, &entry.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit parameters: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate admin actions: %w", err)
+	}
+
+	return entries, nil
+}
@@ -2,15 +2,27 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"spontra/search-service/internal/models"
 )
 
+// ErrDurationNotFound is returned when a route is missing from flight_durations and cannot be estimated
+var ErrDurationNotFound = errors.New("flight duration not found")
+
+// DurationEstimator predicts the duration of routes missing from flight_durations
+type DurationEstimator interface {
+	EstimateFlightDuration(originAirport, destinationAirport string) (*FlightDuration, error)
+}
+
 // DurationRepository handles flight duration data access
 type DurationRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	estimator DurationEstimator
 }
 
 // NewDurationRepository creates a new duration repository
@@ -27,14 +39,22 @@ type FlightDuration struct {
 	DistanceKM         int       `db:"distance_km"`
 	IsDirect           bool      `db:"is_direct"`
 	TypicalStops       int       `db:"typical_stops"`
+	Estimated          bool      `db:"is_estimated" json:"estimated"`
+	Confidence         float64   `db:"estimate_confidence" json:"confidence"` // 1 for measured routes
 	CreatedAt          time.Time `db:"created_at"`
 	UpdatedAt          time.Time `db:"updated_at"`
 }
 
-// GetFlightDuration retrieves flight duration for a specific route
+// SetEstimator makes GetFlightDuration fall back to estimates for routes missing from the table
+func (r *DurationRepository) SetEstimator(estimator DurationEstimator) {
+	r.estimator = estimator
+}
+
+// GetFlightDuration retrieves flight duration for a specific route, estimating it when the
+// route is missing and an estimator is set
 func (r *DurationRepository) GetFlightDuration(originAirport, destinationAirport string) (*FlightDuration, error) {
 	query := `
-		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, created_at, updated_at
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
 		FROM flight_durations 
 		WHERE origin_airport = $1 AND destination_airport = $2`
 
@@ -47,13 +67,18 @@ func (r *DurationRepository) GetFlightDuration(originAirport, destinationAirport
 		&duration.DistanceKM,
 		&duration.IsDirect,
 		&duration.TypicalStops,
+		&duration.Estimated,
+		&duration.Confidence,
 		&duration.CreatedAt,
 		&duration.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("flight duration not found for route %s -> %s", originAirport, destinationAirport)
+			if r.estimator != nil {
+				return r.estimator.EstimateFlightDuration(originAirport, destinationAirport)
+			}
+			return nil, fmt.Errorf("%w for route %s -> %s", ErrDurationNotFound, originAirport, destinationAirport)
 		}
 		return nil, fmt.Errorf("failed to get flight duration: %w", err)
 	}
@@ -68,7 +93,7 @@ func (r *DurationRepository) GetFlightDurationsForOrigin(originAirport string, l
 	}
 
 	query := `
-		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, created_at, updated_at
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
 		FROM flight_durations 
 		WHERE origin_airport = $1 
 		ORDER BY duration_minutes ASC 
@@ -91,6 +116,8 @@ func (r *DurationRepository) GetFlightDurationsForOrigin(originAirport string, l
 			&duration.DistanceKM,
 			&duration.IsDirect,
 			&duration.TypicalStops,
+			&duration.Estimated,
+			&duration.Confidence,
 			&duration.CreatedAt,
 			&duration.UpdatedAt,
 		)
@@ -110,9 +137,9 @@ func (r *DurationRepository) GetFlightDurationsForOrigin(originAirport string, l
 // GetDirectFlights retrieves only direct flights for a route
 func (r *DurationRepository) GetDirectFlights(originAirport, destinationAirport string) (*FlightDuration, error) {
 	query := `
-		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, created_at, updated_at
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
 		FROM flight_durations 
-		WHERE origin_airport = $1 AND destination_airport = $2 AND is_direct = true`
+		WHERE origin_airport = $1 AND destination_airport = $2 AND is_direct = true AND is_estimated = false`
 
 	var duration FlightDuration
 	err := r.db.QueryRow(query, originAirport, destinationAirport).Scan(
@@ -123,6 +150,8 @@ func (r *DurationRepository) GetDirectFlights(originAirport, destinationAirport
 		&duration.DistanceKM,
 		&duration.IsDirect,
 		&duration.TypicalStops,
+		&duration.Estimated,
+		&duration.Confidence,
 		&duration.CreatedAt,
 		&duration.UpdatedAt,
 	)
@@ -144,7 +173,7 @@ func (r *DurationRepository) GetFlightsByDurationRange(originAirport string, min
 	}
 
 	query := `
-		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, created_at, updated_at
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
 		FROM flight_durations 
 		WHERE origin_airport = $1 
 		AND duration_minutes >= $2 
@@ -169,6 +198,8 @@ func (r *DurationRepository) GetFlightsByDurationRange(originAirport string, min
 			&duration.DistanceKM,
 			&duration.IsDirect,
 			&duration.TypicalStops,
+			&duration.Estimated,
+			&duration.Confidence,
 			&duration.CreatedAt,
 			&duration.UpdatedAt,
 		)
@@ -192,7 +223,7 @@ func (r *DurationRepository) GetPopularDestinations(originAirport string, direct
 	}
 
 	baseQuery := `
-		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, created_at, updated_at
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
 		FROM flight_durations 
 		WHERE origin_airport = $1`
 
@@ -224,6 +255,8 @@ func (r *DurationRepository) GetPopularDestinations(originAirport string, direct
 			&duration.DistanceKM,
 			&duration.IsDirect,
 			&duration.TypicalStops,
+			&duration.Estimated,
+			&duration.Confidence,
 			&duration.CreatedAt,
 			&duration.UpdatedAt,
 		)
@@ -240,6 +273,133 @@ func (r *DurationRepository) GetPopularDestinations(originAirport string, direct
 	return durations, nil
 }
 
+// GetDirectRoutes retrieves direct routes leaving any of the origins and arriving at any of the
+// destinations. An empty list leaves that side of the route unrestricted.
+func (r *DurationRepository) GetDirectRoutes(origins, destinations []string) ([]FlightDuration, error) {
+	query := `
+		SELECT id, origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence, created_at, updated_at
+		FROM flight_durations 
+		WHERE is_direct = true AND is_estimated = false`
+
+	var conditions []string
+	var args []interface{}
+	if len(origins) > 0 {
+		args = append(args, pq.Array(origins))
+		conditions = append(conditions, fmt.Sprintf("origin_airport = ANY($%d)", len(args)))
+	}
+	if len(destinations) > 0 {
+		args = append(args, pq.Array(destinations))
+		conditions = append(conditions, fmt.Sprintf("destination_airport = ANY($%d)", len(args)))
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("direct routes need at least one origin or destination")
+	}
+	query += " AND " + strings.Join(conditions, " AND ")
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct routes: %w", err)
+	}
+	defer rows.Close()
+
+	var durations []FlightDuration
+	for rows.Next() {
+		var duration FlightDuration
+		err := rows.Scan(
+			&duration.ID,
+			&duration.OriginAirport,
+			&duration.DestinationAirport,
+			&duration.DurationMinutes,
+			&duration.DistanceKM,
+			&duration.IsDirect,
+			&duration.TypicalStops,
+			&duration.Estimated,
+			&duration.Confidence,
+			&duration.CreatedAt,
+			&duration.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan direct route: %w", err)
+		}
+		durations = append(durations, duration)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate direct routes: %w", err)
+	}
+
+	return durations, nil
+}
+
+// GetRouteKeys returns the routes already stored between the given airports, keyed "ORIGIN-DESTINATION"
+func (r *DurationRepository) GetRouteKeys(airports []string) (map[string]bool, error) {
+	query := `
+		SELECT origin_airport, destination_airport
+		FROM flight_durations 
+		WHERE origin_airport = ANY($1) AND destination_airport = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(airports))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get route keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var origin, destination string
+		if err := rows.Scan(&origin, &destination); err != nil {
+			return nil, fmt.Errorf("failed to scan route key: %w", err)
+		}
+		keys[origin+"-"+destination] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate route keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// InsertFlightDurations stores flight durations in a single transaction
+func (r *DurationRepository) InsertFlightDurations(durations []FlightDuration) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO flight_durations 
+		(origin_airport, destination_airport, duration_minutes, distance_km, is_direct, typical_stops, is_estimated, estimate_confidence) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, duration := range durations {
+		_, err := stmt.Exec(
+			duration.OriginAirport,
+			duration.DestinationAirport,
+			duration.DurationMinutes,
+			duration.DistanceKM,
+			duration.IsDirect,
+			duration.TypicalStops,
+			duration.Estimated,
+			duration.Confidence,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert flight duration %s -> %s: %w", duration.OriginAirport, duration.DestinationAirport, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit flight durations: %w", err)
+	}
+
+	return nil
+}
+
 // GetRouteStatistics retrieves statistics for flight durations
 func (r *DurationRepository) GetRouteStatistics() (map[string]interface{}, error) {
 	query := `
@@ -385,6 +545,9 @@ func (r *DurationRepository) ValidateFlightDuration(originAirport, destinationAi
 	} else {
 		validation.Message = "Duration is within expected range"
 	}
+	if duration.Estimated {
+		validation.Message += fmt.Sprintf(" (estimated, confidence %.2f)", duration.Confidence)
+	}
 
 	return validation, nil
 }
\ No newline at end of file
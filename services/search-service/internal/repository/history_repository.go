@@ -1,15 +1,25 @@
 package repository
 
 import (
+	"bytes"
+	"compress/gzip"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"spontra/search-service/internal/models"
 	"github.com/google/uuid"
 )
 
+// ErrSearchHistoryNotFound is returned when no search history record matches
+var ErrSearchHistoryNotFound = errors.New("search history not found")
+
+// ErrSnapshotNotFound is returned when a search has no unexpired result snapshot
+var ErrSnapshotNotFound = errors.New("result snapshot not found")
+
 // HistoryRepository handles search history data access
 type HistoryRepository struct {
 	db *sql.DB
@@ -76,7 +86,7 @@ func (r *HistoryRepository) GetSearchHistory(historyID uuid.UUID) (*models.Searc
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("search history not found")
+			return nil, ErrSearchHistoryNotFound
 		}
 		return nil, fmt.Errorf("failed to get search history: %w", err)
 	}
@@ -89,6 +99,66 @@ func (r *HistoryRepository) GetSearchHistory(historyID uuid.UUID) (*models.Searc
 	return &history, nil
 }
 
+// GetSearchHistoryBySearchID retrieves the search history record of a search
+func (r *HistoryRepository) GetSearchHistoryBySearchID(searchID uuid.UUID) (*models.SearchHistory, error) {
+	query := `
+		SELECT id, search_id, user_id, session_id, request, result_count, best_price, currency, created_at, expires_at
+		FROM search_history 
+		WHERE search_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var history models.SearchHistory
+	var requestJSON []byte
+
+	err := r.db.QueryRow(query, searchID).Scan(
+		&history.ID,
+		&history.SearchID,
+		&history.UserID,
+		&history.SessionID,
+		&requestJSON,
+		&history.ResultCount,
+		&history.BestPrice,
+		&history.Currency,
+		&history.CreatedAt,
+		&history.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSearchHistoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get search history: %w", err)
+	}
+
+	// Unmarshal the request JSON
+	if err := json.Unmarshal(requestJSON, &history.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+
+	return &history, nil
+}
+
+// ClaimSessionHistory assigns a session's anonymous search history to a user
+func (r *HistoryRepository) ClaimSessionHistory(sessionID string, userID uuid.UUID) (int, error) {
+	query := `
+		UPDATE search_history 
+		SET user_id = $1 
+		WHERE session_id = $2 AND user_id IS NULL`
+
+	result, err := r.db.Exec(query, userID, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim session history: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 // GetUserSearchHistory retrieves search history for a user
 func (r *HistoryRepository) GetUserSearchHistory(userID uuid.UUID, limit, offset int) ([]models.SearchHistory, error) {
 	query := `
@@ -311,13 +381,24 @@ func (r *HistoryRepository) GetSearchStats() (map[string]interface{}, error) {
 	return result, nil
 }
 
-// DeleteExpiredHistory removes expired search history records
+// DeleteExpiredHistory removes expired search history records and their result snapshots
 func (r *HistoryRepository) DeleteExpiredHistory() (int, error) {
-	query := `
-		DELETE FROM search_history 
-		WHERE expires_at < CURRENT_TIMESTAMP`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM search_result_snapshots 
+		WHERE expires_at < CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired snapshots: %w", err)
+	}
 
-	result, err := r.db.Exec(query)
+	result, err := tx.Exec(`
+		DELETE FROM search_history 
+		WHERE expires_at < CURRENT_TIMESTAMP`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete expired history: %w", err)
 	}
@@ -327,9 +408,88 @@ func (r *HistoryRepository) DeleteExpiredHistory() (int, error) {
 		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return int(rowsAffected), nil
 }
 
+// SaveResultSnapshot stores a search's full result set, gzip-compressed, until expiresAt
+// or later, when an existing snapshot of the search already outlives it
+func (r *HistoryRepository) SaveResultSnapshot(response *models.FlightSearchResponse, expiresAt time.Time) error {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result snapshot: %w", err)
+	}
+
+	var payload bytes.Buffer
+	writer := gzip.NewWriter(&payload)
+	if _, err := writer.Write(responseJSON); err != nil {
+		return fmt.Errorf("failed to compress result snapshot: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress result snapshot: %w", err)
+	}
+
+	query := `
+		INSERT INTO search_result_snapshots (search_id, payload, flight_count, uncompressed_size, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (search_id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			flight_count = EXCLUDED.flight_count,
+			uncompressed_size = EXCLUDED.uncompressed_size,
+			expires_at = GREATEST(search_result_snapshots.expires_at, EXCLUDED.expires_at)`
+
+	_, err = r.db.Exec(query,
+		response.SearchID,
+		payload.Bytes(),
+		len(response.Flights),
+		len(responseJSON),
+		time.Now(),
+		expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save result snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetResultSnapshot loads the result set snapshot of a search
+func (r *HistoryRepository) GetResultSnapshot(searchID uuid.UUID) (*models.FlightSearchResponse, error) {
+	query := `
+		SELECT payload 
+		FROM search_result_snapshots 
+		WHERE search_id = $1 AND expires_at >= CURRENT_TIMESTAMP`
+
+	var payload []byte
+	if err := r.db.QueryRow(query, searchID).Scan(&payload); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to get result snapshot: %w", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress result snapshot: %w", err)
+	}
+	defer reader.Close()
+
+	responseJSON, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress result snapshot: %w", err)
+	}
+
+	var response models.FlightSearchResponse
+	if err := json.Unmarshal(responseJSON, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result snapshot: %w", err)
+	}
+
+	return &response, nil
+}
+
 // GetSearchTrends returns search trends over time
 func (r *HistoryRepository) GetSearchTrends(days int) ([]map[string]interface{}, error) {
 	query := `
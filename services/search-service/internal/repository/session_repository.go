@@ -155,6 +155,21 @@ func (r *SessionRepository) GetUserSearchSessions(userID uuid.UUID, limit int) (
 	return sessions, nil
 }
 
+// ClaimSearchSession assigns an anonymous search session to a user
+func (r *SessionRepository) ClaimSearchSession(sessionID string, userID uuid.UUID) error {
+	query := `
+		UPDATE search_sessions 
+		SET user_id = $1 
+		WHERE session_id = $2 AND user_id IS NULL`
+
+	_, err := r.db.Exec(query, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to claim search session: %w", err)
+	}
+
+	return nil
+}
+
 // DeactivateSearchSession deactivates a search session
 func (r *SessionRepository) DeactivateSearchSession(sessionID string) error {
 	query := `
@@ -0,0 +1,73 @@
+package services
+
+// cityAliases holds the names of cities in other languages, keyed by the IATA city code the
+// airport index stores in city_code. Only names that differ from the English one beyond
+// diacritics are listed; folding takes care of the rest.
+var cityAliases = map[string][]string{
+	"ATH": {"Athen", "Athènes", "Atenas", "Atene", "Athína"},
+	"BER": {"Berlino"},
+	"BRN": {"Berne", "Berna"},
+	"BRU": {"Brüssel", "Bruxelles", "Bruselas", "Bruxelas", "Brussel"},
+	"BSL": {"Bâle", "Basilea"},
+	"BUH": {"Bukarest", "Bucarest", "Bucureşti", "Bucareste"},
+	"CGN": {"Köln", "Colonia", "Colônia", "Keulen"},
+	"CPH": {"Kopenhagen", "Copenhague", "Copenaghen", "København"},
+	"DUB": {"Dublino"},
+	"DUS": {"Duesseldorf"},
+	"FLR": {"Florenz", "Florencia", "Firenze", "Florença"},
+	"FRA": {"Francfort", "Fráncfort", "Francoforte"},
+	"GDN": {"Danzig", "Dantzig"},
+	"GOT": {"Göteborg", "Gotemburgo"},
+	"GVA": {"Genf", "Genève", "Ginebra", "Ginevra", "Genebra"},
+	"HAM": {"Hambourg", "Hamburgo", "Amburgo"},
+	"HAJ": {"Hanover", "Hanovre"},
+	"HEL": {"Helsingfors", "Helsinque"},
+	"IST": {"Estambul", "Stamboul", "Istambul"},
+	"KRK": {"Krakau", "Cracovie", "Cracovia", "Cracóvia"},
+	"LGG": {"Lüttich", "Lieja", "Luik"},
+	"LIS": {"Lissabon", "Lisbonne", "Lisboa", "Lisbona"},
+	"LJU": {"Laibach", "Liubliana", "Lubiana"},
+	"LON": {"Londres", "Londra", "Londen", "Londýn"},
+	"MIL": {"Mailand", "Milano", "Milán", "Milão"},
+	"MRS": {"Marsella", "Marsiglia", "Marselha"},
+	"MUC": {"München", "Munique", "Monaco di Baviera"},
+	"NAP": {"Neapel", "Nápoles", "Napoli"},
+	"NCE": {"Nizza", "Niza"},
+	"NUE": {"Nürnberg", "Nuremberga", "Norimberga"},
+	"PAR": {"Parigi", "Parijs", "Paříž"},
+	"PRG": {"Prag", "Praga", "Praha"},
+	"ROM": {"Rom", "Roma"},
+	"SOF": {"Sofija"},
+	"STO": {"Estocolmo", "Stoccolma"},
+	"SVQ": {"Sevilla", "Séville", "Siviglia", "Sevilha"},
+	"SXB": {"Straßburg", "Estrasburgo", "Strasburgo"},
+	"VCE": {"Venedig", "Venise", "Venecia", "Venezia", "Veneza"},
+	"VIE": {"Wien", "Vienne", "Viena"},
+	"WAW": {"Warschau", "Varsovie", "Varsovia", "Varsavia", "Warszawa"},
+	"WRO": {"Breslau", "Breslavia"},
+	"ZUR": {"Zurigo"},
+}
+
+// countryAliases holds other names of countries, mostly in other languages, keyed by ISO
+// country code
+var countryAliases = map[string][]string{
+	"AT": {"Österreich", "Autriche"},
+	"BE": {"Belgien", "Belgique", "Bélgica", "Belgio", "België"},
+	"CH": {"Schweiz", "Suisse", "Suiza", "Svizzera", "Suíça"},
+	"CZ": {"Tschechien", "Tchéquie", "Chequia", "Cechia"},
+	"DE": {"Deutschland", "Allemagne", "Alemania", "Germania", "Alemanha", "Duitsland"},
+	"DK": {"Dänemark", "Danemark", "Dinamarca", "Danimarca"},
+	"ES": {"Spanien", "Espagne", "España", "Spagna", "Espanha", "Spanje"},
+	"FR": {"Frankreich", "Francia", "França", "Frankrijk"},
+	"GB": {"England", "Großbritannien", "Royaume-Uni", "Reino Unido", "Regno Unito", "Great Britain"},
+	"GR": {"Griechenland", "Grèce", "Grecia", "Grécia", "Hellas"},
+	"HR": {"Kroatien", "Croatie", "Croacia", "Croazia", "Hrvatska"},
+	"IE": {"Irland", "Irlande", "Irlanda"},
+	"IT": {"Italien", "Italie", "Italia", "Itália"},
+	"NL": {"Niederlande", "Pays-Bas", "Países Bajos", "Paesi Bassi", "Holland", "Nederland"},
+	"NO": {"Norwegen", "Norvège", "Noruega", "Norvegia"},
+	"PL": {"Polen", "Pologne", "Polonia", "Polska"},
+	"PT": {"Portogallo"},
+	"SE": {"Schweden", "Suède", "Suecia", "Svezia", "Sverige"},
+	"TR": {"Türkei", "Turquie", "Turquía", "Turchia", "Türkiye"},
+}
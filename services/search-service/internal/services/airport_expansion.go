@@ -0,0 +1,226 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+
+	"spontra/search-service/internal/geo"
+	"spontra/search-service/internal/models"
+)
+
+// expandedAirport is an airport searched on one side of an expanded search
+type expandedAirport struct {
+	code       string
+	distanceKm float64 // from the nearest airport the user asked for
+}
+
+// airportPair is one origin/destination combination of an expanded search
+type airportPair struct {
+	origin      expandedAirport
+	destination expandedAirport
+}
+
+// searchAirportPairs runs the orchestrator for every origin/destination pair of an expanded search
+// and tags each flight with how far its airports are from the requested ones. Each pair fans out
+// to every provider, so only AirportPairConcurrency pairs are searched at a time. It fails when
+// no pair could be searched.
+func (s *SearchService) searchAirportPairs(req *models.FlightSearchRequest, onResult providerResultFunc) ([]models.Flight, *SearchMetadata, error) {
+	if !req.ExpandAirports || len(req.Legs) > 0 {
+		return s.orchestrateSearch(req, onResult)
+	}
+
+	radiusKm := s.nearbyRadius(req)
+	origins := s.expandAirport(req.OriginAirport, radiusKm)
+	destinations := s.expandAirport(req.DestinationAirport, radiusKm)
+
+	var pairs []airportPair
+	for _, origin := range origins {
+		for _, destination := range destinations {
+			if origin.code != destination.code {
+				pairs = append(pairs, airportPair{origin: origin, destination: destination})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return s.orchestrateSearch(req, onResult)
+	}
+
+	// Every pair collects provider results in its own goroutine; serialise the callbacks
+	callback := onResult
+	if onResult != nil {
+		var mu sync.Mutex
+		callback = func(provider string, flights []models.Flight, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			onResult(provider, flights, err)
+		}
+	}
+
+	type pairResult struct {
+		flights  []models.Flight
+		metadata *SearchMetadata
+		err      error
+	}
+
+	concurrency := s.cfg.AirportPairConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	results := make([]pairResult, len(pairs))
+	var wg sync.WaitGroup
+	for i, pair := range pairs {
+		wg.Add(1)
+		go func(i int, pair airportPair) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			pairReq := *req
+			pairReq.OriginAirport = pair.origin.code
+			pairReq.DestinationAirport = pair.destination.code
+
+			flights, metadata, err := s.orchestrateSearch(&pairReq, callback)
+			for j := range flights {
+				flights[j].OriginDistanceKm = pair.origin.distanceKm
+				flights[j].DestinationDistanceKm = pair.destination.distanceKm
+			}
+			results[i] = pairResult{flights: flights, metadata: metadata, err: err}
+		}(i, pair)
+	}
+	wg.Wait()
+
+	combined := &SearchMetadata{
+		ProvidersQueried:      s.cfg.EnabledProviders,
+		ProvidersSuccessful:   []string{},
+		ProvidersErrors:       make(map[string]string),
+		ProviderContributions: make(map[string]models.ProviderContribution),
+	}
+	successful := make(map[string]bool)
+
+	var allFlights []models.Flight
+	pairsSearched := 0
+	for i, result := range results {
+		route := pairs[i].origin.code + "-" + pairs[i].destination.code
+		if result.err != nil {
+			combined.ProvidersErrors[route] = result.err.Error()
+			continue
+		}
+		pairsSearched++
+
+		allFlights = append(allFlights, result.flights...)
+		combined.TotalResults += result.metadata.TotalResults
+		combined.DuplicatesRemoved += result.metadata.DuplicatesRemoved
+		combined.FaresWithoutRate += result.metadata.FaresWithoutRate
+
+		for _, name := range result.metadata.ProvidersSuccessful {
+			if !successful[name] {
+				successful[name] = true
+				combined.ProvidersSuccessful = append(combined.ProvidersSuccessful, name)
+			}
+		}
+		for name, message := range result.metadata.ProvidersErrors {
+			combined.ProvidersErrors[name+" "+route] = message
+		}
+		for name, contribution := range result.metadata.ProviderContributions {
+			total := combined.ProviderContributions[name]
+			total.Offers += contribution.Offers
+			total.Unique += contribution.Unique
+			total.Cheapest += contribution.Cheapest
+			combined.ProviderContributions[name] = total
+		}
+	}
+	if pairsSearched == 0 {
+		return nil, combined, fmt.Errorf("%w for any of %d airport pairs: %v", errNoProviderResults, len(pairs), combined.ProvidersErrors)
+	}
+
+	return allFlights, combined, nil
+}
+
+// expandAirport resolves a requested code into the airports to search, nearest first.
+// City codes resolve to their member airports; a positive radius adds nearby airports.
+// The requested airports are always kept; only nearby airports are cut to MaxExpandedAirports.
+func (s *SearchService) expandAirport(code string, radiusKm float64) []expandedAirport {
+	fallback := []expandedAirport{{code: code}}
+	if s.elasticsearch == nil {
+		return fallback
+	}
+
+	requested, err := s.elasticsearch.ResolveAirports(code)
+	if err != nil {
+		log.Printf("Airport expansion for %s failed: %v", code, err)
+		return fallback
+	}
+	if len(requested) == 0 {
+		return fallback
+	}
+
+	distances := make(map[string]float64, len(requested))
+	requestedCodes := make(map[string]struct{}, len(requested))
+	for _, airport := range requested {
+		distances[airport.Code] = 0
+		requestedCodes[airport.Code] = struct{}{}
+	}
+
+	if radiusKm > 0 {
+		for _, center := range requested {
+			if center.Coordinates == nil {
+				continue
+			}
+
+			nearby, err := s.elasticsearch.NearbyAirports(*center.Coordinates, radiusKm, s.cfg.MaxExpandedAirports*2)
+			if err != nil {
+				log.Printf("Nearby airport lookup for %s failed: %v", center.Code, err)
+				continue
+			}
+
+			for _, airport := range nearby {
+				if airport.Coordinates == nil {
+					continue
+				}
+				distance := geo.DistanceKm(center.Coordinates.Lat, center.Coordinates.Lon, airport.Coordinates.Lat, airport.Coordinates.Lon)
+				if existing, ok := distances[airport.Code]; !ok || distance < existing {
+					distances[airport.Code] = math.Round(distance*10) / 10
+				}
+			}
+		}
+	}
+
+	airports := make([]expandedAirport, 0, len(distances))
+	for airportCode, distance := range distances {
+		airports = append(airports, expandedAirport{code: airportCode, distanceKm: distance})
+	}
+	// Requested airports first, so truncation only drops nearby ones
+	sort.Slice(airports, func(i, j int) bool {
+		_, iRequested := requestedCodes[airports[i].code]
+		_, jRequested := requestedCodes[airports[j].code]
+		if iRequested != jRequested {
+			return iRequested
+		}
+		if airports[i].distanceKm != airports[j].distanceKm {
+			return airports[i].distanceKm < airports[j].distanceKm
+		}
+		return airports[i].code < airports[j].code
+	})
+
+	limit := s.cfg.MaxExpandedAirports
+	if limit > 0 && limit < len(requestedCodes) {
+		limit = len(requestedCodes)
+	}
+	if limit > 0 && len(airports) > limit {
+		airports = airports[:limit]
+	}
+	return airports
+}
+
+// nearbyRadius returns the requested nearby-airport radius, capped by configuration
+func (s *SearchService) nearbyRadius(req *models.FlightSearchRequest) float64 {
+	if req.NearbyRadiusKm <= 0 {
+		return 0
+	}
+	return math.Min(req.NearbyRadiusKm, s.cfg.MaxNearbyRadiusKm)
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/analytics"
+	"spontra/search-service/internal/cache"
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/models"
+)
+
+// ErrUnknownSuggestionType is returned when a picked suggestion is neither an airport nor a city
+var ErrUnknownSuggestionType = errors.New("unknown suggestion type")
+
+// ErrUnknownSuggestion is returned when a picked code is not in the suggestion index
+var ErrUnknownSuggestion = errors.New("unknown suggestion")
+
+// AirportSuggestService serves airport autocomplete from the suggestion index and rebuilds
+// the index with current traffic and the suggestions users pick
+type AirportSuggestService struct {
+	cfg             *config.Config
+	cache           *cache.RedisClient
+	cacheKeyBuilder *cache.CacheKeyBuilder
+	elasticsearch   *elasticsearch.Client
+	events          *analytics.Publisher
+}
+
+// NewAirportSuggestService creates a new airport suggestion service
+func NewAirportSuggestService(
+	cfg *config.Config,
+	redisClient *cache.RedisClient,
+	elasticsearch *elasticsearch.Client,
+	events *analytics.Publisher,
+) *AirportSuggestService {
+	return &AirportSuggestService{
+		cfg:             cfg,
+		cache:           redisClient,
+		cacheKeyBuilder: cache.NewCacheKeyBuilder("airport"),
+		elasticsearch:   elasticsearch,
+		events:          events,
+	}
+}
+
+// Suggest returns up to limit suggestions for a partially typed query, and whether they came
+// from the cache. A matching city is followed by its airports.
+func (s *AirportSuggestService) Suggest(query string, limit int) ([]models.AirportSuggestion, bool, error) {
+	query = normalizeSuggestQuery(query)
+	cacheKey := s.cacheKeyBuilder.AirportSuggestions(query, limit)
+
+	var suggestions []models.AirportSuggestion
+	if err := s.cache.Get(cacheKey, &suggestions); err == nil {
+		return suggestions, true, nil
+	}
+
+	documents, err := s.elasticsearch.SuggestAirports(query, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	suggestions = groupSuggestions(documents, limit)
+
+	go func() {
+		if err := s.cache.Set(cacheKey, suggestions, s.cacheTTL()); err != nil {
+			log.Printf("Failed to cache airport suggestions: %v", err)
+		}
+	}()
+
+	return suggestions, false, nil
+}
+
+// RecordPick counts a picked suggestion towards its ranking and emits an autocomplete_used event.
+// Only codes in the suggestion index are counted, so the endpoint cannot grow the picks hash
+// with arbitrary codes.
+func (s *AirportSuggestService) RecordPick(req *models.AirportPickRequest, userID *uuid.UUID, sessionID string, eventContext analytics.EventContext) error {
+	suggestionType := req.Type
+	if suggestionType == "" {
+		suggestionType = elasticsearch.SuggestionTypeAirport
+	}
+	if suggestionType != elasticsearch.SuggestionTypeAirport && suggestionType != elasticsearch.SuggestionTypeCity {
+		return ErrUnknownSuggestionType
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+
+	exists, err := s.elasticsearch.SuggestionExists(suggestionType, code)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s %s", ErrUnknownSuggestion, suggestionType, code)
+	}
+
+	// The counter survives rebuilds; the index update makes the pick count before the next one
+	picksKey := s.cacheKeyBuilder.AirportPicks()
+	if _, err := s.cache.IncrementHashField(picksKey, elasticsearch.SuggestionID(suggestionType, code)); err != nil {
+		return fmt.Errorf("failed to record airport pick: %w", err)
+	}
+	if err := s.elasticsearch.RecordAirportPick(suggestionType, code); err != nil {
+		log.Printf("Failed to update airport suggestion picks: %v", err)
+	}
+
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	event := analytics.NewEvent(analytics.EventAutocompleteUsed, userID, sessionID)
+	event.Context = eventContext
+	event.Properties["query"] = req.Query
+	event.Properties["selected_code"] = code
+	event.Properties["selected_type"] = suggestionType
+	event.Properties["position"] = req.Position
+	s.events.Track(event)
+
+	return nil
+}
+
+// Rebuild replaces the suggestion index with documents built from the airport index, the
+// traffic of the flight index and the recorded picks. It returns the number of documents.
+func (s *AirportSuggestService) Rebuild() (int, error) {
+	airports, err := s.elasticsearch.ListAirports()
+	if err != nil {
+		return 0, err
+	}
+	if len(airports) == 0 {
+		// Keep serving the current suggestions until the airport index is populated
+		return 0, nil
+	}
+
+	traffic, err := s.elasticsearch.AirportTraffic()
+	if err != nil {
+		return 0, err
+	}
+
+	picks, err := s.cache.GetHashCounters(s.cacheKeyBuilder.AirportPicks())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load airport picks: %w", err)
+	}
+
+	documents := buildSuggestionDocuments(airports, traffic, picks)
+	index, err := s.elasticsearch.RebuildAirportSuggestions(documents)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("Rebuilt airport suggestions in %s with %d documents", index, len(documents))
+	return len(documents), nil
+}
+
+// cacheTTL keeps cached suggestions no longer than the ranking they were computed with
+func (s *AirportSuggestService) cacheTTL() time.Duration {
+	if s.cfg.AirportSuggestionRefresh > 0 && s.cfg.AirportSuggestionRefresh < s.cfg.AirportCacheTTL {
+		return s.cfg.AirportSuggestionRefresh
+	}
+	return s.cfg.AirportCacheTTL
+}
+
+// buildSuggestionDocuments turns airports into suggestion documents, adding a city document
+// for every city code shared by several airports
+func buildSuggestionDocuments(airports []models.AirportSuggestion, traffic, picks map[string]int64) []elasticsearch.SuggestionDocument {
+	documents := make([]elasticsearch.SuggestionDocument, 0, len(airports))
+	cities := make(map[string][]models.AirportSuggestion)
+
+	for _, airport := range airports {
+		airport.Type = elasticsearch.SuggestionTypeAirport
+		airport.Relevance = 0
+		cityCode := airport.CityCode
+		if cityCode == "" {
+			cityCode = airport.Code
+		}
+
+		documents = append(documents, elasticsearch.SuggestionDocument{
+			AirportSuggestion: airport,
+			Aliases:           suggestionAliases(cityCode, airport.CountryCode),
+			Traffic:           traffic[airport.Code],
+			Picks:             picks[elasticsearch.SuggestionID(airport.Type, airport.Code)],
+		})
+		cities[cityCode] = append(cities[cityCode], airport)
+	}
+
+	for cityCode, members := range cities {
+		if len(members) < 2 {
+			continue
+		}
+
+		// Busiest airports are listed first under their city
+		sort.Slice(members, func(i, j int) bool {
+			if traffic[members[i].Code] != traffic[members[j].Code] {
+				return traffic[members[i].Code] > traffic[members[j].Code]
+			}
+			return members[i].Code < members[j].Code
+		})
+
+		var cityTraffic int64
+		for _, member := range members {
+			cityTraffic += traffic[member.Code]
+		}
+
+		name := cityName(members)
+		documents = append(documents, elasticsearch.SuggestionDocument{
+			AirportSuggestion: models.AirportSuggestion{
+				Code:        cityCode,
+				Name:        name,
+				City:        name,
+				Country:     members[0].Country,
+				CountryCode: members[0].CountryCode,
+				Type:        elasticsearch.SuggestionTypeCity,
+				CityCode:    cityCode,
+				Coordinates: centroid(members),
+			},
+			Aliases:  suggestionAliases(cityCode, members[0].CountryCode),
+			Traffic:  cityTraffic,
+			Picks:    picks[elasticsearch.SuggestionID(elasticsearch.SuggestionTypeCity, cityCode)],
+			Airports: members,
+		})
+	}
+
+	return documents
+}
+
+// groupSuggestions lists each city followed by its airports, skipping airports already listed
+func groupSuggestions(documents []elasticsearch.SuggestionDocument, limit int) []models.AirportSuggestion {
+	suggestions := make([]models.AirportSuggestion, 0, limit)
+	listed := make(map[string]bool)
+
+	for _, document := range documents {
+		if len(suggestions) >= limit {
+			break
+		}
+		id := elasticsearch.SuggestionID(document.Type, document.Code)
+		if listed[id] {
+			continue
+		}
+		listed[id] = true
+		suggestions = append(suggestions, document.AirportSuggestion)
+
+		if document.Type != elasticsearch.SuggestionTypeCity {
+			continue
+		}
+		for _, airport := range document.Airports {
+			airportID := elasticsearch.SuggestionID(elasticsearch.SuggestionTypeAirport, airport.Code)
+			if listed[airportID] || len(suggestions) >= limit {
+				continue
+			}
+			listed[airportID] = true
+			airport.Type = elasticsearch.SuggestionTypeAirport
+			airport.Relevance = document.Relevance
+			suggestions = append(suggestions, airport)
+		}
+	}
+
+	return suggestions
+}
+
+// suggestionAliases returns the other names of a city and its country
+func suggestionAliases(cityCode, countryCode string) []string {
+	aliases := append([]string{}, cityAliases[cityCode]...)
+	return append(aliases, countryAliases[countryCode]...)
+}
+
+// cityName returns the city most of a city code's airports are in; an airport can sit in a
+// neighbouring town, such as Bergamo for Milan
+func cityName(airports []models.AirportSuggestion) string {
+	counts := make(map[string]int)
+	best := airports[0].City
+	for _, airport := range airports {
+		counts[airport.City]++
+		if counts[airport.City] > counts[best] {
+			best = airport.City
+		}
+	}
+	return best
+}
+
+// centroid returns the average position of the airports that have coordinates
+func centroid(airports []models.AirportSuggestion) *models.GeoPoint {
+	var point models.GeoPoint
+	var count float64
+	for _, airport := range airports {
+		if airport.Coordinates == nil {
+			continue
+		}
+		point.Lat += airport.Coordinates.Lat
+		point.Lon += airport.Coordinates.Lon
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	point.Lat /= count
+	point.Lon /= count
+	return &point
+}
+
+// normalizeSuggestQuery lowercases a query and collapses its whitespace, so equivalent queries
+// share a cache entry
+func normalizeSuggestQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
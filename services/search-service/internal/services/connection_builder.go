@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+)
+
+// maxConnectionStops is the most hubs a proposed itinerary changes planes at
+const maxConnectionStops = 2
+
+// ConnectionBuilder proposes self-transfer itineraries through hub airports from the
+// direct routes in flight_durations
+type ConnectionBuilder struct {
+	cfg       *config.Config
+	durations *repository.DurationRepository
+}
+
+// NewConnectionBuilder creates a new connection builder
+func NewConnectionBuilder(cfg *config.Config, durations *repository.DurationRepository) *ConnectionBuilder {
+	return &ConnectionBuilder{
+		cfg:       cfg,
+		durations: durations,
+	}
+}
+
+// FindPaths returns itineraries from origin to destination with at most maxStops connections
+// and a total duration, including minimum connection times, of at most maxMinutes
+func (b *ConnectionBuilder) FindPaths(origin, destination string, maxStops, maxMinutes, limit int) (*models.ConnectionPaths, error) {
+	origin, destination = normalizeAirport(origin), normalizeAirport(destination)
+	if origin == destination {
+		return nil, fmt.Errorf("origin and destination cannot be the same")
+	}
+
+	if maxStops < 0 || maxStops > maxConnectionStops {
+		maxStops = maxConnectionStops
+	}
+	if maxMinutes <= 0 || maxMinutes > b.cfg.MaxItineraryMinutes {
+		maxMinutes = b.cfg.MaxItineraryMinutes
+	}
+	if limit <= 0 || limit > b.cfg.MaxConnectionPaths {
+		limit = b.cfg.MaxConnectionPaths
+	}
+
+	outbound, err := b.durations.GetDirectRoutes([]string{origin}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load routes from %s: %w", origin, err)
+	}
+	inbound, err := b.durations.GetDirectRoutes(nil, []string{destination})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load routes to %s: %w", destination, err)
+	}
+
+	result := &models.ConnectionPaths{
+		OriginAirport:      origin,
+		DestinationAirport: destination,
+		MaxStops:           maxStops,
+		Paths:              []models.ConnectionPath{},
+	}
+
+	// Index the first and last segments by the hub they connect at
+	firstLegs := make(map[string]repository.FlightDuration)
+	for _, route := range outbound {
+		if route.DestinationAirport == destination {
+			result.DirectAvailable = true
+			result.Paths = append(result.Paths, b.buildPath(route))
+			continue
+		}
+		firstLegs[route.DestinationAirport] = route
+	}
+	lastLegs := make(map[string]repository.FlightDuration)
+	for _, route := range inbound {
+		if route.OriginAirport != origin {
+			lastLegs[route.OriginAirport] = route
+		}
+	}
+
+	if maxStops >= 1 {
+		for hub, first := range firstLegs {
+			if last, ok := lastLegs[hub]; ok {
+				result.Paths = append(result.Paths, b.buildPath(first, last))
+			}
+		}
+	}
+
+	if maxStops >= 2 && len(firstLegs) > 0 && len(lastLegs) > 0 {
+		middle, err := b.durations.GetDirectRoutes(airportKeys(firstLegs), airportKeys(lastLegs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load routes between hubs: %w", err)
+		}
+		for _, route := range middle {
+			if route.OriginAirport == route.DestinationAirport {
+				continue
+			}
+			result.Paths = append(result.Paths, b.buildPath(firstLegs[route.OriginAirport], route, lastLegs[route.DestinationAirport]))
+		}
+	}
+
+	paths := result.Paths[:0]
+	for _, path := range result.Paths {
+		if path.TotalMinutes <= maxMinutes {
+			paths = append(paths, path)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		if paths[i].TotalMinutes != paths[j].TotalMinutes {
+			return paths[i].TotalMinutes < paths[j].TotalMinutes
+		}
+		return paths[i].Stops < paths[j].Stops
+	})
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+	result.Paths = paths
+
+	return result, nil
+}
+
+// buildPath joins consecutive direct routes into an itinerary, allowing the minimum
+// connection time at every hub
+func (b *ConnectionBuilder) buildPath(segments ...repository.FlightDuration) models.ConnectionPath {
+	path := models.ConnectionPath{
+		Via:      []string{},
+		Stops:    len(segments) - 1,
+		Segments: make([]models.FlightSuggestion, 0, len(segments)),
+	}
+
+	for i, segment := range segments {
+		if i > 0 {
+			hub := segment.OriginAirport
+			path.Via = append(path.Via, hub)
+			path.ConnectionMinutes += b.minConnectionTime(hub)
+		}
+
+		path.FlightMinutes += segment.DurationMinutes
+		path.DistanceKM += segment.DistanceKM
+		path.Segments = append(path.Segments, models.FlightSuggestion{
+			OriginAirport:      segment.OriginAirport,
+			DestinationAirport: segment.DestinationAirport,
+			DurationMinutes:    segment.DurationMinutes,
+			DistanceKM:         segment.DistanceKM,
+			IsDirect:           segment.IsDirect,
+			TypicalStops:       segment.TypicalStops,
+		})
+	}
+	path.TotalMinutes = path.FlightMinutes + path.ConnectionMinutes
+
+	return path
+}
+
+// minConnectionTime returns the minimum time needed to change planes at a hub
+func (b *ConnectionBuilder) minConnectionTime(hub string) int {
+	if minutes, ok := b.cfg.MinConnectionTimes[hub]; ok {
+		return minutes
+	}
+	return b.cfg.MinConnectionMinutes
+}
+
+// airportKeys lists the airports a route index is keyed by
+func airportKeys(routes map[string]repository.FlightDuration) []string {
+	keys := make([]string, 0, len(routes))
+	for airport := range routes {
+		keys = append(keys, airport)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+	"spontra/shared/fx"
+)
+
+// displayCurrency returns the currency a response is priced in, defaulting to the FX base
+func (s *SearchService) displayCurrency(currency string) string {
+	if currency = fx.NormalizeCurrency(currency); currency != "" {
+		return currency
+	}
+	return s.converter.Base()
+}
+
+// validateCurrency normalises a requested display currency and checks it can be converted to
+func (s *SearchService) validateCurrency(currency *string) error {
+	*currency = fx.NormalizeCurrency(*currency)
+	if *currency != "" && !s.converter.Supports(*currency) {
+		return fmt.Errorf("%w: %s", fx.ErrUnsupportedCurrency, *currency)
+	}
+	return nil
+}
+
+// normalizeFlights converts a provider's fares to the FX base currency so they can be merged
+// and ranked with other providers' fares. Fares that cannot be converted, because their
+// currency has no rate or no rates are available at all, are dropped rather than compared
+// as raw numbers; the number dropped is returned so it can be reported.
+func (s *SearchService) normalizeFlights(provider string, flights []models.Flight) ([]models.Flight, int) {
+	base := s.converter.Base()
+	normalized := make([]models.Flight, 0, len(flights))
+	dropped := 0
+	var ratesErr error
+
+	for _, flight := range flights {
+		if flight.Currency == "" {
+			flight.Currency = base
+		}
+
+		converted, err := s.convertFlight(flight, base)
+		if err != nil {
+			if errors.Is(err, fx.ErrRatesUnavailable) {
+				ratesErr = err
+			}
+			dropped++
+			continue
+		}
+		normalized = append(normalized, converted)
+	}
+
+	if ratesErr != nil {
+		log.Printf("Dropped %d %s fares not quoted in %s: %v", dropped, provider, base, ratesErr)
+	} else if dropped > 0 {
+		log.Printf("Dropped %d %s fares in currencies without an exchange rate", dropped, provider)
+	}
+
+	return normalized, dropped
+}
+
+// localizeFlights returns copies of flights priced in the display currency. Flights that cannot
+// be converted keep their current currency.
+func (s *SearchService) localizeFlights(flights []models.Flight, currency string) []models.Flight {
+	localized := make([]models.Flight, len(flights))
+	for i, flight := range flights {
+		converted, err := s.convertFlight(flight, currency)
+		if err != nil {
+			log.Printf("Failed to convert flight %s to %s: %v", flight.ID, currency, err)
+			converted = flight
+		}
+		localized[i] = converted
+	}
+	return localized
+}
+
+// localizeResponse reprices a complete result set in the display currency
+func (s *SearchService) localizeResponse(response *models.FlightSearchResponse, currency string) {
+	if response.SearchMetadata.Currency == currency {
+		return
+	}
+
+	response.Flights = s.localizeFlights(response.Flights, currency)
+	if len(response.Legs) > 0 {
+		response.Legs = buildLegGroups(response.SearchRequest.Legs, response.Flights)
+	}
+	response.SearchMetadata.PriceRange = s.calculatePriceRange(response.Flights, currency)
+	response.SearchMetadata.Currency = currency
+}
+
+// convertFlight returns a copy of a flight priced in another currency. The first conversion
+// records the provider's fare as the original price.
+func (s *SearchService) convertFlight(flight models.Flight, currency string) (models.Flight, error) {
+	from := flight.Currency
+	if from == "" {
+		from = s.converter.Base()
+	}
+	if from == currency {
+		return flight, nil
+	}
+
+	rate, err := s.converter.Rate(from, currency)
+	if err != nil {
+		return flight, err
+	}
+	return convertFlightAt(flight, currency, rate), nil
+}
+
+// convertFlightAt converts a flight's fares at the given rate. Nested flights, provider offers,
+// ancillaries and trip prices are copied, so flights shared with a cached result set are left
+// untouched.
+func convertFlightAt(flight models.Flight, currency string, rate decimal.Decimal) models.Flight {
+	convert := func(amount decimal.Decimal) decimal.Decimal {
+		return amount.Mul(rate).Round(2)
+	}
+
+	if flight.OriginalPrice == nil && !flight.Price.IsZero() {
+		original := flight.Price
+		flight.OriginalPrice = &original
+		flight.OriginalCurrency = flight.Currency
+	}
+	flight.Price = convert(flight.Price)
+	flight.Currency = currency
+
+	if len(flight.Ancillaries) > 0 {
+		ancillaries := make([]models.Ancillary, len(flight.Ancillaries))
+		for i, ancillary := range flight.Ancillaries {
+			ancillary.Price = convert(ancillary.Price)
+			ancillaries[i] = ancillary
+		}
+		flight.Ancillaries = ancillaries
+	}
+	if flight.TripPrice != nil {
+		trip := *flight.TripPrice
+		trip.Fare = flight.Price
+		trip.Total = flight.Price
+		trip.Extras = make([]models.TripExtra, len(flight.TripPrice.Extras))
+		for i, extra := range flight.TripPrice.Extras {
+			extra.Price = convert(extra.Price)
+			trip.Extras[i] = extra
+			trip.Total = trip.Total.Add(extra.Price)
+		}
+		flight.TripPrice = &trip
+	}
+
+	breakdown := &flight.PriceBreakdown
+	breakdown.BaseFare = convert(breakdown.BaseFare)
+	breakdown.Taxes = convert(breakdown.Taxes)
+	breakdown.Fees = convert(breakdown.Fees)
+	breakdown.Total = convert(breakdown.Total)
+	breakdown.PricePerPax = convert(breakdown.PricePerPax)
+	if breakdown.Currency != "" {
+		breakdown.Currency = currency
+	}
+
+	if flight.ReturnFlight != nil {
+		returnFlight := convertFlightAt(*flight.ReturnFlight, currency, rate)
+		flight.ReturnFlight = &returnFlight
+	}
+	if len(flight.Legs) > 0 {
+		legs := make([]models.Flight, len(flight.Legs))
+		for i, leg := range flight.Legs {
+			legs[i] = convertFlightAt(leg, currency, rate)
+		}
+		flight.Legs = legs
+	}
+	if len(flight.ProviderOffers) > 0 {
+		offers := make([]models.ProviderOffer, len(flight.ProviderOffers))
+		for i, offer := range flight.ProviderOffers {
+			offer.Price = convert(offer.Price)
+			offer.Currency = currency
+			offers[i] = offer
+		}
+		flight.ProviderOffers = offers
+	}
+
+	return flight
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sync"
+
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/geo"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+)
+
+// DurationEstimator predicts block times for routes missing from flight_durations from the
+// great-circle distance between their airports
+type DurationEstimator struct {
+	elasticsearch *elasticsearch.Client
+	model         *geo.DurationModel
+
+	mu          sync.RWMutex
+	coordinates map[string]models.GeoPoint
+}
+
+// NewDurationEstimator fits the duration model on the configured dataset, falling back to a
+// fixed-speed model when the dataset or airport coordinates are unavailable
+func NewDurationEstimator(cfg *config.Config, esClient *elasticsearch.Client) *DurationEstimator {
+	estimator := &DurationEstimator{
+		elasticsearch: esClient,
+		model:         geo.FallbackDurationModel(),
+		coordinates:   make(map[string]models.GeoPoint),
+	}
+
+	model, err := estimator.fitModel(cfg.DurationDatasetPath)
+	if err != nil {
+		log.Printf("Using fallback duration model: %v", err)
+		return estimator
+	}
+
+	log.Printf("Fitted duration model on %d routes: %.1f min + %.4f min/km (residual %.1f min)",
+		model.Samples, model.InterceptMinutes, model.MinutesPerKm, model.ResidualStdDev)
+	estimator.model = model
+	return estimator
+}
+
+// EstimateFlightDuration estimates a route from its airports' coordinates
+func (e *DurationEstimator) EstimateFlightDuration(originAirport, destinationAirport string) (*repository.FlightDuration, error) {
+	points, err := e.lookupCoordinates([]string{originAirport, destinationAirport})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate route %s -> %s: %w", originAirport, destinationAirport, err)
+	}
+
+	origin, ok := points[originAirport]
+	if !ok {
+		return nil, fmt.Errorf("%w for route %s -> %s: no coordinates for %s", repository.ErrDurationNotFound, originAirport, destinationAirport, originAirport)
+	}
+	destination, ok := points[destinationAirport]
+	if !ok {
+		return nil, fmt.Errorf("%w for route %s -> %s: no coordinates for %s", repository.ErrDurationNotFound, originAirport, destinationAirport, destinationAirport)
+	}
+
+	duration := e.Estimate(originAirport, destinationAirport, origin, destination)
+	return &duration, nil
+}
+
+// Estimate builds an estimated flight duration between two airports. Estimates are not marked
+// direct: nothing says the route is actually flown nonstop.
+func (e *DurationEstimator) Estimate(originAirport, destinationAirport string, origin, destination models.GeoPoint) repository.FlightDuration {
+	distance := geo.DistanceKm(origin.Lat, origin.Lon, destination.Lat, destination.Lon)
+	minutes, confidence := e.model.Estimate(distance)
+
+	return repository.FlightDuration{
+		OriginAirport:      originAirport,
+		DestinationAirport: destinationAirport,
+		DurationMinutes:    minutes,
+		DistanceKM:         int(math.Round(distance)),
+		IsDirect:           false,
+		Estimated:          true,
+		Confidence:         confidence,
+	}
+}
+
+// fitModel fits the duration model on a CSV dataset, joining each route with airport coordinates
+func (e *DurationEstimator) fitModel(path string) (*geo.DurationModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open duration dataset: %w", err)
+	}
+	defer file.Close()
+
+	routes, err := geo.ReadRouteDurations(file)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var codes []string
+	for _, route := range routes {
+		for _, code := range []string{route.OriginAirport, route.DestinationAirport} {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	points, err := e.lookupCoordinates(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]geo.DurationSample, 0, len(routes))
+	for _, route := range routes {
+		origin, ok := points[route.OriginAirport]
+		if !ok {
+			continue
+		}
+		destination, ok := points[route.DestinationAirport]
+		if !ok {
+			continue
+		}
+		samples = append(samples, geo.DurationSample{
+			DistanceKm:      geo.DistanceKm(origin.Lat, origin.Lon, destination.Lat, destination.Lon),
+			DurationMinutes: float64(route.DurationMinutes),
+		})
+	}
+
+	return geo.FitDurationModel(samples)
+}
+
+// lookupCoordinates returns the coordinates of the given airports, loading unknown ones from Elasticsearch
+func (e *DurationEstimator) lookupCoordinates(codes []string) (map[string]models.GeoPoint, error) {
+	points := make(map[string]models.GeoPoint, len(codes))
+	var missing []string
+
+	e.mu.RLock()
+	for _, code := range codes {
+		if point, ok := e.coordinates[code]; ok {
+			points[code] = point
+			continue
+		}
+		missing = append(missing, code)
+	}
+	e.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return points, nil
+	}
+	if e.elasticsearch == nil {
+		return points, nil
+	}
+
+	airports, err := e.elasticsearch.GetAirports(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, airport := range airports {
+		if airport.Coordinates == nil {
+			continue
+		}
+		e.coordinates[airport.Code] = *airport.Coordinates
+		points[airport.Code] = *airport.Coordinates
+	}
+
+	return points, nil
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/cache"
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+	"spontra/shared/fx"
+)
+
+// Price sources of an explore destination
+const (
+	PriceSourceLive    = "live"
+	PriceSourceHistory = "history"
+)
+
+// ErrInvalidExploreRequest is returned when an explore request fails validation
+var ErrInvalidExploreRequest = errors.New("invalid explore request")
+
+// pricingHistoryEntry mirrors a price_history row returned by pricing-service
+type pricingHistoryEntry struct {
+	Date     time.Time       `json:"date"`
+	MinPrice decimal.Decimal `json:"min_price"`
+	Currency string          `json:"currency"`
+}
+
+// ExploreService finds the cheapest destinations reachable from an origin
+type ExploreService struct {
+	cfg             *config.Config
+	cache           *cache.RedisClient
+	cacheKeyBuilder *cache.CacheKeyBuilder
+	elasticsearch   *elasticsearch.Client
+	durations       *repository.DurationRepository
+	converter       *fx.Converter
+	httpClient      *http.Client
+}
+
+// NewExploreService creates a new explore service
+func NewExploreService(
+	cfg *config.Config,
+	redisClient *cache.RedisClient,
+	elasticsearch *elasticsearch.Client,
+	durations *repository.DurationRepository,
+) *ExploreService {
+	return &ExploreService{
+		cfg:             cfg,
+		cache:           redisClient,
+		cacheKeyBuilder: cache.NewCacheKeyBuilder("search"),
+		elasticsearch:   elasticsearch,
+		durations:       durations,
+		converter:       newCurrencyConverter(cfg, redisClient),
+		httpClient: &http.Client{
+			Timeout: cfg.ProviderTimeout,
+		},
+	}
+}
+
+// ExploreAnywhere prices every destination reachable from the origin within the duration bounds
+// and returns those within budget, cheapest first, with the best departure date for each.
+// Live and historical fares are converted to the request currency before they are compared.
+func (s *ExploreService) ExploreAnywhere(req *models.ExploreRequest) (*models.ExploreResponse, error) {
+	if err := s.validateExploreRequest(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExploreRequest, err)
+	}
+
+	cacheKey := s.cacheKeyBuilder.ExploreAnywhere(req.OriginAirport, exploreFingerprint(req))
+	var cached models.ExploreResponse
+	if err := s.cache.Get(cacheKey, &cached); err == nil {
+		cached.FromCache = true
+		return &cached, nil
+	}
+
+	candidates, err := s.durations.GetFlightsByDurationRange(req.OriginAirport, req.MinDurationMinutes, req.MaxDurationMinutes, s.cfg.ExploreCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reachable destinations: %w", err)
+	}
+
+	routes := make(map[string]repository.FlightDuration, len(candidates))
+	codes := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if req.DirectOnly && !candidate.IsDirect {
+			continue
+		}
+		routes[candidate.DestinationAirport] = candidate
+		codes = append(codes, candidate.DestinationAirport)
+	}
+
+	// Live fares come from the flight index; the rest fall back to pricing history
+	windowEnd := req.DateTo.AddDate(0, 0, 1).Add(-time.Second)
+	fares := make(map[string]elasticsearch.CheapestFare)
+	if s.elasticsearch != nil && len(codes) > 0 {
+		fares, err = s.elasticsearch.GetCheapestFares(req.OriginAirport, codes, req.DateFrom, windowEnd)
+		if err != nil {
+			log.Printf("Live fares for explore from %s unavailable: %v", req.OriginAirport, err)
+			fares = make(map[string]elasticsearch.CheapestFare)
+		}
+	}
+
+	var unpriced []string
+	for _, code := range codes {
+		if _, ok := fares[code]; !ok {
+			unpriced = append(unpriced, code)
+		}
+	}
+	historical := s.historicalFares(req, unpriced)
+
+	destinations := make([]models.ExploreDestination, 0, len(codes))
+	for _, code := range codes {
+		route := routes[code]
+		destination := models.ExploreDestination{
+			DestinationAirport: code,
+			DurationMinutes:    route.DurationMinutes,
+			DistanceKM:         route.DistanceKM,
+			IsDirect:           route.IsDirect,
+		}
+
+		if fare, ok := fares[code]; ok {
+			departure := fare.DepartureTime
+			destination.Price = decimal.NewFromFloat(fare.Price).Round(2)
+			destination.Currency = fare.Currency
+			destination.BestDate = &departure
+			destination.PriceSource = PriceSourceLive
+		} else if fare, ok := historical[code]; ok {
+			destination = fare.apply(destination)
+		} else {
+			continue
+		}
+
+		if destination.Currency == "" {
+			destination.Currency = s.converter.Base()
+		}
+		price, err := s.converter.Convert(destination.Price, destination.Currency, req.Currency)
+		if err != nil {
+			log.Printf("Skipping explore destination %s priced in %q: %v", code, destination.Currency, err)
+			continue
+		}
+		destination.Price = price
+		destination.Currency = req.Currency
+
+		if destination.Price.GreaterThan(req.MaxBudget) {
+			continue
+		}
+		destinations = append(destinations, destination)
+	}
+
+	sort.SliceStable(destinations, func(i, j int) bool {
+		if !destinations[i].Price.Equal(destinations[j].Price) {
+			return destinations[i].Price.LessThan(destinations[j].Price)
+		}
+		return destinations[i].DurationMinutes < destinations[j].DurationMinutes
+	})
+	if len(destinations) > req.Limit {
+		destinations = destinations[:req.Limit]
+	}
+
+	response := &models.ExploreResponse{
+		OriginAirport: req.OriginAirport,
+		DateFrom:      req.DateFrom,
+		DateTo:        req.DateTo,
+		MaxBudget:     req.MaxBudget,
+		Currency:      req.Currency,
+		Destinations:  destinations,
+		Candidates:    len(codes),
+	}
+
+	if err := s.cache.Set(cacheKey, response, s.cfg.CacheTTL); err != nil {
+		log.Printf("Failed to cache explore results: %v", err)
+	}
+
+	return response, nil
+}
+
+// validateExploreRequest normalises the request and applies defaults and limits
+func (s *ExploreService) validateExploreRequest(req *models.ExploreRequest) error {
+	req.OriginAirport = normalizeAirport(req.OriginAirport)
+	if req.OriginAirport == "" {
+		return fmt.Errorf("origin airport is required")
+	}
+	if !req.MaxBudget.IsPositive() {
+		return fmt.Errorf("max budget must be positive")
+	}
+
+	req.Currency = fx.NormalizeCurrency(req.Currency)
+	if req.Currency == "" {
+		req.Currency = s.converter.Base()
+	}
+	if !s.converter.Supports(req.Currency) {
+		return fmt.Errorf("%w: %s", fx.ErrUnsupportedCurrency, req.Currency)
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if req.DateFrom.IsZero() {
+		req.DateFrom = today
+	}
+	if req.DateTo.IsZero() {
+		req.DateTo = req.DateFrom
+	}
+	if req.DateTo.Before(req.DateFrom) {
+		return fmt.Errorf("date_to cannot be before date_from")
+	}
+	if req.DateTo.Before(today) {
+		return fmt.Errorf("date window is in the past")
+	}
+	if req.DateFrom.Before(today) {
+		req.DateFrom = today
+	}
+	if req.DateTo.Sub(req.DateFrom) > time.Duration(s.cfg.ExploreMaxWindowDays)*24*time.Hour {
+		return fmt.Errorf("date window cannot exceed %d days", s.cfg.ExploreMaxWindowDays)
+	}
+
+	if req.MaxDurationMinutes <= 0 {
+		req.MaxDurationMinutes = s.cfg.MaxFlightDuration * 60
+	}
+	if req.MinDurationMinutes < 0 || req.MinDurationMinutes > req.MaxDurationMinutes {
+		return fmt.Errorf("invalid duration bounds")
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = s.cfg.DefaultMaxResults
+	}
+	if req.Limit > s.cfg.MaxResultsLimit {
+		req.Limit = s.cfg.MaxResultsLimit
+	}
+
+	return nil
+}
+
+// historicalFare is the cheapest price pricing-service has recorded for a route
+type historicalFare struct {
+	price    decimal.Decimal
+	currency string
+	date     *time.Time // set when the recorded date falls inside the requested window
+}
+
+// apply fills a destination's price from the historical fare
+func (f historicalFare) apply(destination models.ExploreDestination) models.ExploreDestination {
+	destination.Price = f.price
+	destination.Currency = f.currency
+	destination.BestDate = f.date
+	destination.PriceSource = PriceSourceHistory
+	return destination
+}
+
+// historicalFares looks up pricing history for destinations without a live fare. Dates inside
+// the requested window are preferred; otherwise the cheapest recent price is used as an indication.
+func (s *ExploreService) historicalFares(req *models.ExploreRequest, destinations []string) map[string]historicalFare {
+	fares := make(map[string]historicalFare)
+	if len(destinations) == 0 {
+		return fares
+	}
+
+	concurrency := s.cfg.ExploreHistoryConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, destination := range destinations {
+		wg.Add(1)
+		go func(destination string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			history, err := s.priceHistory(req.OriginAirport, destination)
+			if err != nil {
+				log.Printf("Price history for %s-%s unavailable: %v", req.OriginAirport, destination, err)
+				return
+			}
+
+			fare, ok := cheapestHistoricalFare(history, req.DateFrom, req.DateTo)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			fares[destination] = fare
+			mu.Unlock()
+		}(destination)
+	}
+	wg.Wait()
+
+	return fares
+}
+
+// cheapestHistoricalFare picks the cheapest entry inside the window, or the cheapest overall
+func cheapestHistoricalFare(history []pricingHistoryEntry, from, to time.Time) (historicalFare, bool) {
+	var inWindow, overall *pricingHistoryEntry
+	for i := range history {
+		entry := &history[i]
+		if !entry.MinPrice.IsPositive() {
+			continue
+		}
+		if overall == nil || entry.MinPrice.LessThan(overall.MinPrice) {
+			overall = entry
+		}
+		if entry.Date.Before(from) || entry.Date.After(to) {
+			continue
+		}
+		if inWindow == nil || entry.MinPrice.LessThan(inWindow.MinPrice) {
+			inWindow = entry
+		}
+	}
+
+	if inWindow != nil {
+		date := inWindow.Date
+		return historicalFare{price: inWindow.MinPrice, currency: inWindow.Currency, date: &date}, true
+	}
+	if overall != nil {
+		return historicalFare{price: overall.MinPrice, currency: overall.Currency}, true
+	}
+	return historicalFare{}, false
+}
+
+// priceHistory fetches a route's recorded prices from pricing-service
+func (s *ExploreService) priceHistory(origin, destination string) ([]pricingHistoryEntry, error) {
+	query := url.Values{}
+	query.Set("origin", origin)
+	query.Set("destination", destination)
+	query.Set("days", strconv.Itoa(s.cfg.ExploreHistoryDays))
+
+	resp, err := s.httpClient.Get(s.cfg.PricingServiceURL + "/api/v1/pricing/history?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to request price history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price history returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		History []pricingHistoryEntry `json:"history"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode price history: %w", err)
+	}
+	return body.History, nil
+}
+
+// exploreFingerprint identifies an explore request for caching
+func exploreFingerprint(req *models.ExploreRequest) string {
+	key := fmt.Sprintf("v2|%s|%s|%s|%s|%s|%d|%d|%t|%d",
+		req.OriginAirport,
+		req.DateFrom.Format("2006-01-02"),
+		req.DateTo.Format("2006-01-02"),
+		req.MaxBudget.String(),
+		req.Currency,
+		req.MinDurationMinutes,
+		req.MaxDurationMinutes,
+		req.DirectOnly,
+		req.Limit,
+	)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
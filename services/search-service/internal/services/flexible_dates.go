@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/models"
+)
+
+const matrixDateLayout = "2006-01-02"
+
+// errFlexibleSearchTimeout marks the matrix cells that were not searched before the deadline
+var errFlexibleSearchTimeout = errors.New("flexible date search timed out")
+
+// matrixCellResult is the outcome of searching one date combination
+type matrixCellResult struct {
+	i, j     int
+	response *models.FlightSearchResponse
+	err      error
+}
+
+// dateSearchFunc searches a single date combination of a flexible search
+type dateSearchFunc func(req *models.FlightSearchRequest) (*models.FlightSearchResponse, error)
+
+// searchFlexibleDates searches every departure and return date in the flexible window and
+// builds a price matrix. Each date combination goes through searchDate, so overlapping
+// windows reuse the cached per-date results instead of querying providers again.
+func (s *SearchService) searchFlexibleDates(req *models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
+	startTime := time.Now()
+	matrix, responses := s.searchPriceMatrix(req, s.searchDate)
+
+	// Pick the cheapest cell and the results for the dates the user asked for
+	var requested *models.FlightSearchResponse
+	var firstErr string
+	searched, cached := 0, 0
+	for i := range matrix.Cells {
+		for j := range matrix.Cells[i] {
+			cell := &matrix.Cells[i][j]
+			if cell.Error != "" && firstErr == "" {
+				firstErr = cell.Error
+			}
+
+			response := responses[i][j]
+			if response == nil {
+				continue
+			}
+			searched++
+			if cell.FromCache {
+				cached++
+			}
+
+			if isRequestedCell(cell, req) {
+				requested = response
+			}
+			if cell.Price != nil && (matrix.Cheapest == nil || cell.Price.LessThan(*matrix.Cheapest.Price)) {
+				cheapest := *cell
+				matrix.Cheapest = &cheapest
+			}
+		}
+	}
+
+	if searched == 0 {
+		if firstErr == "" {
+			firstErr = "no valid date combinations in window"
+		}
+		return nil, fmt.Errorf("flexible date search failed: %s", firstErr)
+	}
+
+	response := &models.FlightSearchResponse{
+		RequestID: uuid.New().String(),
+		Flights:   []models.Flight{},
+		SearchMetadata: models.SearchMetadata{
+			Currency: matrix.Currency,
+		},
+	}
+	allFlights := response.Flights
+	if requested != nil {
+		*response = *requested
+		allFlights = s.fullResultSet(requested)
+	}
+
+	response.SearchID = req.ID
+	response.SearchRequest = *req
+	response.PriceMatrix = matrix
+	response.SearchMetadata.SearchTime = time.Since(startTime)
+	response.SearchMetadata.CacheHit = cached == searched
+	response.SearchMetadata.FromCache = cached == searched
+	response.CreatedAt = time.Now()
+	response.ExpiresAt = time.Now().Add(s.cfg.SearchResultsCacheTTL)
+
+	// Cache under the flexible search's own ID so it can be fetched, filtered, shared and
+	// snapshotted like any other search
+	s.cacheResultSet(response, allFlights)
+
+	return response, nil
+}
+
+// searchPriceMatrix searches the date combinations of a flexible window in parallel with search
+// and returns the matrix with each cell's response, nil for cells that failed or were skipped.
+// Cells still unanswered at FlexibleSearchTimeout are reported as timed out; their searches
+// finish in the background and warm the cache.
+func (s *SearchService) searchPriceMatrix(req *models.FlightSearchRequest, search dateSearchFunc) (*models.PriceMatrix, [][]*models.FlightSearchResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.FlexibleSearchTimeout)
+	defer cancel()
+
+	departureDates := flexibleDateWindow(req.DepartureDate, req.FlexibleDatesRange)
+	var returnDates []time.Time
+	if req.ReturnDate != nil {
+		returnDates = flexibleDateWindow(*req.ReturnDate, req.FlexibleDatesRange)
+	}
+
+	columns := len(returnDates)
+	if columns == 0 {
+		columns = 1
+	}
+
+	matrix := &models.PriceMatrix{
+		DepartureDates: formatMatrixDates(departureDates),
+		ReturnDates:    formatMatrixDates(returnDates),
+		Cells:          make([][]models.PriceMatrixCell, len(departureDates)),
+		Currency:       s.displayCurrency(req.Currency),
+	}
+	responses := make([][]*models.FlightSearchResponse, len(departureDates))
+
+	concurrency := s.cfg.FlexibleSearchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	results := make(chan matrixCellResult, len(departureDates)*columns)
+	launched := 0
+
+	for i, departure := range departureDates {
+		matrix.Cells[i] = make([]models.PriceMatrixCell, columns)
+		responses[i] = make([]*models.FlightSearchResponse, columns)
+
+		for j := 0; j < columns; j++ {
+			cell := &matrix.Cells[i][j]
+			cell.DepartureDate = departure.Format(matrixDateLayout)
+
+			cellReq := *req
+			cellReq.ID = uuid.New()
+			cellReq.DepartureDate = departure
+			cellReq.FlexibleDates = false
+			cellReq.FlexibleDatesRange = 0
+
+			if len(returnDates) > 0 {
+				returnDate := returnDates[j]
+				cell.ReturnDate = returnDate.Format(matrixDateLayout)
+				if returnDate.Before(departure) {
+					continue
+				}
+				cellReq.ReturnDate = &returnDate
+			}
+
+			launched++
+			go func(i, j int, cellReq models.FlightSearchRequest) {
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					results <- matrixCellResult{i: i, j: j, err: errFlexibleSearchTimeout}
+					return
+				}
+				defer func() { <-semaphore }()
+
+				response, err := search(&cellReq)
+				results <- matrixCellResult{i: i, j: j, response: response, err: err}
+			}(i, j, cellReq)
+		}
+	}
+
+	answered := make([][]bool, len(departureDates))
+	for i := range answered {
+		answered[i] = make([]bool, columns)
+	}
+collect:
+	for ; launched > 0; launched-- {
+		select {
+		case result := <-results:
+			answered[result.i][result.j] = true
+			if result.err != nil {
+				matrix.Cells[result.i][result.j].Error = result.err.Error()
+				continue
+			}
+			responses[result.i][result.j] = result.response
+			fillMatrixCell(&matrix.Cells[result.i][result.j], result.response)
+		case <-ctx.Done():
+			break collect
+		}
+	}
+	if launched > 0 {
+		for i := range matrix.Cells {
+			for j := range matrix.Cells[i] {
+				cell := &matrix.Cells[i][j]
+				if !answered[i][j] && !isSkippedCell(cell) {
+					cell.Error = errFlexibleSearchTimeout.Error()
+				}
+			}
+		}
+	}
+
+	return matrix, responses
+}
+
+// fullResultSet returns every flight of a date search, not just the returned page
+func (s *SearchService) fullResultSet(response *models.FlightSearchResponse) []models.Flight {
+	resultSet, err := s.cachedResultSet(response.SearchID)
+	if err != nil {
+		log.Printf("Full results of search %s unavailable, keeping the returned page: %v", response.SearchID, err)
+		return response.Flights
+	}
+	return resultSet.Flights
+}
+
+// isSkippedCell reports whether a cell was never searched because it returns before it departs
+func isSkippedCell(cell *models.PriceMatrixCell) bool {
+	return cell.ReturnDate != "" && cell.ReturnDate < cell.DepartureDate
+}
+
+// flexibleDateWindow returns the days within rangeDays of date, skipping days in the past
+func flexibleDateWindow(date time.Time, rangeDays int) []time.Time {
+	today := time.Now().Truncate(24 * time.Hour)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	dates := make([]time.Time, 0, 2*rangeDays+1)
+	for offset := -rangeDays; offset <= rangeDays; offset++ {
+		candidate := day.AddDate(0, 0, offset)
+		if candidate.Before(today) {
+			continue
+		}
+		dates = append(dates, candidate)
+	}
+	return dates
+}
+
+// formatMatrixDates formats the matrix axis labels
+func formatMatrixDates(dates []time.Time) []string {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	formatted := make([]string, len(dates))
+	for i, date := range dates {
+		formatted[i] = date.Format(matrixDateLayout)
+	}
+	return formatted
+}
+
+// fillMatrixCell records the cheapest fare of a date search in its matrix cell
+func fillMatrixCell(cell *models.PriceMatrixCell, response *models.FlightSearchResponse) {
+	searchID := response.SearchID
+	cell.SearchID = &searchID
+	cell.FromCache = response.SearchMetadata.FromCache
+
+	if len(response.Flights) > 0 {
+		price := response.SearchMetadata.PriceRange.MinPrice
+		cell.Price = &price
+	}
+}
+
+// isRequestedCell reports whether the cell matches the dates in the original request
+func isRequestedCell(cell *models.PriceMatrixCell, req *models.FlightSearchRequest) bool {
+	if cell.DepartureDate != req.DepartureDate.Format(matrixDateLayout) {
+		return false
+	}
+	if req.ReturnDate == nil {
+		return true
+	}
+	return cell.ReturnDate == req.ReturnDate.Format(matrixDateLayout)
+}
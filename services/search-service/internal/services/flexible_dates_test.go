@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/models"
+	"spontra/shared/fx"
+)
+
+// matrixSearch answers date searches with a fixed fare, failing or holding the combinations it
+// is told to, and records every combination it was asked for
+type matrixSearch struct {
+	mu       sync.Mutex
+	searched map[string]bool
+	fail     map[string]bool
+	hold     map[string]bool // held until release is closed, past the matrix deadline
+	release  chan struct{}
+}
+
+func (m *matrixSearch) search(req *models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
+	key := matrixKey(req.DepartureDate, *req.ReturnDate)
+	m.mu.Lock()
+	m.searched[key] = true
+	m.mu.Unlock()
+
+	if m.hold[key] {
+		<-m.release
+	}
+	if m.fail[key] {
+		return nil, errors.New("upstream unavailable")
+	}
+
+	response := &models.FlightSearchResponse{
+		SearchID: req.ID,
+		Flights:  []models.Flight{testFlight("amadeus", 100, req.DepartureDate)},
+	}
+	response.SearchMetadata.PriceRange.MinPrice = decimal.NewFromInt(100)
+	return response, nil
+}
+
+func (m *matrixSearch) wasSearched(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.searched[key]
+}
+
+func matrixKey(departure, ret time.Time) string {
+	return departure.Format(matrixDateLayout) + "/" + ret.Format(matrixDateLayout)
+}
+
+func TestSearchPriceMatrix(t *testing.T) {
+	departure := time.Now().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	ret := departure.AddDate(0, 0, 1)
+	day := func(offset int) time.Time { return departure.AddDate(0, 0, offset) }
+
+	// The window is a day either way; returning the day before departing is skipped
+	var everyCell []string
+	for _, dep := range []int{-1, 0, 1} {
+		for _, back := range []int{0, 1, 2} {
+			if back >= dep {
+				everyCell = append(everyCell, matrixKey(day(dep), day(back)))
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		fail        []string
+		hold        []string
+		wantTimeout []string // every other searched cell is priced
+	}{
+		{
+			name:        "slow and failing cells",
+			concurrency: 8,
+			fail:        []string{matrixKey(day(-1), day(0))},
+			hold:        []string{matrixKey(day(1), day(2))},
+			wantTimeout: []string{matrixKey(day(1), day(2))},
+		},
+		{
+			// Every search holds its slot past the deadline, so the cells queued behind it time out too
+			name:        "queued cells",
+			concurrency: 1,
+			hold:        everyCell,
+			wantTimeout: everyCell,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &matrixSearch{searched: map[string]bool{}, fail: map[string]bool{}, hold: map[string]bool{}, release: make(chan struct{})}
+			defer close(fake.release)
+			for _, key := range tt.fail {
+				fake.fail[key] = true
+			}
+			for _, key := range tt.hold {
+				fake.hold[key] = true
+			}
+			timeouts := map[string]bool{}
+			for _, key := range tt.wantTimeout {
+				timeouts[key] = true
+			}
+
+			s := &SearchService{
+				cfg: &config.Config{
+					FlexibleSearchConcurrency: tt.concurrency,
+					FlexibleSearchTimeout:     50 * time.Millisecond,
+				},
+				converter: fx.NewConverter(fx.ChainSource{}, "EUR", time.Hour),
+			}
+			req := &models.FlightSearchRequest{DepartureDate: departure, ReturnDate: &ret, FlexibleDatesRange: 1}
+
+			start := time.Now()
+			matrix, responses := s.searchPriceMatrix(req, fake.search)
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("searchPriceMatrix() took %v, want it cut off at the timeout", elapsed)
+			}
+
+			if len(matrix.Cells) != 3 || len(matrix.Cells[0]) != 3 {
+				t.Fatalf("matrix is %dx%d, want 3x3", len(matrix.Cells), len(matrix.Cells[0]))
+			}
+			for i := range matrix.Cells {
+				for j := range matrix.Cells[i] {
+					cell := &matrix.Cells[i][j]
+					key := cell.DepartureDate + "/" + cell.ReturnDate
+
+					switch {
+					case isSkippedCell(cell):
+						// Returning before departing is never searched and is no error
+						if fake.wasSearched(key) || cell.Error != "" || responses[i][j] != nil {
+							t.Errorf("skipped cell %s: searched %v, error %q", key, fake.wasSearched(key), cell.Error)
+						}
+					case timeouts[key]:
+						if cell.Error != errFlexibleSearchTimeout.Error() || responses[i][j] != nil {
+							t.Errorf("cell %s error = %q, want a timeout", key, cell.Error)
+						}
+					case fake.fail[key]:
+						if cell.Error != "upstream unavailable" || cell.Price != nil {
+							t.Errorf("cell %s error = %q, want the search error", key, cell.Error)
+						}
+					default:
+						if cell.Error != "" || cell.Price == nil || responses[i][j] == nil {
+							t.Errorf("cell %s = %+v, want it priced", key, cell)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"spontra/search-service/internal/models"
+)
+
+// mergeResult holds the outcome of merging provider results
+type mergeResult struct {
+	flights           []models.Flight
+	duplicatesRemoved int
+	contributions     map[string]models.ProviderContribution
+}
+
+// mergeFlights collapses offers for the same journey returned by several providers.
+// Flights must be in provider priority order; on equal fares the earlier offer wins.
+func mergeFlights(flights []models.Flight) *mergeResult {
+	result := &mergeResult{
+		flights:       make([]models.Flight, 0, len(flights)),
+		contributions: make(map[string]models.ProviderContribution),
+	}
+
+	index := make(map[string]int, len(flights))
+	for _, flight := range flights {
+		contribution := result.contributions[flight.Provider]
+		contribution.Offers++
+		result.contributions[flight.Provider] = contribution
+
+		offer := providerOfferFor(&flight)
+		key := journeyKey(&flight)
+
+		i, seen := index[key]
+		if !seen {
+			flight.ProviderOffers = []models.ProviderOffer{offer}
+			index[key] = len(result.flights)
+			result.flights = append(result.flights, flight)
+			continue
+		}
+
+		result.duplicatesRemoved++
+		existing := &result.flights[i]
+		offers := append(existing.ProviderOffers, offer)
+		refundable := existing.IsRefundable && flight.IsRefundable
+		baggage := existing.BaggageIncluded && flight.BaggageIncluded
+
+		if flight.Price.LessThan(existing.Price) {
+			flight.ID = existing.ID
+			*existing = flight
+		}
+		existing.ProviderOffers = offers
+		// Only promise refunds or bags when every provider agrees
+		existing.IsRefundable = refundable
+		existing.BaggageIncluded = baggage
+	}
+
+	for _, flight := range result.flights {
+		providers := make(map[string]bool, len(flight.ProviderOffers))
+		for _, offer := range flight.ProviderOffers {
+			providers[offer.Provider] = true
+		}
+
+		if len(providers) == 1 {
+			contribution := result.contributions[flight.Provider]
+			contribution.Unique++
+			result.contributions[flight.Provider] = contribution
+		}
+
+		contribution := result.contributions[flight.Provider]
+		contribution.Cheapest++
+		result.contributions[flight.Provider] = contribution
+	}
+
+	return result
+}
+
+// providerOfferFor captures a provider's fare before it is merged away
+func providerOfferFor(flight *models.Flight) models.ProviderOffer {
+	return models.ProviderOffer{
+		Provider:        flight.Provider,
+		Price:           flight.Price,
+		Currency:        flight.Currency,
+		BookingURL:      flight.BookingURL,
+		IsRefundable:    flight.IsRefundable,
+		BaggageIncluded: flight.BaggageIncluded,
+	}
+}
+
+// journeyKey identifies a journey by airline, flight numbers, departure times and cabin
+func journeyKey(flight *models.Flight) string {
+	key := legKey(flight)
+	if flight.ReturnFlight != nil {
+		key += "|" + legKey(flight.ReturnFlight)
+	}
+	for i := range flight.Legs {
+		key += "|" + legKey(&flight.Legs[i])
+	}
+	return key
+}
+
+// legKey identifies a single direction of a journey
+func legKey(flight *models.Flight) string {
+	departures := make([]string, 0, len(flight.StopDetails)+1)
+	departures = append(departures, flight.DepartureTime.UTC().Format(time.RFC3339))
+	for _, stop := range flight.StopDetails {
+		if !stop.DepartureTime.IsZero() {
+			departures = append(departures, stop.DepartureTime.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return strings.Join([]string{
+		strings.ToUpper(strings.TrimSpace(flight.Airline)),
+		normalizeFlightNumbers(flight.FlightNumber),
+		strings.Join(departures, ","),
+		strings.ToLower(strings.TrimSpace(flight.CabinClass)),
+	}, "|")
+}
+
+// normalizeFlightNumbers strips spacing and leading zeros so "BA 0123" matches "BA123"
+func normalizeFlightNumbers(flightNumber string) string {
+	parts := strings.FieldsFunc(strings.ToUpper(flightNumber), func(r rune) bool {
+		return r == '/' || r == ','
+	})
+
+	normalized := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ReplaceAll(part, " ", "")
+		if len(part) > 2 {
+			carrier, number := part[:2], strings.TrimLeft(part[2:], "0")
+			part = carrier + number
+		}
+		normalized = append(normalized, part)
+	}
+
+	return strings.Join(normalized, "/")
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+)
+
+// testFlight builds a BA123 economy flight departing at the given time
+func testFlight(provider string, price int64, departure time.Time) models.Flight {
+	return models.Flight{
+		ID:            uuid.New(),
+		Provider:      provider,
+		Airline:       "BA",
+		FlightNumber:  "BA123",
+		CabinClass:    "economy",
+		DepartureTime: departure,
+		Price:         decimal.NewFromInt(price),
+		Currency:      "EUR",
+	}
+}
+
+func TestNormalizeFlightNumbers(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"BA123", "BA123"},
+		{"BA 0123", "BA123"},
+		{"ba0123", "BA123"},
+		{"BA0123/IB 045", "BA123/IB45"},
+		{"BA123,IB45", "BA123/IB45"},
+		{"U2", "U2"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeFlightNumbers(tt.in); got != tt.want {
+			t.Errorf("normalizeFlightNumbers(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJourneyKey(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+	base := testFlight("amadeus", 100, departure)
+
+	returnFlight := testFlight("amadeus", 0, departure.AddDate(0, 0, 7))
+
+	tests := []struct {
+		name   string
+		modify func(f *models.Flight)
+		same   bool
+	}{
+		{"other provider and price", func(f *models.Flight) { f.Provider = "elasticsearch"; f.Price = decimal.NewFromInt(90) }, true},
+		{"flight number spelling", func(f *models.Flight) { f.FlightNumber = "BA 0123" }, true},
+		{"airline case and cabin case", func(f *models.Flight) { f.Airline = " ba"; f.CabinClass = "Economy" }, true},
+		{"departure in another zone", func(f *models.Flight) { f.DepartureTime = departure.In(time.FixedZone("CET", 3600)) }, true},
+		{"other departure", func(f *models.Flight) { f.DepartureTime = departure.Add(time.Hour) }, false},
+		{"other cabin", func(f *models.Flight) { f.CabinClass = "business" }, false},
+		{"other flight number", func(f *models.Flight) { f.FlightNumber = "BA125" }, false},
+		{"connection departure", func(f *models.Flight) {
+			f.StopDetails = []models.Stop{{DepartureTime: departure.Add(3 * time.Hour)}}
+		}, false},
+		{"return flight", func(f *models.Flight) { f.ReturnFlight = &returnFlight }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+			if same := journeyKey(&base) == journeyKey(&other); same != tt.same {
+				t.Errorf("keys equal = %t, want %t (%q vs %q)", same, tt.same, journeyKey(&base), journeyKey(&other))
+			}
+		})
+	}
+}
+
+func TestMergeFlights(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		flights           func() []models.Flight
+		wantFlights       int
+		wantDuplicates    int
+		wantPrice         []int64
+		wantProvider      []string
+		wantOffers        []int
+		wantContributions map[string]models.ProviderContribution
+	}{
+		{
+			name: "distinct journeys",
+			flights: func() []models.Flight {
+				return []models.Flight{
+					testFlight("amadeus", 100, departure),
+					testFlight("amadeus", 120, departure.Add(time.Hour)),
+				}
+			},
+			wantFlights:    2,
+			wantDuplicates: 0,
+			wantPrice:      []int64{100, 120},
+			wantProvider:   []string{"amadeus", "amadeus"},
+			wantOffers:     []int{1, 1},
+			wantContributions: map[string]models.ProviderContribution{
+				"amadeus": {Offers: 2, Unique: 2, Cheapest: 2},
+			},
+		},
+		{
+			name: "cheaper later offer wins",
+			flights: func() []models.Flight {
+				return []models.Flight{
+					testFlight("elasticsearch", 100, departure),
+					testFlight("amadeus", 90, departure),
+				}
+			},
+			wantFlights:    1,
+			wantDuplicates: 1,
+			wantPrice:      []int64{90},
+			wantProvider:   []string{"amadeus"},
+			wantOffers:     []int{2},
+			wantContributions: map[string]models.ProviderContribution{
+				"elasticsearch": {Offers: 1},
+				"amadeus":       {Offers: 1, Cheapest: 1},
+			},
+		},
+		{
+			name: "equal fares keep the earlier offer",
+			flights: func() []models.Flight {
+				return []models.Flight{
+					testFlight("elasticsearch", 100, departure),
+					testFlight("amadeus", 100, departure),
+				}
+			},
+			wantFlights:    1,
+			wantDuplicates: 1,
+			wantPrice:      []int64{100},
+			wantProvider:   []string{"elasticsearch"},
+			wantOffers:     []int{2},
+			wantContributions: map[string]models.ProviderContribution{
+				"elasticsearch": {Offers: 1, Cheapest: 1},
+				"amadeus":       {Offers: 1},
+			},
+		},
+		{
+			name:              "no flights",
+			flights:           func() []models.Flight { return nil },
+			wantContributions: map[string]models.ProviderContribution{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flights := tt.flights()
+			result := mergeFlights(flights)
+
+			if len(result.flights) != tt.wantFlights {
+				t.Fatalf("flights = %d, want %d", len(result.flights), tt.wantFlights)
+			}
+			if result.duplicatesRemoved != tt.wantDuplicates {
+				t.Errorf("duplicatesRemoved = %d, want %d", result.duplicatesRemoved, tt.wantDuplicates)
+			}
+			for i, flight := range result.flights {
+				if !flight.Price.Equal(decimal.NewFromInt(tt.wantPrice[i])) {
+					t.Errorf("flight %d price = %s, want %d", i, flight.Price, tt.wantPrice[i])
+				}
+				if flight.Provider != tt.wantProvider[i] {
+					t.Errorf("flight %d provider = %s, want %s", i, flight.Provider, tt.wantProvider[i])
+				}
+				if len(flight.ProviderOffers) != tt.wantOffers[i] {
+					t.Errorf("flight %d offers = %d, want %d", i, len(flight.ProviderOffers), tt.wantOffers[i])
+				}
+			}
+			if len(result.contributions) != len(tt.wantContributions) {
+				t.Errorf("contributions = %v, want %v", result.contributions, tt.wantContributions)
+			}
+			for provider, want := range tt.wantContributions {
+				if got := result.contributions[provider]; got != want {
+					t.Errorf("contribution of %s = %+v, want %+v", provider, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeFlightsKeepsFirstIDAndAgreedConditions(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+
+	first := testFlight("elasticsearch", 100, departure)
+	first.IsRefundable = true
+	first.BaggageIncluded = true
+	second := testFlight("amadeus", 90, departure)
+	second.IsRefundable = true
+
+	result := mergeFlights([]models.Flight{first, second})
+	if len(result.flights) != 1 {
+		t.Fatalf("flights = %d, want 1", len(result.flights))
+	}
+
+	merged := result.flights[0]
+	if merged.ID != first.ID {
+		t.Errorf("ID = %s, want the first offer's %s", merged.ID, first.ID)
+	}
+	if !merged.IsRefundable {
+		t.Error("IsRefundable = false, want true when every provider agrees")
+	}
+	if merged.BaggageIncluded {
+		t.Error("BaggageIncluded = true, want false when a provider disagrees")
+	}
+}
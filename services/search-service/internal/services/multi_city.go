@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/providers"
+)
+
+// maxSearchLegs matches the number of origin-destinations Amadeus accepts
+const maxSearchLegs = 6
+
+// validateLegs checks a multi-city request and derives its overall route from the legs
+func validateLegs(req *models.FlightSearchRequest) error {
+	if len(req.Legs) < 2 {
+		return fmt.Errorf("multi-city trips need at least 2 legs")
+	}
+	if len(req.Legs) > maxSearchLegs {
+		return fmt.Errorf("multi-city trips cannot have more than %d legs", maxSearchLegs)
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	for i := range req.Legs {
+		leg := &req.Legs[i]
+		leg.OriginAirport = normalizeAirport(leg.OriginAirport)
+		leg.DestinationAirport = normalizeAirport(leg.DestinationAirport)
+
+		if leg.OriginAirport == "" || leg.DestinationAirport == "" {
+			return fmt.Errorf("leg %d: origin and destination airports are required", i+1)
+		}
+		if leg.OriginAirport == leg.DestinationAirport {
+			return fmt.Errorf("leg %d: origin and destination cannot be the same", i+1)
+		}
+		if leg.DepartureDate.IsZero() {
+			return fmt.Errorf("leg %d: departure date is required", i+1)
+		}
+		if leg.DepartureDate.Before(today) {
+			return fmt.Errorf("leg %d: departure date cannot be in the past", i+1)
+		}
+		if i > 0 && leg.DepartureDate.Before(req.Legs[i-1].DepartureDate) {
+			return fmt.Errorf("leg %d: departure date is before the previous leg", i+1)
+		}
+	}
+
+	first, last := req.Legs[0], req.Legs[len(req.Legs)-1]
+	req.OriginAirport = first.OriginAirport
+	req.DestinationAirport = last.DestinationAirport
+	req.DepartureDate = first.DepartureDate
+	req.ReturnDate = nil
+	req.FlexibleDates = false
+
+	return nil
+}
+
+// supportingProviders drops providers that cannot answer a multi-city request
+func supportingProviders(enabled []providers.FlightProvider, req *models.FlightSearchRequest) ([]providers.FlightProvider, []string) {
+	if len(req.Legs) == 0 {
+		return enabled, nil
+	}
+
+	supported := make([]providers.FlightProvider, 0, len(enabled))
+	var unsupported []string
+	for _, provider := range enabled {
+		if provider.Capabilities().MultiCity {
+			supported = append(supported, provider)
+			continue
+		}
+		unsupported = append(unsupported, provider.Name())
+	}
+	return supported, unsupported
+}
+
+// buildLegGroups lists the distinct flights for each leg, priced by the cheapest itinerary containing
+// them. Itineraries are compared by their effective price, so declared travel needs count.
+func buildLegGroups(legs []models.SearchLeg, itineraries []models.Flight) []models.LegGroup {
+	groups := make([]models.LegGroup, len(legs))
+	for i, leg := range legs {
+		group := models.LegGroup{
+			Index:              i,
+			OriginAirport:      leg.OriginAirport,
+			DestinationAirport: leg.DestinationAirport,
+			DepartureDate:      leg.DepartureDate,
+			Flights:            []models.Flight{},
+		}
+
+		index := make(map[string]int)
+		for _, itinerary := range itineraries {
+			if len(itinerary.Legs) != len(legs) {
+				continue
+			}
+
+			flight := itinerary.Legs[i]
+			flight.Price = itinerary.Price
+			flight.Currency = itinerary.Currency
+			flight.TripPrice = itinerary.TripPrice
+
+			key := legKey(&flight)
+			if existing, seen := index[key]; seen {
+				if effectivePrice(&flight).LessThan(effectivePrice(&group.Flights[existing])) {
+					group.Flights[existing].Price = flight.Price
+					group.Flights[existing].TripPrice = flight.TripPrice
+				}
+				continue
+			}
+			index[key] = len(group.Flights)
+			group.Flights = append(group.Flights, flight)
+		}
+
+		sort.SliceStable(group.Flights, func(a, b int) bool {
+			return effectivePrice(&group.Flights[a]).LessThan(effectivePrice(&group.Flights[b]))
+		})
+		if len(group.Flights) > 0 {
+			group.CheapestPrice = effectivePrice(&group.Flights[0])
+		}
+
+		groups[i] = group
+	}
+
+	return groups
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"spontra/search-service/internal/models"
+)
+
+// tagParetoFrontier marks flights dominated on price, duration and stops and tags the
+// cheapest, fastest and best flights of the frontier. It returns the flight ID for each
+// tag and the size of the frontier.
+func tagParetoFrontier(flights []models.Flight) (map[string]uuid.UUID, int) {
+	tagged := make(map[string]uuid.UUID)
+	if len(flights) == 0 {
+		return tagged, 0
+	}
+
+	var frontier []int
+	for i := range flights {
+		flights[i].Tags = nil
+		flights[i].Dominated = false
+
+		for j := range flights {
+			if i != j && dominates(&flights[j], &flights[i]) {
+				flights[i].Dominated = true
+				break
+			}
+		}
+		if !flights[i].Dominated {
+			frontier = append(frontier, i)
+		}
+	}
+
+	cheapest, fastest, best := frontier[0], frontier[0], frontier[0]
+	for _, i := range frontier[1:] {
+		flight := &flights[i]
+		price, cheapestPrice := effectivePrice(flight), effectivePrice(&flights[cheapest])
+		if price.LessThan(cheapestPrice) || (price.Equal(cheapestPrice) && flight.Duration < flights[cheapest].Duration) {
+			cheapest = i
+		}
+		if flight.Duration < flights[fastest].Duration ||
+			(flight.Duration == flights[fastest].Duration && price.LessThan(effectivePrice(&flights[fastest]))) {
+			fastest = i
+		}
+		// The best flight balances the criteria as weighted by the relevance score
+		if flight.RelevanceScore > flights[best].RelevanceScore ||
+			(flight.RelevanceScore == flights[best].RelevanceScore && price.LessThan(effectivePrice(&flights[best]))) {
+			best = i
+		}
+	}
+
+	// Tag in a fixed order so a flight holding several tags lists them consistently
+	for _, pick := range []struct {
+		tag   string
+		index int
+	}{
+		{models.FlightTagCheapest, cheapest},
+		{models.FlightTagFastest, fastest},
+		{models.FlightTagBest, best},
+	} {
+		flights[pick.index].Tags = append(flights[pick.index].Tags, pick.tag)
+		tagged[pick.tag] = flights[pick.index].ID
+	}
+
+	return tagged, len(frontier)
+}
+
+// dominates reports whether a is no worse than b on price, duration and stops and better on at least one
+func dominates(a, b *models.Flight) bool {
+	aPrice, bPrice := effectivePrice(a), effectivePrice(b)
+	if aPrice.GreaterThan(bPrice) || a.Duration > b.Duration || a.Stops > b.Stops {
+		return false
+	}
+	return aPrice.LessThan(bPrice) || a.Duration < b.Duration || a.Stops < b.Stops
+}
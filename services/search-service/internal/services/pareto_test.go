@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+)
+
+// paretoFlight builds a flight with the criteria the frontier compares
+func paretoFlight(price int64, duration, stops int, relevance float64) models.Flight {
+	return models.Flight{
+		ID:             uuid.New(),
+		Price:          decimal.NewFromInt(price),
+		Duration:       duration,
+		Stops:          stops,
+		RelevanceScore: relevance,
+	}
+}
+
+func TestTagParetoFrontier(t *testing.T) {
+	tests := []struct {
+		name          string
+		flights       []models.Flight
+		wantDominated []bool
+		wantFrontier  int
+		wantTags      map[string]int // tag to index of the flight holding it
+	}{
+		{
+			name: "trade-off between price and duration",
+			flights: []models.Flight{
+				paretoFlight(100, 300, 1, 0.5),
+				paretoFlight(200, 120, 0, 0.9),
+				paretoFlight(250, 300, 1, 0.1), // worse than the first on every criterion
+			},
+			wantDominated: []bool{false, false, true},
+			wantFrontier:  2,
+			wantTags: map[string]int{
+				models.FlightTagCheapest: 0,
+				models.FlightTagFastest:  1,
+				models.FlightTagBest:     1,
+			},
+		},
+		{
+			name: "one flight holds every tag",
+			flights: []models.Flight{
+				paretoFlight(100, 120, 0, 0.4),
+				paretoFlight(150, 180, 1, 0.9),
+			},
+			wantDominated: []bool{false, true},
+			wantFrontier:  1,
+			wantTags: map[string]int{
+				models.FlightTagCheapest: 0,
+				models.FlightTagFastest:  0,
+				models.FlightTagBest:     0,
+			},
+		},
+		{
+			name: "identical flights do not dominate each other",
+			flights: []models.Flight{
+				paretoFlight(100, 120, 0, 0.5),
+				paretoFlight(100, 120, 0, 0.5),
+			},
+			wantDominated: []bool{false, false},
+			wantFrontier:  2,
+			wantTags: map[string]int{
+				models.FlightTagCheapest: 0,
+				models.FlightTagFastest:  0,
+				models.FlightTagBest:     0,
+			},
+		},
+		{
+			name:          "no flights",
+			wantDominated: []bool{},
+			wantTags:      map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagged, frontier := tagParetoFrontier(tt.flights)
+
+			if frontier != tt.wantFrontier {
+				t.Errorf("frontier = %d, want %d", frontier, tt.wantFrontier)
+			}
+			for i, want := range tt.wantDominated {
+				if tt.flights[i].Dominated != want {
+					t.Errorf("flight %d dominated = %t, want %t", i, tt.flights[i].Dominated, want)
+				}
+			}
+			if len(tagged) != len(tt.wantTags) {
+				t.Errorf("tagged = %v, want %d tags", tagged, len(tt.wantTags))
+			}
+			for tag, index := range tt.wantTags {
+				if tagged[tag] != tt.flights[index].ID {
+					t.Errorf("%s = %s, want flight %d", tag, tagged[tag], index)
+				}
+			}
+		})
+	}
+}
+
+func TestTagParetoFrontierUsesTripPrice(t *testing.T) {
+	// The cheaper fare needs an expensive bag, so the all-in fare is the cheapest trip
+	low := paretoFlight(100, 120, 0, 0.5)
+	low.TripPrice = &models.TripPrice{Fare: low.Price, Total: decimal.NewFromInt(160)}
+	allIn := paretoFlight(130, 120, 0, 0.5)
+	allIn.TripPrice = &models.TripPrice{Fare: allIn.Price, Total: allIn.Price}
+
+	flights := []models.Flight{low, allIn}
+	tagged, _ := tagParetoFrontier(flights)
+
+	if tagged[models.FlightTagCheapest] != allIn.ID {
+		t.Errorf("cheapest = %s, want the all-in fare %s", tagged[models.FlightTagCheapest], allIn.ID)
+	}
+	if !flights[0].Dominated {
+		t.Error("low fare with an expensive bag is not dominated")
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"spontra/search-service/internal/models"
+)
+
+// Relevance score components, also used as RELEVANCE_WEIGHTS keys
+const (
+	scorePrice     = "price"
+	scoreDuration  = "duration"
+	scoreStops     = "stops"
+	scoreDeparture = "departure"
+	scoreActivity  = "activity"
+)
+
+// departureWindows maps a preferred departure window onto local hours [start, end)
+var departureWindows = map[string][2]int{
+	"morning":   {5, 12},
+	"afternoon": {12, 17},
+	"evening":   {17, 22},
+	"night":     {22, 29}, // runs past midnight until 05:00
+}
+
+// destinationActivity mirrors the activity entries of data-ingestion-service's destination
+type destinationActivity struct {
+	Type  string  `json:"type"`
+	Score float64 `json:"score"` // 0-10
+}
+
+// scoreFlights sets RelevanceScore and ActivityMatch on every flight.
+// Price and duration are scored relative to the other flights in the set.
+func (s *SearchService) scoreFlights(req *models.FlightSearchRequest, flights []models.Flight) {
+	if len(flights) == 0 {
+		return
+	}
+
+	minPrice := effectivePrice(&flights[0]).InexactFloat64()
+	maxPrice := minPrice
+	minDuration, maxDuration := flights[0].Duration, flights[0].Duration
+	for _, flight := range flights[1:] {
+		price := effectivePrice(&flight).InexactFloat64()
+		minPrice, maxPrice = math.Min(minPrice, price), math.Max(maxPrice, price)
+		if flight.Duration < minDuration {
+			minDuration = flight.Duration
+		}
+		if flight.Duration > maxDuration {
+			maxDuration = flight.Duration
+		}
+	}
+
+	window, hasWindow := departureWindows[strings.ToLower(req.PreferredDepartureWindow)]
+	activityScores := s.activityMatches(req, flights)
+
+	for i := range flights {
+		flight := &flights[i]
+		breakdown := models.ScoreBreakdown{
+			Price:    relativeScore(effectivePrice(flight).InexactFloat64(), minPrice, maxPrice),
+			Duration: relativeScore(float64(flight.Duration), float64(minDuration), float64(maxDuration)),
+			Stops:    1 / float64(1+flight.Stops),
+			Weights:  make(map[string]float64),
+		}
+
+		components := map[string]float64{
+			scorePrice:    breakdown.Price,
+			scoreDuration: breakdown.Duration,
+			scoreStops:    breakdown.Stops,
+		}
+		if hasWindow {
+			breakdown.DepartureFit = departureFit(flight.DepartureTime.Hour(), window)
+			components[scoreDeparture] = breakdown.DepartureFit
+		}
+		if match, ok := activityScores[flight.DestinationAirport]; ok {
+			breakdown.ActivityMatch = match
+			flight.ActivityMatch = match
+			components[scoreActivity] = match
+		}
+
+		// Components without a signal are dropped and the remaining weights renormalised
+		totalWeight := 0.0
+		for name := range components {
+			totalWeight += s.cfg.RelevanceWeights[name]
+		}
+
+		score := 0.0
+		if totalWeight > 0 {
+			for name, value := range components {
+				weight := s.cfg.RelevanceWeights[name] / totalWeight
+				breakdown.Weights[name] = math.Round(weight*1000) / 1000
+				score += weight * value
+			}
+		}
+
+		flight.RelevanceScore = math.Round(score*1000) / 1000
+		flight.ScoreBreakdown = nil
+		if req.IncludeScoreBreakdown {
+			flight.ScoreBreakdown = &breakdown
+		}
+	}
+}
+
+// relativeScore maps value onto 0..1 where the lowest value in the set scores 1
+func relativeScore(value, min, max float64) float64 {
+	if max <= min {
+		return 1
+	}
+	return (max - value) / (max - min)
+}
+
+// departureFit scores 1 inside the preferred window, falling to 0 six hours outside it
+func departureFit(hour int, window [2]int) float64 {
+	if hour < window[0] && window[1] > 24 {
+		hour += 24
+	}
+	if hour >= window[0] && hour < window[1] {
+		return 1
+	}
+
+	distance := window[0] - hour
+	if hour >= window[1] {
+		distance = hour - window[1] + 1
+	}
+	return math.Max(0, 1-float64(distance)/6)
+}
+
+// activityMatches returns, per destination airport, how well it matches the preferred activities.
+// Destinations are looked up in parallel; those that fail or time out are left unscored.
+func (s *SearchService) activityMatches(req *models.FlightSearchRequest, flights []models.Flight) map[string]float64 {
+	matches := make(map[string]float64)
+	if len(req.PreferredActivities) == 0 {
+		return matches
+	}
+
+	airports := make(map[string]bool)
+	for _, flight := range flights {
+		airports[flight.DestinationAirport] = true
+	}
+
+	concurrency := s.cfg.ActivityLookupConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for airport := range airports {
+		wg.Add(1)
+		go func(airport string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			activities, err := s.destinationActivities(airport)
+			if err != nil {
+				log.Printf("Failed to load activity scores for %s: %v", airport, err)
+				return
+			}
+			if len(activities) == 0 {
+				return
+			}
+
+			total := 0.0
+			for _, preferred := range req.PreferredActivities {
+				for _, activity := range activities {
+					if strings.EqualFold(activity.Type, preferred) {
+						total += activity.Score
+						break
+					}
+				}
+			}
+
+			mu.Lock()
+			matches[airport] = math.Round(total/float64(len(req.PreferredActivities))/10*1000) / 1000
+			mu.Unlock()
+		}(airport)
+	}
+	wg.Wait()
+
+	return matches
+}
+
+// destinationActivities loads a destination's activity scores from data-ingestion-service, cached in Redis
+func (s *SearchService) destinationActivities(airportCode string) ([]destinationActivity, error) {
+	cacheKey := s.cacheKeyBuilder.DestinationActivities(airportCode)
+
+	var activities []destinationActivity
+	if err := s.cache.Get(cacheKey, &activities); err == nil {
+		return activities, nil
+	}
+
+	// Lookups run while a search response is built, so they get a much shorter timeout than providers
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ActivityLookupTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.DataIngestionServiceURL+"/api/v1/data/destinations/"+airportCode, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build destination request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request destination: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var destination struct {
+			Activities []destinationActivity `json:"activities"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&destination); err != nil {
+			return nil, fmt.Errorf("failed to decode destination: %w", err)
+		}
+		activities = destination.Activities
+	case http.StatusNotFound:
+		// Unknown destinations are cached as having no activities
+		activities = []destinationActivity{}
+	default:
+		return nil, fmt.Errorf("destination lookup returned status %d", resp.StatusCode)
+	}
+
+	if err := s.cache.Set(cacheKey, activities, s.cfg.CacheTTL); err != nil {
+		log.Printf("Failed to cache activity scores for %s: %v", airportCode, err)
+	}
+	return activities, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"spontra/search-service/internal/models"
+)
+
+// fingerprintVersion is bumped whenever the fields that make up the fingerprint, or the way
+// cached provider results are prepared, change
+const fingerprintVersion = "v3"
+
+// resultsCacheKey builds the cache key for the provider results of a request
+func (s *SearchService) resultsCacheKey(req *models.FlightSearchRequest) string {
+	return s.cacheKeyBuilder.SearchResults(
+		normalizeAirport(req.OriginAirport),
+		normalizeAirport(req.DestinationAirport),
+		requestFingerprint(req),
+	)
+}
+
+// requestFingerprint hashes the request fields that change what providers return.
+// Filter and sort fields are left out because they are applied after the cache lookup.
+func requestFingerprint(req *models.FlightSearchRequest) string {
+	tripType := normalizeTripType(req.TripType)
+
+	returnDate := ""
+	if tripType == "return" && req.ReturnDate != nil {
+		returnDate = req.ReturnDate.Format("2006-01-02")
+	}
+
+	flexibleRange := 0
+	if req.FlexibleDates {
+		flexibleRange = req.FlexibleDatesRange
+	}
+
+	legs := make([]string, 0, len(req.Legs))
+	for _, leg := range req.Legs {
+		legs = append(legs, normalizeAirport(leg.OriginAirport)+"-"+normalizeAirport(leg.DestinationAirport)+"@"+leg.DepartureDate.Format("2006-01-02"))
+	}
+
+	expansion := ""
+	if req.ExpandAirports && len(req.Legs) == 0 {
+		expansion = "expand:" + strconv.FormatFloat(req.NearbyRadiusKm, 'f', 0, 64)
+	}
+
+	canonical := strings.Join([]string{
+		fingerprintVersion,
+		normalizeAirport(req.OriginAirport),
+		normalizeAirport(req.DestinationAirport),
+		req.DepartureDate.Format("2006-01-02"),
+		returnDate,
+		tripType,
+		normalizeCabinClass(req.CabinClass),
+		strconv.Itoa(req.PassengerCount),
+		strconv.Itoa(flexibleRange),
+		strings.Join(legs, ","),
+		expansion,
+	}, "|")
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:16])
+}
+
+// providerRequest strips the filter and sort fields from a request so providers
+// return the full result set that is cached under the request fingerprint
+func (s *SearchService) providerRequest(req *models.FlightSearchRequest) *models.FlightSearchRequest {
+	providerReq := *req
+	providerReq.OriginAirport = normalizeAirport(req.OriginAirport)
+	providerReq.DestinationAirport = normalizeAirport(req.DestinationAirport)
+	providerReq.TripType = normalizeTripType(req.TripType)
+	providerReq.CabinClass = normalizeCabinClass(req.CabinClass)
+	if providerReq.TripType != "return" {
+		providerReq.ReturnDate = nil
+	}
+
+	providerReq.MaxResults = s.cfg.MaxResultsLimit
+	providerReq.Currency = ""
+	providerReq.TravelNeeds = nil
+	providerReq.SortBy = ""
+	providerReq.SortOrder = ""
+	providerReq.DirectFlightsOnly = false
+	providerReq.MaxStops = nil
+	providerReq.MinFlightDurationHours = nil
+	providerReq.MaxFlightDurationHours = nil
+	providerReq.PreferredAirlines = nil
+	providerReq.ExcludedAirlines = nil
+
+	return &providerReq
+}
+
+// normalizeAirport upper-cases and trims an airport code
+func normalizeAirport(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// normalizeTripType maps the accepted trip type spellings onto "oneway", "return" or "multicity"
+func normalizeTripType(tripType string) string {
+	switch strings.ToLower(strings.TrimSpace(tripType)) {
+	case "return", "roundtrip", "round_trip", "round-trip":
+		return "return"
+	case "multicity", "multi_city", "multi-city", "openjaw", "open_jaw", "open-jaw":
+		return "multicity"
+	default:
+		return "oneway"
+	}
+}
+
+// normalizeCabinClass lower-cases a cabin class, defaulting to economy
+func normalizeCabinClass(cabinClass string) string {
+	cabinClass = strings.ToLower(strings.TrimSpace(cabinClass))
+	if cabinClass == "" {
+		return "economy"
+	}
+	return cabinClass
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"spontra/search-service/internal/models"
+)
+
+func TestRequestFingerprint(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	returnDate := departure.AddDate(0, 0, 7)
+	otherReturn := departure.AddDate(0, 0, 8)
+	maxStops := 0
+
+	base := func() *models.FlightSearchRequest {
+		return &models.FlightSearchRequest{
+			OriginAirport:      "LHR",
+			DestinationAirport: "CDG",
+			DepartureDate:      departure,
+			PassengerCount:     1,
+			CabinClass:         "economy",
+			TripType:           "oneway",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *models.FlightSearchRequest)
+		same   bool
+	}{
+		{"airport case and spacing", func(req *models.FlightSearchRequest) { req.OriginAirport = " lhr " }, true},
+		{"default cabin", func(req *models.FlightSearchRequest) { req.CabinClass = "" }, true},
+		{"cabin case", func(req *models.FlightSearchRequest) { req.CabinClass = "Economy" }, true},
+		{"return date on a one-way trip", func(req *models.FlightSearchRequest) { req.ReturnDate = &returnDate }, true},
+		{"flexible range without flexible dates", func(req *models.FlightSearchRequest) { req.FlexibleDatesRange = 3 }, true},
+		{"radius without expansion", func(req *models.FlightSearchRequest) { req.NearbyRadiusKm = 100 }, true},
+		{"sort, filters and display fields", func(req *models.FlightSearchRequest) {
+			req.SortBy = "duration"
+			req.SortOrder = "desc"
+			req.MaxResults = 5
+			req.MaxStops = &maxStops
+			req.DirectFlightsOnly = true
+			req.ExcludedAirlines = []string{"FR"}
+			req.Currency = "USD"
+			req.TravelNeeds = &models.TravelNeeds{CheckedBags: 1}
+		}, true},
+		{"other origin", func(req *models.FlightSearchRequest) { req.OriginAirport = "LGW" }, false},
+		{"other departure", func(req *models.FlightSearchRequest) { req.DepartureDate = departure.AddDate(0, 0, 1) }, false},
+		{"other cabin", func(req *models.FlightSearchRequest) { req.CabinClass = "business" }, false},
+		{"more passengers", func(req *models.FlightSearchRequest) { req.PassengerCount = 2 }, false},
+		{"return trip", func(req *models.FlightSearchRequest) {
+			req.TripType = "return"
+			req.ReturnDate = &returnDate
+		}, false},
+		{"flexible dates", func(req *models.FlightSearchRequest) {
+			req.FlexibleDates = true
+			req.FlexibleDatesRange = 3
+		}, false},
+		{"airport expansion", func(req *models.FlightSearchRequest) {
+			req.ExpandAirports = true
+			req.NearbyRadiusKm = 100
+		}, false},
+		{"multi-city legs", func(req *models.FlightSearchRequest) {
+			req.Legs = []models.SearchLeg{
+				{OriginAirport: "LHR", DestinationAirport: "CDG", DepartureDate: departure},
+				{OriginAirport: "CDG", DestinationAirport: "FCO", DepartureDate: returnDate},
+			}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base()
+			tt.modify(req)
+			if same := requestFingerprint(base()) == requestFingerprint(req); same != tt.same {
+				t.Errorf("fingerprints equal = %t, want %t", same, tt.same)
+			}
+		})
+	}
+
+	t.Run("return date", func(t *testing.T) {
+		first, second := base(), base()
+		first.TripType, second.TripType = "roundtrip", "return"
+		first.ReturnDate, second.ReturnDate = &returnDate, &returnDate
+		if requestFingerprint(first) != requestFingerprint(second) {
+			t.Error("trip type spellings give different fingerprints")
+		}
+
+		second.ReturnDate = &otherReturn
+		if requestFingerprint(first) == requestFingerprint(second) {
+			t.Error("return dates give the same fingerprint")
+		}
+	})
+}
+
+func TestNormalizeTripType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "oneway"},
+		{"oneway", "oneway"},
+		{"Return", "return"},
+		{"round-trip", "return"},
+		{" roundtrip ", "return"},
+		{"multi_city", "multicity"},
+		{"open-jaw", "multicity"},
+		{"unknown", "oneway"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeTripType(tt.in); got != tt.want {
+			t.Errorf("normalizeTripType(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/cache"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+)
+
+// ErrSearchNotFound is returned when a search's results are no longer cached
+var ErrSearchNotFound = errors.New("search results not found or expired")
+
+// cacheResultSet stores the complete, unpaginated result set of a search under its search ID
+func (s *SearchService) cacheResultSet(response *models.FlightSearchResponse, flights []models.Flight) {
+	resultSet := *response
+	resultSet.Flights = flights
+
+	key := s.cacheKeyBuilder.SearchResponse(response.SearchID.String())
+	if err := s.cache.Set(key, &resultSet, s.cfg.SearchResultsCacheTTL); err != nil {
+		log.Printf("Failed to cache result set for search %s: %v", response.SearchID, err)
+	}
+}
+
+// loadResultSet loads the complete result set of a search cached by cacheResultSet, falling
+// back to its persisted snapshot once the cache entry has expired
+func (s *SearchService) loadResultSet(searchID uuid.UUID) (*models.FlightSearchResponse, error) {
+	response, err := s.cachedResultSet(searchID)
+	if !errors.Is(err, ErrSearchNotFound) || !s.cfg.EnableResultSnapshots {
+		return response, err
+	}
+
+	response, err = s.historyRepo.GetResultSnapshot(searchID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSnapshotNotFound) {
+			return nil, ErrSearchNotFound
+		}
+		return nil, err
+	}
+	response.SearchMetadata.FromSnapshot = true
+	return response, nil
+}
+
+// cachedResultSet loads the complete result set of a search from the cache only
+func (s *SearchService) cachedResultSet(searchID uuid.UUID) (*models.FlightSearchResponse, error) {
+	var response models.FlightSearchResponse
+	key := s.cacheKeyBuilder.SearchResponse(searchID.String())
+	if err := s.cache.Get(key, &response); err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil, ErrSearchNotFound
+		}
+		return nil, fmt.Errorf("failed to load search results: %w", err)
+	}
+	return &response, nil
+}
+
+// snapshotResultSet persists the cached result set of a completed search until expiresAt
+func (s *SearchService) snapshotResultSet(searchID uuid.UUID, expiresAt time.Time) {
+	resultSet, err := s.cachedResultSet(searchID)
+	if err != nil {
+		log.Printf("Result set for search %s unavailable for snapshot: %v", searchID, err)
+		return
+	}
+
+	if err := s.historyRepo.SaveResultSnapshot(resultSet, expiresAt); err != nil {
+		log.Printf("Failed to snapshot results of search %s: %v", searchID, err)
+	}
+}
+
+// GetSearchResults returns the complete result set of a previous search, priced in the given
+// currency or, if empty, the currency it was searched in
+func (s *SearchService) GetSearchResults(searchID uuid.UUID, currency string) (*models.FlightSearchResponse, error) {
+	if err := s.validateCurrency(&currency); err != nil {
+		return nil, err
+	}
+
+	response, err := s.loadResultSet(searchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if currency != "" {
+		s.localizeResponse(response, currency)
+	}
+	return response, nil
+}
+
+// FilterResults narrows the cached results of a previous search without querying providers again
+func (s *SearchService) FilterResults(filter *models.SearchFilter) (*models.FlightSearchResponse, error) {
+	if err := s.validateCurrency(&filter.Currency); err != nil {
+		return nil, err
+	}
+
+	response, err := s.loadResultSet(filter.SearchID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Price bounds are in the display currency, so convert before filtering
+	currency := filter.Currency
+	if currency == "" {
+		currency = s.displayCurrency(response.SearchMetadata.Currency)
+	}
+	s.localizeResponse(response, currency)
+
+	s.filterResultSet(response, filter, currency)
+	return response, nil
+}
+
+// filterResultSet filters, sorts and paginates a result set priced in currency in place
+func (s *SearchService) filterResultSet(response *models.FlightSearchResponse, filter *models.SearchFilter, currency string) {
+	sortBy, sortOrder := filterSort(filter, &response.SearchRequest)
+	filtered := s.applySorting(applySearchFilter(response.Flights, filter), sortBy, sortOrder)
+
+	// Ranges describe the whole filtered set, not just the returned page
+	response.SearchMetadata.TotalResults = len(filtered)
+	response.SearchMetadata.PriceRange = s.calculatePriceRange(filtered, currency)
+	response.SearchMetadata.DurationRange = s.calculateDurationRange(filtered)
+	response.SearchMetadata.TaggedFlights, response.SearchMetadata.ParetoFrontierSize = tagParetoFrontier(filtered)
+	response.SearchMetadata.FilterCriteria = filterCriteriaFor(filter)
+	response.SearchMetadata.CacheHit = true
+	response.SearchMetadata.FromCache = true
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = s.cfg.DefaultMaxResults
+	}
+	if limit > s.cfg.MaxResultsLimit {
+		limit = s.cfg.MaxResultsLimit
+	}
+
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(filtered) {
+		offset = len(filtered)
+	}
+
+	end := offset + limit
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+
+	response.Flights = filtered[offset:end]
+	response.SearchMetadata.ResultsReturned = len(response.Flights)
+}
+
+// filterSort returns the sort a filter asks for. A field or order the filter leaves out is
+// taken from the original search, and the order defaults to ascending as it does for searches.
+func filterSort(filter *models.SearchFilter, req *models.FlightSearchRequest) (string, string) {
+	sortBy, sortOrder := filter.SortBy, filter.SortOrder
+	if sortBy == "" {
+		sortBy = req.SortBy
+		if sortOrder == "" {
+			sortOrder = req.SortOrder
+		}
+	}
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+	return sortBy, sortOrder
+}
+
+// applySearchFilter returns the flights matching every criterion of the filter
+func applySearchFilter(flights []models.Flight, filter *models.SearchFilter) []models.Flight {
+	filtered := make([]models.Flight, 0, len(flights))
+
+	for _, flight := range flights {
+		// Price filters
+		if filter.MinPrice != nil && effectivePrice(&flight).LessThan(*filter.MinPrice) {
+			continue
+		}
+		if filter.MaxPrice != nil && effectivePrice(&flight).GreaterThan(*filter.MaxPrice) {
+			continue
+		}
+
+		// Duration filters
+		if filter.MinDuration != nil && flight.Duration < *filter.MinDuration {
+			continue
+		}
+		if filter.MaxDuration != nil && flight.Duration > *filter.MaxDuration {
+			continue
+		}
+
+		// Stops filters
+		if filter.DirectFlightsOnly && flight.Stops > 0 {
+			continue
+		}
+		if filter.MaxStops != nil && flight.Stops > *filter.MaxStops {
+			continue
+		}
+
+		// Airlines filter
+		if len(filter.Airlines) > 0 && !containsAirline(filter.Airlines, flight.Airline) {
+			continue
+		}
+
+		// Departure and arrival windows
+		if filter.DepartureTimeFrom != nil && flight.DepartureTime.Before(*filter.DepartureTimeFrom) {
+			continue
+		}
+		if filter.DepartureTimeTo != nil && flight.DepartureTime.After(*filter.DepartureTimeTo) {
+			continue
+		}
+		if filter.ArrivalTimeFrom != nil && flight.ArrivalTime.Before(*filter.ArrivalTimeFrom) {
+			continue
+		}
+		if filter.ArrivalTimeTo != nil && flight.ArrivalTime.After(*filter.ArrivalTimeTo) {
+			continue
+		}
+
+		filtered = append(filtered, flight)
+	}
+
+	return filtered
+}
+
+// containsAirline reports whether airline is in the list, ignoring case
+func containsAirline(airlines []string, airline string) bool {
+	for _, candidate := range airlines {
+		if strings.EqualFold(strings.TrimSpace(candidate), airline) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCriteriaFor describes the applied filter in search metadata
+func filterCriteriaFor(filter *models.SearchFilter) models.FilterCriteria {
+	criteria := models.FilterCriteria{
+		MaxPrice:    filter.MaxPrice,
+		MinPrice:    filter.MinPrice,
+		MaxDuration: filter.MaxDuration,
+		MinDuration: filter.MinDuration,
+		Airlines:    filter.Airlines,
+		Stops:       filter.MaxStops,
+		DirectOnly:  filter.DirectFlightsOnly,
+	}
+
+	if filter.DepartureTimeFrom != nil || filter.DepartureTimeTo != nil {
+		criteria.DepartureTime = timeRangeFor(filter.DepartureTimeFrom, filter.DepartureTimeTo)
+	}
+	if filter.ArrivalTimeFrom != nil || filter.ArrivalTimeTo != nil {
+		criteria.ArrivalTime = timeRangeFor(filter.ArrivalTimeFrom, filter.ArrivalTimeTo)
+	}
+
+	return criteria
+}
+
+// timeRangeFor builds a time range from optional bounds
+func timeRangeFor(from, to *time.Time) *models.TimeRange {
+	timeRange := &models.TimeRange{}
+	if from != nil {
+		timeRange.Start = *from
+	}
+	if to != nil {
+		timeRange.End = *to
+	}
+	return timeRange
+}
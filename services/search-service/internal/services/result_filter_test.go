@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/models"
+)
+
+func TestFilterResultSetSort(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+
+	// Prices and durations run in opposite directions so each sort gives a distinct order
+	flight := func(flightNumber string, price int64, duration int) models.Flight {
+		f := testFlight("amadeus", price, departure)
+		f.FlightNumber = flightNumber
+		f.Duration = duration
+		return f
+	}
+
+	tests := []struct {
+		name       string
+		searchSort string
+		searchDir  string
+		filter     models.SearchFilter
+		want       []string
+	}{
+		{"search sort kept", "duration", "asc", models.SearchFilter{}, []string{"C", "B", "A"}},
+		{"sort_by without sort_order is ascending", "duration", "desc", models.SearchFilter{SortBy: "price"}, []string{"A", "B", "C"}},
+		{"sort_order only inherits sort_by", "duration", "asc", models.SearchFilter{SortOrder: "desc"}, []string{"A", "B", "C"}},
+		{"sort_order only on an unsorted search", "", "", models.SearchFilter{SortOrder: "desc"}, []string{"A", "B", "C"}},
+		{"search without an order is ascending", "duration", "", models.SearchFilter{}, []string{"C", "B", "A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SearchService{cfg: &config.Config{DefaultMaxResults: 10, MaxResultsLimit: 10}}
+			response := &models.FlightSearchResponse{
+				SearchRequest: models.FlightSearchRequest{SortBy: tt.searchSort, SortOrder: tt.searchDir},
+				Flights:       []models.Flight{flight("B", 200, 120), flight("C", 300, 60), flight("A", 100, 180)},
+			}
+			filter := tt.filter
+
+			s.filterResultSet(response, &filter, "EUR")
+
+			var got []string
+			for _, f := range response.Flights {
+				got = append(got, f.FlightNumber)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("flights = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("flights = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterResultSetPagination(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+	s := &SearchService{cfg: &config.Config{DefaultMaxResults: 2, MaxResultsLimit: 3}}
+
+	var flights []models.Flight
+	for price := int64(100); price <= 500; price += 100 {
+		flights = append(flights, testFlight("amadeus", price, departure))
+	}
+	maxPrice := decimal.NewFromInt(400)
+
+	tests := []struct {
+		name      string
+		filter    models.SearchFilter
+		wantFirst int64
+		wantCount int
+	}{
+		{"default page", models.SearchFilter{MaxPrice: &maxPrice}, 100, 2},
+		{"limit capped", models.SearchFilter{MaxPrice: &maxPrice, Limit: 50}, 100, 3},
+		{"offset", models.SearchFilter{MaxPrice: &maxPrice, Offset: 3}, 400, 1},
+		{"offset past the end", models.SearchFilter{MaxPrice: &maxPrice, Offset: 9}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &models.FlightSearchResponse{Flights: append([]models.Flight(nil), flights...)}
+			filter := tt.filter
+
+			s.filterResultSet(response, &filter, "EUR")
+
+			if response.SearchMetadata.TotalResults != 4 {
+				t.Errorf("TotalResults = %d, want the 4 flights within the price filter", response.SearchMetadata.TotalResults)
+			}
+			if response.SearchMetadata.ResultsReturned != tt.wantCount || len(response.Flights) != tt.wantCount {
+				t.Fatalf("returned %d flights, want %d", len(response.Flights), tt.wantCount)
+			}
+			if tt.wantCount > 0 && !response.Flights[0].Price.Equal(decimal.NewFromInt(tt.wantFirst)) {
+				t.Errorf("first flight costs %s, want %d", response.Flights[0].Price, tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/models"
+)
+
+// searchCall is an in-flight search that other callers can wait on
+type searchCall struct {
+	done     chan struct{}
+	response *models.FlightSearchResponse
+	err      error
+
+	mu        sync.Mutex
+	results   []providerResult // provider results so far, replayed to late listeners
+	listeners []*resultListener
+	finished  bool // no more results will be published
+}
+
+// providerResult is one provider's answer during an in-flight search
+type providerResult struct {
+	provider string
+	flights  []models.Flight
+	err      error
+}
+
+// publish records a provider's result and queues it for every listener. It never waits for a
+// listener to handle the result.
+func (c *searchCall) publish(provider string, flights []models.Flight, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := providerResult{provider: provider, flights: flights, err: err}
+	c.results = append(c.results, result)
+	for _, listener := range c.listeners {
+		listener.push(result)
+	}
+}
+
+// listen replays the provider results so far to a listener and passes it those still to come.
+// It returns nil for a nil listener.
+func (c *searchCall) listen(fn providerResultFunc) *resultListener {
+	if fn == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listener := newResultListener(fn, c.results)
+	if c.finished {
+		listener.close()
+	} else {
+		c.listeners = append(c.listeners, listener)
+	}
+	return listener
+}
+
+// finish tells every listener that no more results will be published
+func (c *searchCall) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.finished = true
+	for _, listener := range c.listeners {
+		listener.close()
+	}
+	c.listeners = nil
+}
+
+// resultListener hands provider results to one caller's callback on its own goroutine, in the
+// order they were published, so a slow caller such as a streaming client holds up neither the
+// provider search nor the other callers
+type resultListener struct {
+	fn      providerResultFunc
+	wake    chan struct{}
+	drained chan struct{}
+
+	mu      sync.Mutex
+	pending []providerResult
+	closed  bool
+}
+
+// newResultListener starts delivering to fn, beginning with the results published so far
+func newResultListener(fn providerResultFunc, published []providerResult) *resultListener {
+	listener := &resultListener{
+		fn:      fn,
+		wake:    make(chan struct{}, 1),
+		drained: make(chan struct{}),
+		pending: append([]providerResult(nil), published...),
+	}
+	go listener.run()
+	return listener
+}
+
+// push queues a result for delivery
+func (l *resultListener) push(result providerResult) {
+	l.mu.Lock()
+	l.pending = append(l.pending, result)
+	l.mu.Unlock()
+	l.signal()
+}
+
+// close lets the listener stop once it has delivered every queued result
+func (l *resultListener) close() {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+	l.signal()
+}
+
+func (l *resultListener) signal() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued results until the listener is closed and drained
+func (l *resultListener) run() {
+	defer close(l.drained)
+
+	for {
+		l.mu.Lock()
+		pending, closed := l.pending, l.closed
+		l.pending = nil
+		l.mu.Unlock()
+
+		if len(pending) == 0 {
+			if closed {
+				return
+			}
+			<-l.wake
+			continue
+		}
+
+		for _, result := range pending {
+			l.fn(result.provider, result.flights, result.err)
+		}
+	}
+}
+
+// wait blocks until every result has been delivered; it returns at once for a nil listener
+func (l *resultListener) wait() {
+	if l != nil {
+		<-l.drained
+	}
+}
+
+// searchGroup coalesces concurrent searches for the same cache key into one provider fan-out
+type searchGroup struct {
+	mu    sync.Mutex
+	calls map[string]*searchCall
+}
+
+// newSearchGroup creates an empty search group
+func newSearchGroup() *searchGroup {
+	return &searchGroup{calls: make(map[string]*searchCall)}
+}
+
+// Do runs fn once per key at a time. Callers arriving while fn runs wait for its result
+// and get coalesced set to true. fn reports provider results to the callback it is given,
+// which passes them to the onResult of every caller, including those that arrive late.
+// Each caller's onResult runs on its own goroutine, and Do returns once it has seen every
+// result.
+func (g *searchGroup) Do(key string, onResult providerResultFunc, fn func(onResult providerResultFunc) (*models.FlightSearchResponse, error)) (*models.FlightSearchResponse, bool, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		listener := call.listen(onResult)
+		<-call.done
+		listener.wait()
+		return call.response, true, call.err
+	}
+
+	call := &searchCall{done: make(chan struct{})}
+	listener := call.listen(onResult)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	func() {
+		defer func() {
+			call.finish()
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+
+		call.response, call.err = fn(call.publish)
+	}()
+
+	// Waiters are released first, so they are not held up by this caller's listener
+	listener.wait()
+	return call.response, false, call.err
+}
+
+// refreshInBackground re-runs a search whose cached results are past the soft TTL.
+// The Redis lock ensures only one replica refreshes a key; it is left to expire so
+// a refresh that fails is retried once the lock TTL has passed.
+func (s *SearchService) refreshInBackground(cacheKey string, req *models.FlightSearchRequest) {
+	lockKey := s.cacheKeyBuilder.RefreshLock(cacheKey)
+	acquired, err := s.cache.SetWithNX(lockKey, s.instanceID, s.cfg.SearchRefreshLockTTL)
+	if err != nil {
+		log.Printf("Failed to acquire refresh lock for %s: %v", cacheKey, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	refreshReq := *req
+	refreshReq.ID = uuid.New()
+
+	go func() {
+		_, _, err := s.inflight.Do(cacheKey, nil, func(onResult providerResultFunc) (*models.FlightSearchResponse, error) {
+			return s.runSearch(&refreshReq, cacheKey, time.Now(), onResult)
+		})
+		if err != nil {
+			log.Printf("Background refresh of %s failed: %v", cacheKey, err)
+		}
+	}()
+}
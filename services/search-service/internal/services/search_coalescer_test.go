@@ -0,0 +1,125 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"spontra/search-service/internal/models"
+)
+
+// recordingListener collects the providers whose results it was given
+type recordingListener struct {
+	mu        sync.Mutex
+	providers []string
+	delay     time.Duration
+}
+
+func (r *recordingListener) onResult(provider string, flights []models.Flight, err error) {
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers = append(r.providers, provider)
+}
+
+func (r *recordingListener) seen() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.providers...)
+}
+
+// waitingListeners returns how many callers listen to the in-flight search for key
+func waitingListeners(group *searchGroup, key string) int {
+	group.mu.Lock()
+	call := group.calls[key]
+	group.mu.Unlock()
+	if call == nil {
+		return 0
+	}
+
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	return len(call.listeners)
+}
+
+func TestSearchGroupSlowListener(t *testing.T) {
+	group := newSearchGroup()
+	slow := &recordingListener{delay: 200 * time.Millisecond}
+	fast := &recordingListener{}
+
+	leaderStarted := make(chan struct{})
+	release := make(chan struct{})
+	published := make(chan time.Duration, 1)
+	leaderDone := make(chan []string, 1)
+
+	// A streaming caller with a slow connection leads the search
+	go func() {
+		group.Do("key", slow.onResult, func(onResult providerResultFunc) (*models.FlightSearchResponse, error) {
+			close(leaderStarted)
+			<-release
+			start := time.Now()
+			onResult("amadeus", nil, nil)
+			onResult("elasticsearch", nil, nil)
+			published <- time.Since(start)
+			return &models.FlightSearchResponse{}, nil
+		})
+		leaderDone <- slow.seen()
+	}()
+	<-leaderStarted
+
+	waiterDone := make(chan time.Time, 1)
+	go func() {
+		_, coalesced, _ := group.Do("key", fast.onResult, nil)
+		if !coalesced {
+			t.Error("second caller was not coalesced")
+		}
+		waiterDone <- time.Now()
+	}()
+
+	// Publish only once the waiter has joined
+	for waitingListeners(group, "key") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	released := time.Now()
+	close(release)
+
+	if elapsed := <-published; elapsed > 100*time.Millisecond {
+		t.Errorf("publishing took %v, want it not to wait for the slow listener", elapsed)
+	}
+	if elapsed := (<-waiterDone).Sub(released); elapsed > 150*time.Millisecond {
+		t.Errorf("waiter took %v, want it not held up by the slow listener", elapsed)
+	}
+	if got := fast.seen(); len(got) != 2 {
+		t.Errorf("waiter saw %v, want both providers before Do returned", got)
+	}
+
+	// The slow caller still sees every result, in order, before its Do returns
+	got := <-leaderDone
+	if len(got) != 2 || got[0] != "amadeus" || got[1] != "elasticsearch" {
+		t.Errorf("slow listener saw %v, want [amadeus elasticsearch]", got)
+	}
+}
+
+func TestSearchGroupLateListener(t *testing.T) {
+	group := newSearchGroup()
+	call := &searchCall{done: make(chan struct{})}
+	call.publish("amadeus", nil, nil)
+	call.finish()
+
+	// A listener joining after the search finished gets the replay and is then closed
+	late := &recordingListener{}
+	listener := call.listen(late.onResult)
+	listener.wait()
+	if got := late.seen(); len(got) != 1 || got[0] != "amadeus" {
+		t.Errorf("late listener saw %v, want [amadeus]", got)
+	}
+
+	// Callers without a listener are fine too
+	response, coalesced, err := group.Do("key", nil, func(onResult providerResultFunc) (*models.FlightSearchResponse, error) {
+		onResult("amadeus", nil, nil)
+		return &models.FlightSearchResponse{}, nil
+	})
+	if response == nil || coalesced || err != nil {
+		t.Errorf("Do() = %v, %v, %v, want a response from the leading call", response, coalesced, err)
+	}
+}
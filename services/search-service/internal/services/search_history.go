@@ -0,0 +1,258 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+)
+
+// ErrReplayExpired is returned when a past search can no longer be replayed because its dates have passed
+var ErrReplayExpired = errors.New("search departure date has passed")
+
+// ListSearchHistory returns a user's past searches, or the session's when the user is anonymous
+func (s *SearchService) ListSearchHistory(userID *uuid.UUID, sessionID string, limit, offset int) ([]models.SearchHistory, error) {
+	if limit <= 0 || limit > s.cfg.MaxResultsLimit {
+		limit = s.cfg.DefaultMaxResults
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var histories []models.SearchHistory
+	var err error
+	switch {
+	case userID != nil:
+		histories, err = s.historyRepo.GetUserSearchHistory(*userID, limit, offset)
+	case sessionID != "":
+		histories, err = s.historyRepo.GetSessionSearchHistory(sessionID, limit+offset)
+		if err == nil {
+			if offset > len(histories) {
+				offset = len(histories)
+			}
+			histories = histories[offset:]
+		}
+	default:
+		return nil, fmt.Errorf("a user or session is required to list search history")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if histories == nil {
+		histories = []models.SearchHistory{}
+	}
+	return histories, nil
+}
+
+// ClaimSessionHistory assigns a session's anonymous searches to the user who has just logged in
+func (s *SearchService) ClaimSessionHistory(sessionID string, userID uuid.UUID) (int, error) {
+	claimed, err := s.historyRepo.ClaimSessionHistory(sessionID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.sessionRepo.ClaimSearchSession(sessionID, userID); err != nil {
+		log.Printf("Failed to claim search session %s: %v", sessionID, err)
+	}
+
+	return claimed, nil
+}
+
+// ReplaySearch runs a past search again and compares the new results with the original
+func (s *SearchService) ReplaySearch(searchID uuid.UUID, userID *uuid.UUID, sessionID string) (*models.SearchReplay, error) {
+	history, err := s.ownedSearchHistory(searchID, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := history.Request
+	if replayExpired(&req) {
+		return nil, ErrReplayExpired
+	}
+
+	req.UserID = history.UserID
+	if userID != nil {
+		req.UserID = userID
+	}
+	req.SearchSessionID = history.SessionID
+	if sessionID != "" {
+		req.SearchSessionID = sessionID
+	}
+
+	response, err := s.SearchFlights(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	replayFlights := response.Flights
+	if resultSet, err := s.loadResultSet(response.SearchID); err == nil {
+		replayFlights = resultSet.Flights
+	}
+
+	return &models.SearchReplay{
+		OriginalSearchID: searchID,
+		Search:           response,
+		Diff:             s.diffAgainstHistory(history, response.SearchID, replayFlights),
+	}, nil
+}
+
+// DiffSearches compares a replayed search with the original past search
+func (s *SearchService) DiffSearches(originalID, replayID uuid.UUID, userID *uuid.UUID, sessionID string) (*models.SearchDiff, error) {
+	history, err := s.ownedSearchHistory(originalID, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := s.loadResultSet(replayID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.diffAgainstHistory(history, replayID, replay.Flights), nil
+}
+
+// ownedSearchHistory loads a past search belonging to the user or session. Searches belonging
+// to someone else are reported as not found.
+func (s *SearchService) ownedSearchHistory(searchID uuid.UUID, userID *uuid.UUID, sessionID string) (*models.SearchHistory, error) {
+	history, err := s.historyRepo.GetSearchHistoryBySearchID(searchID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSearchHistoryNotFound) {
+			return nil, ErrSearchNotFound
+		}
+		return nil, err
+	}
+
+	ownedByUser := userID != nil && history.UserID != nil && *history.UserID == *userID
+	ownedBySession := sessionID != "" && history.SessionID == sessionID
+	if !ownedByUser && !ownedBySession {
+		return nil, ErrSearchNotFound
+	}
+
+	return history, nil
+}
+
+// diffAgainstHistory diffs replayed flights against the original search's result set,
+// falling back to the best price recorded in history once the result set has expired. Both
+// sides are priced in the original search's display currency.
+func (s *SearchService) diffAgainstHistory(history *models.SearchHistory, replayID uuid.UUID, replayFlights []models.Flight) *models.SearchDiff {
+	currency := s.displayCurrency(history.Request.Currency)
+
+	var originalFlights []models.Flight
+	available := false
+	if original, err := s.loadResultSet(history.SearchID); err == nil {
+		originalFlights = s.localizeFlights(original.Flights, currency)
+		available = true
+	}
+
+	diff := diffFlights(originalFlights, s.localizeFlights(replayFlights, currency))
+	diff.OriginalSearchID = history.SearchID
+	diff.ReplaySearchID = replayID
+	diff.OriginalFlightsAvailable = available
+	diff.Currency = currency
+	if !available && history.BestPrice != nil {
+		bestPrice, err := s.converter.Convert(*history.BestPrice, s.displayCurrency(history.Currency), currency)
+		if err == nil {
+			diff.OriginalBestPrice = &bestPrice
+		}
+	}
+
+	if diff.OriginalBestPrice != nil && diff.ReplayBestPrice != nil {
+		change := diff.ReplayBestPrice.Sub(*diff.OriginalBestPrice)
+		diff.BestPriceChange = &change
+	}
+
+	return diff
+}
+
+// diffFlights matches itineraries between two result sets by their journey
+func diffFlights(original, replay []models.Flight) *models.SearchDiff {
+	diff := &models.SearchDiff{
+		OriginalBestPrice: cheapestPrice(original),
+		ReplayBestPrice:   cheapestPrice(replay),
+		NewFlights:        []models.Flight{},
+		RemovedFlights:    []models.Flight{},
+		PriceChanges:      []models.FlightPriceDelta{},
+	}
+	if len(replay) > 0 {
+		diff.Currency = replay[0].Currency
+	}
+	if original == nil {
+		return diff
+	}
+
+	originalByKey := make(map[string]models.Flight, len(original))
+	for _, flight := range original {
+		originalByKey[journeyKey(&flight)] = flight
+	}
+
+	seen := make(map[string]bool, len(replay))
+	for _, flight := range replay {
+		key := journeyKey(&flight)
+		seen[key] = true
+
+		previous, ok := originalByKey[key]
+		if !ok {
+			diff.NewFlights = append(diff.NewFlights, flight)
+			continue
+		}
+		if flight.Price.Equal(previous.Price) {
+			diff.Unchanged++
+			continue
+		}
+		diff.PriceChanges = append(diff.PriceChanges, models.FlightPriceDelta{
+			Flight:        flight,
+			OriginalPrice: previous.Price,
+			ReplayPrice:   flight.Price,
+			Delta:         flight.Price.Sub(previous.Price),
+		})
+	}
+
+	for _, flight := range original {
+		if !seen[journeyKey(&flight)] {
+			diff.RemovedFlights = append(diff.RemovedFlights, flight)
+		}
+	}
+
+	// Largest price drops first
+	sort.SliceStable(diff.PriceChanges, func(i, j int) bool {
+		return diff.PriceChanges[i].Delta.LessThan(diff.PriceChanges[j].Delta)
+	})
+
+	return diff
+}
+
+// cheapestPrice returns the lowest price in a result set, or nil when it is empty
+func cheapestPrice(flights []models.Flight) *decimal.Decimal {
+	if len(flights) == 0 {
+		return nil
+	}
+
+	cheapest := flights[0].Price
+	for _, flight := range flights[1:] {
+		if flight.Price.LessThan(cheapest) {
+			cheapest = flight.Price
+		}
+	}
+	return &cheapest
+}
+
+// replayExpired reports whether any departure of the request is already in the past
+func replayExpired(req *models.FlightSearchRequest) bool {
+	today := time.Now().Truncate(24 * time.Hour)
+	if req.DepartureDate.Before(today) {
+		return true
+	}
+	for _, leg := range req.Legs {
+		if leg.DepartureDate.Before(today) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+)
+
+func TestDiffFlights(t *testing.T) {
+	departure := time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+	flight := func(flightNumber string, price int64) models.Flight {
+		f := testFlight("amadeus", price, departure)
+		f.FlightNumber = flightNumber
+		return f
+	}
+
+	original := []models.Flight{
+		flight("BA123", 200),
+		flight("IB3163", 150),
+		flight("UX1013", 90),
+		flight("VY7821", 120),
+	}
+	replay := []models.Flight{
+		flight("BA123", 200),  // unchanged
+		flight("IB3163", 170), // up 20
+		flight("VY7821", 80),  // down 40
+		flight("FR5995", 60),  // new
+	}
+
+	diff := diffFlights(original, replay)
+
+	if diff.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", diff.Unchanged)
+	}
+	if len(diff.NewFlights) != 1 || diff.NewFlights[0].FlightNumber != "FR5995" {
+		t.Errorf("NewFlights = %v, want FR5995", diff.NewFlights)
+	}
+	if len(diff.RemovedFlights) != 1 || diff.RemovedFlights[0].FlightNumber != "UX1013" {
+		t.Errorf("RemovedFlights = %v, want UX1013", diff.RemovedFlights)
+	}
+
+	// Largest drop first
+	if len(diff.PriceChanges) != 2 {
+		t.Fatalf("got %d price changes, want 2", len(diff.PriceChanges))
+	}
+	if change := diff.PriceChanges[0]; change.Flight.FlightNumber != "VY7821" || !change.Delta.Equal(decimal.NewFromInt(-40)) {
+		t.Errorf("PriceChanges[0] = %s %s, want VY7821 -40", change.Flight.FlightNumber, change.Delta)
+	}
+	if change := diff.PriceChanges[1]; change.Flight.FlightNumber != "IB3163" || !change.OriginalPrice.Equal(decimal.NewFromInt(150)) {
+		t.Errorf("PriceChanges[1] = %s from %s, want IB3163 from 150", change.Flight.FlightNumber, change.OriginalPrice)
+	}
+
+	if !diff.OriginalBestPrice.Equal(decimal.NewFromInt(90)) || !diff.ReplayBestPrice.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("best prices = %s and %s, want 90 and 60", diff.OriginalBestPrice, diff.ReplayBestPrice)
+	}
+	if diff.Currency != "EUR" {
+		t.Errorf("Currency = %q, want EUR", diff.Currency)
+	}
+}
+
+func TestDiffFlightsWithoutOriginal(t *testing.T) {
+	// Expired original results leave only the replay's best price to compare
+	replay := []models.Flight{testFlight("amadeus", 80, time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC))}
+
+	diff := diffFlights(nil, replay)
+
+	if len(diff.NewFlights) != 0 || len(diff.RemovedFlights) != 0 || len(diff.PriceChanges) != 0 {
+		t.Errorf("diff = %+v, want no flight changes without the original results", diff)
+	}
+	if diff.OriginalBestPrice != nil || !diff.ReplayBestPrice.Equal(decimal.NewFromInt(80)) {
+		t.Errorf("best prices = %v and %v, want none and 80", diff.OriginalBestPrice, diff.ReplayBestPrice)
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,7 +14,9 @@ import (
 	"spontra/search-service/internal/database"
 	"spontra/search-service/internal/elasticsearch"
 	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/providers"
 	"spontra/search-service/internal/repository"
+	"spontra/shared/fx"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -27,6 +31,10 @@ type SearchService struct {
 	historyRepo     *repository.HistoryRepository
 	cacheKeyBuilder *cache.CacheKeyBuilder
 	httpClient      *http.Client
+	providers       *providers.Registry
+	converter       *fx.Converter
+	inflight        *searchGroup
+	instanceID      string
 }
 
 // NewSearchService creates a new search service
@@ -49,13 +57,15 @@ func NewSearchService(
 		httpClient: &http.Client{
 			Timeout: cfg.ProviderTimeout,
 		},
+		providers:  newProviderRegistry(cfg, elasticsearch),
+		converter:  newCurrencyConverter(cfg, redisClient),
+		inflight:   newSearchGroup(),
+		instanceID: uuid.New().String(),
 	}
 }
 
 // SearchFlights orchestrates flight search across multiple providers
 func (s *SearchService) SearchFlights(req *models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
-	startTime := time.Now()
-
 	// Generate search ID
 	req.ID = uuid.New()
 	req.CreatedAt = time.Now()
@@ -65,159 +75,305 @@ func (s *SearchService) SearchFlights(req *models.FlightSearchRequest) (*models.
 		return nil, fmt.Errorf("invalid search request: %w", err)
 	}
 
+	var response *models.FlightSearchResponse
+	var err error
+	if req.FlexibleDates && req.FlexibleDatesRange > 0 {
+		response, err = s.searchFlexibleDates(req)
+	} else {
+		response, err = s.searchDate(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Every search gets its own ID and result set, so record it in history even when the
+	// provider results came from the cache or a concurrent search
+	go s.storeSearchHistory(req, response)
+
+	if response.SearchMetadata.CacheHit {
+		log.Printf("Cache hit for search %s (stale: %t)", req.ID, response.SearchMetadata.Stale)
+	} else {
+		log.Printf("Search %s completed in %v, found %d flights", req.ID, response.SearchMetadata.SearchTime, len(response.Flights))
+	}
+
+	return response, nil
+}
+
+// searchDate searches a single departure (and return) date, serving from cache when possible
+func (s *SearchService) searchDate(req *models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
+	startTime := time.Now()
+
+	base, err := s.providerResults(req, startTime, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(req, base, startTime), nil
+}
+
+// providerResults returns the unfiltered provider results for a request, from cache when possible.
+// onResult, when set, is called as each provider answers, also when the search is coalesced
+// with one already in flight; it is not called for results served from the cache.
+func (s *SearchService) providerResults(req *models.FlightSearchRequest, startTime time.Time, onResult providerResultFunc) (*models.FlightSearchResponse, error) {
 	// Check cache first
-	cacheKey := s.cacheKeyBuilder.SearchResults(
-		req.OriginAirport,
-		req.DestinationAirport,
-		req.DepartureDate.Format("2006-01-02"),
-		req.PassengerCount,
-	)
+	cacheKey := s.resultsCacheKey(req)
 
 	var cachedResponse models.FlightSearchResponse
 	if err := s.cache.Get(cacheKey, &cachedResponse); err == nil {
 		// Update metadata
 		cachedResponse.SearchMetadata.CacheHit = true
 		cachedResponse.SearchMetadata.FromCache = true
-		cachedResponse.SearchMetadata.SearchTime = time.Since(startTime)
-		
-		log.Printf("Cache hit for search %s", req.ID)
+
+		// Past the soft TTL: serve what we have and refresh behind the caller
+		if time.Since(cachedResponse.CreatedAt) > s.cfg.SearchResultsSoftTTL {
+			cachedResponse.SearchMetadata.Stale = true
+			s.refreshInBackground(cacheKey, req)
+		}
 		return &cachedResponse, nil
 	}
 
-	// Search across multiple providers
-	flights, metadata, err := s.orchestrateSearch(req)
+	// Concurrent misses for the same key share a single provider fan-out
+	response, coalesced, err := s.inflight.Do(cacheKey, onResult, func(onResult providerResultFunc) (*models.FlightSearchResponse, error) {
+		return s.runSearch(req, cacheKey, startTime, onResult)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("search orchestration failed: %w", err)
+		return nil, err
+	}
+	if coalesced {
+		// The shared response still carries the search ID of the search that ran it
+		log.Printf("Search %s coalesced with in-flight search %s", req.ID, response.SearchID)
+		shared := *response
+		shared.SearchMetadata.Coalesced = true
+		return &shared, nil
 	}
 
-	// Apply filters and sorting
-	filteredFlights := s.applyFilters(flights, req)
-	sortedFlights := s.applySorting(filteredFlights, req.SortBy, req.SortOrder)
+	return response, nil
+}
 
-	// Limit results
-	if len(sortedFlights) > req.MaxResults {
-		sortedFlights = sortedFlights[:req.MaxResults]
+// runSearch queries the providers and caches their merged, unfiltered results
+func (s *SearchService) runSearch(req *models.FlightSearchRequest, cacheKey string, startTime time.Time, onResult providerResultFunc) (*models.FlightSearchResponse, error) {
+	providerReq := s.providerRequest(req)
+
+	// Search across multiple providers
+	flights, metadata, err := s.searchAirportPairs(providerReq, onResult)
+	if err != nil {
+		return nil, fmt.Errorf("search orchestration failed: %w", err)
 	}
 
-	// Build response
 	response := &models.FlightSearchResponse{
 		SearchID:      req.ID,
 		RequestID:     uuid.New().String(),
-		SearchRequest: *req,
-		Flights:       sortedFlights,
+		SearchRequest: *providerReq,
+		Flights:       flights,
 		SearchMetadata: models.SearchMetadata{
-			TotalResults:        metadata.TotalResults,
-			ResultsReturned:     len(sortedFlights),
-			SearchTime:          time.Since(startTime),
-			ProvidersQueried:    metadata.ProvidersQueried,
-			ProvidersSuccessful: metadata.ProvidersSuccessful,
-			ProvidersErrors:     metadata.ProvidersErrors,
-			CacheHit:            false,
-			FromCache:           false,
-			Currency:            "EUR",
+			TotalResults:          metadata.TotalResults,
+			SearchTime:            time.Since(startTime),
+			ProvidersQueried:      metadata.ProvidersQueried,
+			ProvidersSuccessful:   metadata.ProvidersSuccessful,
+			ProvidersErrors:       metadata.ProvidersErrors,
+			DuplicatesRemoved:     metadata.DuplicatesRemoved,
+			ProviderContributions: metadata.ProviderContributions,
+			FaresWithoutRate:      metadata.FaresWithoutRate,
+			CacheHit:              false,
+			FromCache:             false,
+			Currency:              s.converter.Base(),
 		},
 		CreatedAt: time.Now(),
 		ExpiresAt: time.Now().Add(s.cfg.SearchResultsCacheTTL),
 	}
 
-	// Calculate price and duration ranges
-	response.SearchMetadata.PriceRange = s.calculatePriceRange(sortedFlights)
-	response.SearchMetadata.DurationRange = s.calculateDurationRange(sortedFlights)
-
 	// Cache the response
 	if err := s.cache.Set(cacheKey, response, s.cfg.SearchResultsCacheTTL); err != nil {
 		log.Printf("Failed to cache search results: %v", err)
 	}
 
-	// Store search history (async)
-	go s.storeSearchHistory(req, response)
+	return response, nil
+}
 
-	log.Printf("Search %s completed in %v, found %d flights", req.ID, response.SearchMetadata.SearchTime, len(sortedFlights))
+// buildResponse applies the request's filters, sorting and limit to provider results
+func (s *SearchService) buildResponse(req *models.FlightSearchRequest, base *models.FlightSearchResponse, startTime time.Time) *models.FlightSearchResponse {
+	// Price in the display currency before anything compares prices
+	currency := s.displayCurrency(req.Currency)
+	flights := s.localizeFlights(base.Flights, currency)
+	s.priceTrips(req, flights)
 
-	return response, nil
+	// Apply filters and sorting
+	filteredFlights := s.applyFilters(flights, req)
+	s.scoreFlights(req, filteredFlights)
+	sortedFlights := s.applySorting(filteredFlights, req.SortBy, req.SortOrder)
+
+	// Keep the full result set so it can be re-filtered by search ID
+	allFlights := sortedFlights
+
+	// Limit results
+	if len(sortedFlights) > req.MaxResults {
+		sortedFlights = sortedFlights[:req.MaxResults]
+	}
+
+	metadata := base.SearchMetadata
+	metadata.ResultsReturned = len(sortedFlights)
+	metadata.SearchTime = time.Since(startTime)
+	metadata.Currency = currency
+
+	// Calculate price and duration ranges over every matching flight
+	metadata.PriceRange = s.calculatePriceRange(allFlights, currency)
+	metadata.DurationRange = s.calculateDurationRange(allFlights)
+	metadata.TaggedFlights, metadata.ParetoFrontierSize = tagParetoFrontier(allFlights)
+
+	// Build response
+	response := &models.FlightSearchResponse{
+		SearchID:       req.ID,
+		RequestID:      uuid.New().String(),
+		SearchRequest:  *req,
+		Flights:        sortedFlights,
+		SearchMetadata: metadata,
+		CreatedAt:      base.CreatedAt,
+		ExpiresAt:      base.ExpiresAt,
+	}
+	if len(req.Legs) > 0 {
+		response.Legs = buildLegGroups(req.Legs, allFlights)
+	}
+
+	s.cacheResultSet(response, allFlights)
+
+	return response
 }
 
-// orchestrateSearch coordinates search across multiple providers
-func (s *SearchService) orchestrateSearch(req *models.FlightSearchRequest) ([]models.Flight, *SearchMetadata, error) {
+// providerResultFunc is called as each provider answers during orchestration
+type providerResultFunc func(provider string, flights []models.Flight, err error)
+
+// errNoProviderResults is returned when no provider answered a search, so that an empty
+// result set is not cached as if it were a real answer
+var errNoProviderResults = errors.New("no provider returned results")
+
+// orchestrateSearch coordinates search across multiple providers.
+// onResult, if set, is called from the collecting goroutine as each provider answers.
+// It fails with errNoProviderResults when every provider failed.
+func (s *SearchService) orchestrateSearch(req *models.FlightSearchRequest, onResult providerResultFunc) ([]models.Flight, *SearchMetadata, error) {
 	type providerResult struct {
-		provider string
-		flights  []models.Flight
-		err      error
+		index   int
+		flights []models.Flight
+		err     error
 	}
 
-	results := make(chan providerResult, len(s.cfg.EnabledProviders))
-	
+	enabled, unknown := s.providers.Enabled(s.cfg.EnabledProviders)
+	enabled, unsupported := supportingProviders(enabled, req)
+	results := make(chan providerResult, len(enabled))
+
 	// Launch searches to all enabled providers
-	for _, provider := range s.cfg.EnabledProviders {
-		go func(p string) {
-			flights, err := s.searchProvider(p, req)
+	ctx := context.Background()
+	for i, provider := range enabled {
+		go func(i int, p providers.FlightProvider) {
+			flights, err := s.providers.Search(ctx, p.Name(), req)
 			results <- providerResult{
-				provider: p,
-				flights:  flights,
-				err:      err,
+				index:   i,
+				flights: flights,
+				err:     err,
 			}
-		}(provider)
+		}(i, provider)
 	}
 
 	// Collect results
-	var allFlights []models.Flight
 	metadata := &SearchMetadata{
 		ProvidersQueried:    s.cfg.EnabledProviders,
 		ProvidersSuccessful: []string{},
 		ProvidersErrors:     make(map[string]string),
 	}
+	for _, name := range unknown {
+		metadata.ProvidersErrors[name] = fmt.Sprintf("unknown provider: %s", name)
+		log.Printf("Provider %s is enabled but not registered", name)
+	}
+	for _, name := range unsupported {
+		metadata.ProvidersErrors[name] = "multi-city search not supported"
+	}
 
-	for i := 0; i < len(s.cfg.EnabledProviders); i++ {
+	byProvider := make([][]models.Flight, len(enabled))
+	succeeded := make([]bool, len(enabled))
+	for i := 0; i < len(enabled); i++ {
 		result := <-results
+		name := enabled[result.index].Name()
+		if result.err == nil {
+			var dropped int
+			result.flights, dropped = s.normalizeFlights(name, result.flights)
+			metadata.FaresWithoutRate += dropped
+		}
+		if onResult != nil {
+			onResult(name, result.flights, result.err)
+		}
 		if result.err != nil {
-			metadata.ProvidersErrors[result.provider] = result.err.Error()
-			log.Printf("Provider %s failed: %v", result.provider, result.err)
-		} else {
-			metadata.ProvidersSuccessful = append(metadata.ProvidersSuccessful, result.provider)
-			allFlights = append(allFlights, result.flights...)
+			metadata.ProvidersErrors[name] = result.err.Error()
+			log.Printf("Provider %s failed: %v", name, result.err)
+			continue
 		}
+		byProvider[result.index] = result.flights
+		succeeded[result.index] = true
 	}
 
-	metadata.TotalResults = len(allFlights)
-	
-	return allFlights, metadata, nil
+	// Assemble in priority order so output does not depend on which provider answered first
+	var allFlights []models.Flight
+	for i, provider := range enabled {
+		if succeeded[i] {
+			metadata.ProvidersSuccessful = append(metadata.ProvidersSuccessful, provider.Name())
+			allFlights = append(allFlights, byProvider[i]...)
+		}
+	}
+	if len(metadata.ProvidersSuccessful) == 0 {
+		return nil, metadata, fmt.Errorf("%w: %v", errNoProviderResults, metadata.ProvidersErrors)
+	}
+
+	// Collapse the same journey offered by several providers
+	merged := mergeFlights(allFlights)
+	metadata.DuplicatesRemoved = merged.duplicatesRemoved
+	metadata.ProviderContributions = merged.contributions
+	metadata.TotalResults = len(merged.flights)
+
+	return merged.flights, metadata, nil
 }
 
-// searchProvider searches a specific provider
-func (s *SearchService) searchProvider(provider string, req *models.FlightSearchRequest) ([]models.Flight, error) {
-	switch provider {
-	case "amadeus":
-		return s.searchAmadeus(req)
-	case "data-ingestion":
-		return s.searchDataIngestion(req)
-	case "elasticsearch":
-		return s.searchElasticsearch(req)
-	default:
-		return nil, fmt.Errorf("unknown provider: %s", provider)
-	}
+// Providers returns the provider registry so callers can register additional providers
+func (s *SearchService) Providers() *providers.Registry {
+	return s.providers
 }
 
-// searchAmadeus searches using Amadeus API (via data-ingestion service)
-func (s *SearchService) searchAmadeus(req *models.FlightSearchRequest) ([]models.Flight, error) {
-	// For now, delegate to data-ingestion service
-	return s.searchDataIngestion(req)
+// ProviderStatus reports health and circuit breaker state for every registered provider
+func (s *SearchService) ProviderStatus(ctx context.Context) []providers.ProviderStatus {
+	return s.providers.Status(ctx)
 }
 
-// searchDataIngestion searches using the data-ingestion service
-func (s *SearchService) searchDataIngestion(req *models.FlightSearchRequest) ([]models.Flight, error) {
-	// This would make an HTTP request to the data-ingestion service
-	// For now, return empty results as placeholder
-	log.Printf("Searching data-ingestion service for %s->%s", req.OriginAirport, req.DestinationAirport)
-	return []models.Flight{}, nil
+// newProviderRegistry registers the built-in flight providers
+func newProviderRegistry(cfg *config.Config, esClient *elasticsearch.Client) *providers.Registry {
+	registry := providers.NewRegistry(cfg.ProviderTimeout)
+
+	options := func(name string) providers.Options {
+		timeout := cfg.ProviderTimeouts[name]
+		if timeout <= 0 {
+			timeout = cfg.ProviderTimeout
+		}
+		return providers.Options{
+			Timeout:  timeout,
+			Priority: cfg.ProviderPriorities[name],
+		}
+	}
+
+	// Amadeus is only reachable through data-ingestion, which owns the credentials. One client
+	// per backend: a second name for the same endpoint would just send every query twice.
+	amadeus := options("amadeus")
+	registry.Register(providers.NewDataIngestionClient("amadeus", cfg.DataIngestionServiceURL, amadeus.Timeout), amadeus)
+	if esClient != nil {
+		registry.Register(providers.NewElasticsearchProvider(esClient), options("elasticsearch"))
+	}
+
+	return registry
 }
 
-// searchElasticsearch searches using Elasticsearch
-func (s *SearchService) searchElasticsearch(req *models.FlightSearchRequest) ([]models.Flight, error) {
-	response, err := s.elasticsearch.SearchFlights(req)
-	if err != nil {
-		return nil, err
+// newCurrencyConverter converts with the rates data-ingestion caches, falling back to the
+// configured rates file
+func newCurrencyConverter(cfg *config.Config, redisClient *cache.RedisClient) *fx.Converter {
+	source := fx.ChainSource{
+		fx.NewCacheSource(redisClient),
+		fx.NewFileSource(cfg.FXRatesFile),
 	}
-	return response.Flights, nil
+	return fx.NewConverter(source, cfg.FXBaseCurrency, cfg.FXRatesRefresh)
 }
 
 // applyFilters applies filters to search results
@@ -283,9 +439,9 @@ func (s *SearchService) applySorting(flights []models.Flight, sortBy, sortOrder
 		switch sortBy {
 		case "price":
 			if ascending {
-				return flights[i].Price.LessThan(flights[j].Price)
+				return effectivePrice(&flights[i]).LessThan(effectivePrice(&flights[j]))
 			}
-			return flights[i].Price.GreaterThan(flights[j].Price)
+			return effectivePrice(&flights[i]).GreaterThan(effectivePrice(&flights[j]))
 		case "duration":
 			if ascending {
 				return flights[i].Duration < flights[j].Duration
@@ -303,25 +459,25 @@ func (s *SearchService) applySorting(flights []models.Flight, sortBy, sortOrder
 			return flights[i].RelevanceScore > flights[j].RelevanceScore
 		default:
 			// Default to price ascending
-			return flights[i].Price.LessThan(flights[j].Price)
+			return effectivePrice(&flights[i]).LessThan(effectivePrice(&flights[j]))
 		}
 	})
 
 	return flights
 }
 
-// calculatePriceRange calculates price statistics
-func (s *SearchService) calculatePriceRange(flights []models.Flight) models.PriceRange {
+// calculatePriceRange calculates price statistics of flights priced in one currency
+func (s *SearchService) calculatePriceRange(flights []models.Flight, currency string) models.PriceRange {
 	if len(flights) == 0 {
-		return models.PriceRange{}
+		return models.PriceRange{Currency: currency}
 	}
 
-	minPrice := flights[0].Price
-	maxPrice := flights[0].Price
-	totalPrice := flights[0].Price
+	minPrice := effectivePrice(&flights[0])
+	maxPrice := minPrice
+	totalPrice := minPrice
 
 	for i := 1; i < len(flights); i++ {
-		price := flights[i].Price
+		price := effectivePrice(&flights[i])
 		if price.LessThan(minPrice) {
 			minPrice = price
 		}
@@ -337,7 +493,7 @@ func (s *SearchService) calculatePriceRange(flights []models.Flight) models.Pric
 		MinPrice: minPrice,
 		MaxPrice: maxPrice,
 		AvgPrice: avgPrice,
-		Currency: "EUR",
+		Currency: currency,
 	}
 }
 
@@ -373,13 +529,21 @@ func (s *SearchService) calculateDurationRange(flights []models.Flight) models.D
 
 // validateSearchRequest validates the search request
 func (s *SearchService) validateSearchRequest(req *models.FlightSearchRequest) error {
+	if normalizeTripType(req.TripType) == "multicity" {
+		if err := validateLegs(req); err != nil {
+			return err
+		}
+	} else if len(req.Legs) > 0 {
+		return fmt.Errorf("legs are only supported for multi-city trips")
+	}
+
 	if req.OriginAirport == "" {
 		return fmt.Errorf("origin airport is required")
 	}
 	if req.DestinationAirport == "" {
 		return fmt.Errorf("destination airport is required")
 	}
-	if req.OriginAirport == req.DestinationAirport {
+	if req.OriginAirport == req.DestinationAirport && len(req.Legs) == 0 {
 		return fmt.Errorf("origin and destination cannot be the same")
 	}
 	if req.PassengerCount < 1 || req.PassengerCount > 9 {
@@ -394,6 +558,23 @@ func (s *SearchService) validateSearchRequest(req *models.FlightSearchRequest) e
 	if req.MaxResults > s.cfg.MaxResultsLimit {
 		req.MaxResults = s.cfg.MaxResultsLimit
 	}
+	if req.FlexibleDatesRange > s.cfg.FlexibleDatesMaxRange {
+		req.FlexibleDatesRange = s.cfg.FlexibleDatesMaxRange
+	}
+	if req.FlexibleDates && req.FlexibleDatesRange <= 0 {
+		req.FlexibleDatesRange = s.cfg.DefaultFlexibleRange
+	}
+	if err := s.validateCurrency(&req.Currency); err != nil {
+		return err
+	}
+	if needs := req.TravelNeeds; needs != nil {
+		if needs.CheckedBags < 0 || needs.CheckedBags > 3 {
+			return fmt.Errorf("checked bags must be between 0 and 3")
+		}
+		if needs.CabinBags < 0 || needs.CabinBags > 2 {
+			return fmt.Errorf("cabin bags must be between 0 and 2")
+		}
+	}
 
 	return nil
 }
@@ -406,25 +587,37 @@ func (s *SearchService) storeSearchHistory(req *models.FlightSearchRequest, resp
 		UserID:      req.UserID,
 		SessionID:   req.SearchSessionID,
 		Request:     *req,
-		ResultCount: len(response.Flights),
-		Currency:    "EUR",
+		ResultCount: response.SearchMetadata.TotalResults,
+		Currency:    response.SearchMetadata.Currency,
 		CreatedAt:   time.Now(),
-		ExpiresAt:   time.Now().Add(24 * time.Hour * 30), // 30 days
+		ExpiresAt:   time.Now().Add(s.cfg.SearchHistoryRetention),
 	}
 
-	if len(response.Flights) > 0 {
-		history.BestPrice = &response.Flights[0].Price
+	// The response holds one sorted page; the count and best price describe the whole result set
+	flights := response.Flights
+	if resultSet, err := s.cachedResultSet(req.ID); err == nil {
+		flights = resultSet.Flights
+		history.ResultCount = len(resultSet.Flights)
 	}
+	history.BestPrice = cheapestPrice(flights)
 
 	if err := s.historyRepo.CreateSearchHistory(history); err != nil {
 		log.Printf("Failed to store search history: %v", err)
 	}
+
+	// Snapshots share the history's expiry so both are removed by DeleteExpiredHistory
+	if s.cfg.EnableResultSnapshots {
+		s.snapshotResultSet(req.ID, history.ExpiresAt)
+	}
 }
 
 // SearchMetadata holds search orchestration metadata
 type SearchMetadata struct {
-	TotalResults        int               `json:"total_results"`
-	ProvidersQueried    []string          `json:"providers_queried"`
-	ProvidersSuccessful []string          `json:"providers_successful"`
-	ProvidersErrors     map[string]string `json:"providers_errors"`
+	TotalResults          int                                    `json:"total_results"`
+	ProvidersQueried      []string                               `json:"providers_queried"`
+	ProvidersSuccessful   []string                               `json:"providers_successful"`
+	ProvidersErrors       map[string]string                      `json:"providers_errors"`
+	DuplicatesRemoved     int                                    `json:"duplicates_removed"`
+	ProviderContributions map[string]models.ProviderContribution `json:"provider_contributions"`
+	FaresWithoutRate      int                                    `json:"fares_without_rate"`
 }
\ No newline at end of file
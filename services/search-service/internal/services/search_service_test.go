@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/providers"
+	"spontra/shared/fx"
+)
+
+var orchestrationDeparture = time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)
+
+// fakeProvider configures a fake provider before it is registered
+type fakeProvider struct {
+	fake    *providers.FakeProvider
+	options providers.Options
+}
+
+// newOrchestrationService creates a search service whose registry holds only the given fakes,
+// all of them enabled. Fares are quoted in the EUR base currency, so no rates are needed.
+func newOrchestrationService(fakes ...fakeProvider) *SearchService {
+	registry := providers.NewRegistry(time.Second)
+	cfg := &config.Config{}
+	for _, f := range fakes {
+		registry.Register(f.fake, f.options)
+		cfg.EnabledProviders = append(cfg.EnabledProviders, f.fake.Name())
+	}
+
+	return &SearchService{
+		cfg:       cfg,
+		providers: registry,
+		converter: fx.NewConverter(fx.ChainSource{}, "EUR", time.Hour),
+	}
+}
+
+// orchestrationFlight builds an LHR-MAD flight on the test departure date
+func orchestrationFlight(flightNumber string, price int64) models.Flight {
+	flight := testFlight("", price, orchestrationDeparture)
+	flight.FlightNumber = flightNumber
+	flight.OriginAirport = "LHR"
+	flight.DestinationAirport = "MAD"
+	return flight
+}
+
+func orchestrationRequest() *models.FlightSearchRequest {
+	return &models.FlightSearchRequest{
+		OriginAirport:      "LHR",
+		DestinationAirport: "MAD",
+		DepartureDate:      orchestrationDeparture,
+	}
+}
+
+func TestOrchestrateSearchPriority(t *testing.T) {
+	// The preferred provider answers last but its offers still come first
+	primary := providers.NewFakeProvider("primary", orchestrationFlight("BA123", 100))
+	primary.SetLatency(30 * time.Millisecond)
+	secondary := providers.NewFakeProvider("secondary", orchestrationFlight("BA123", 100), orchestrationFlight("IB3163", 80))
+
+	s := newOrchestrationService(
+		fakeProvider{secondary, providers.Options{Priority: 2}},
+		fakeProvider{primary, providers.Options{Priority: 1}},
+	)
+
+	flights, metadata, err := s.orchestrateSearch(orchestrationRequest(), nil)
+	if err != nil {
+		t.Fatalf("orchestrateSearch() error = %v", err)
+	}
+
+	if got := strings.Join(metadata.ProvidersSuccessful, ","); got != "primary,secondary" {
+		t.Errorf("ProvidersSuccessful = %s, want primary,secondary", got)
+	}
+	if len(flights) != 2 || metadata.TotalResults != 2 || metadata.DuplicatesRemoved != 1 {
+		t.Fatalf("got %d flights, %d results and %d duplicates removed, want 2, 2 and 1",
+			len(flights), metadata.TotalResults, metadata.DuplicatesRemoved)
+	}
+	if flights[0].FlightNumber != "BA123" || flights[0].Provider != "primary" {
+		t.Errorf("first flight = %s from %s, want BA123 from primary on an equal fare",
+			flights[0].FlightNumber, flights[0].Provider)
+	}
+	if len(flights[0].ProviderOffers) != 2 {
+		t.Errorf("BA123 has %d provider offers, want 2", len(flights[0].ProviderOffers))
+	}
+}
+
+func TestOrchestrateSearchPartialFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(f *providers.FakeProvider) providers.Options
+		trip      bool // fail five searches first, which trips the breaker
+		wantError string
+		wantCalls int
+	}{
+		{
+			name: "slow provider",
+			configure: func(f *providers.FakeProvider) providers.Options {
+				f.SetLatency(time.Second)
+				return providers.Options{Timeout: 20 * time.Millisecond}
+			},
+			wantError: "timed out",
+			wantCalls: 1,
+		},
+		{
+			name: "failing provider",
+			configure: func(f *providers.FakeProvider) providers.Options {
+				f.SetError(errors.New("upstream unavailable"))
+				return providers.Options{}
+			},
+			wantError: "upstream unavailable",
+			wantCalls: 1,
+		},
+		{
+			name: "breaker open",
+			configure: func(f *providers.FakeProvider) providers.Options {
+				return providers.Options{}
+			},
+			trip:      true,
+			wantError: "Circuit breaker 'broken' is open",
+			wantCalls: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken := providers.NewFakeProvider("broken", orchestrationFlight("BA123", 100))
+			healthy := providers.NewFakeProvider("healthy", orchestrationFlight("IB3163", 80))
+			s := newOrchestrationService(
+				fakeProvider{broken, tt.configure(broken)},
+				fakeProvider{healthy, providers.Options{Priority: 1}},
+			)
+
+			if tt.trip {
+				// The provider recovers, but only after the breaker has opened
+				broken.SetError(errors.New("upstream unavailable"))
+				for i := 0; i < 5; i++ {
+					s.orchestrateSearch(orchestrationRequest(), nil)
+				}
+				broken.SetError(nil)
+			}
+
+			var mu sync.Mutex
+			answered := make(map[string]error)
+			flights, metadata, err := s.orchestrateSearch(orchestrationRequest(), func(provider string, flights []models.Flight, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				answered[provider] = err
+			})
+			if err != nil {
+				t.Fatalf("orchestrateSearch() error = %v", err)
+			}
+
+			if len(flights) != 1 || flights[0].Provider != "healthy" {
+				t.Fatalf("got %d flights, want only the healthy provider's IB3163", len(flights))
+			}
+			if got := strings.Join(metadata.ProvidersSuccessful, ","); got != "healthy" {
+				t.Errorf("ProvidersSuccessful = %s, want healthy", got)
+			}
+			if message := metadata.ProvidersErrors["broken"]; !strings.Contains(message, tt.wantError) {
+				t.Errorf("ProvidersErrors[broken] = %q, want it to contain %q", message, tt.wantError)
+			}
+			if answered["broken"] == nil || answered["healthy"] != nil || len(answered) != 2 {
+				t.Errorf("onResult errors = %v, want one for broken only", answered)
+			}
+			if calls := broken.Calls(); calls != tt.wantCalls {
+				t.Errorf("broken provider served %d searches, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestOrchestrateSearchAllProvidersFail(t *testing.T) {
+	failing := providers.NewFakeProvider("failing", orchestrationFlight("BA123", 100))
+	failing.SetError(errors.New("upstream unavailable"))
+	slow := providers.NewFakeProvider("slow", orchestrationFlight("IB3163", 80))
+	slow.SetLatency(time.Second)
+
+	s := newOrchestrationService(
+		fakeProvider{failing, providers.Options{}},
+		fakeProvider{slow, providers.Options{Timeout: 20 * time.Millisecond}},
+	)
+
+	flights, metadata, err := s.orchestrateSearch(orchestrationRequest(), nil)
+	if !errors.Is(err, errNoProviderResults) {
+		t.Fatalf("orchestrateSearch() error = %v, want %v", err, errNoProviderResults)
+	}
+	if flights != nil {
+		t.Errorf("orchestrateSearch() flights = %v, want none", flights)
+	}
+	if len(metadata.ProvidersErrors) != 2 {
+		t.Errorf("ProvidersErrors = %v, want both providers", metadata.ProvidersErrors)
+	}
+
+	// Expansion without an airport index searches the requested pair alone and fails the same way
+	req := orchestrationRequest()
+	req.ExpandAirports = true
+	if _, _, err := s.searchAirportPairs(req, nil); !errors.Is(err, errNoProviderResults) {
+		t.Errorf("searchAirportPairs() error = %v, want %v", err, errNoProviderResults)
+	}
+}
+
+func TestOrchestrateSearchUnknownProvider(t *testing.T) {
+	s := newOrchestrationService(fakeProvider{providers.NewFakeProvider("known", orchestrationFlight("BA123", 100)), providers.Options{}})
+	s.cfg.EnabledProviders = append(s.cfg.EnabledProviders, "missing")
+
+	flights, metadata, err := s.orchestrateSearch(orchestrationRequest(), nil)
+	if err != nil {
+		t.Fatalf("orchestrateSearch() error = %v", err)
+	}
+	if len(flights) != 1 {
+		t.Errorf("got %d flights, want 1", len(flights))
+	}
+	if metadata.ProvidersErrors["missing"] == "" {
+		t.Errorf("ProvidersErrors = %v, want an entry for the unregistered provider", metadata.ProvidersErrors)
+	}
+}
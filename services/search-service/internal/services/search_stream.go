@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/models"
+)
+
+// Server-sent event names emitted by a streamed search
+const (
+	StreamEventSearch   = "search"
+	StreamEventProvider = "provider"
+	StreamEventResults  = "results"
+	StreamEventSummary  = "summary"
+)
+
+// StreamEmitter writes one event to the client; an error means the client has gone away
+type StreamEmitter func(event string, data interface{}) error
+
+// searchStream stops emitting once the client has gone away
+type searchStream struct {
+	searchID uuid.UUID
+	emit     StreamEmitter
+	err      error
+}
+
+// send emits an event unless an earlier emit failed
+func (st *searchStream) send(event string, data interface{}) {
+	if st.err != nil {
+		return
+	}
+	if st.err = st.emit(event, data); st.err != nil {
+		log.Printf("Stream for search %s closed: %v", st.searchID, st.err)
+	}
+}
+
+// providerFlights holds the flights one provider returned during a streamed search
+type providerFlights struct {
+	provider string
+	flights  []models.Flight
+}
+
+// StreamSearch runs a single-date search and emits each provider's results as they arrive,
+// followed by the final results and a summary. Like any search it is served from the cache or
+// shares a search already in flight for the same request. Provider searches run to completion
+// even if the client goes away so that the results are still cached.
+func (s *SearchService) StreamSearch(req *models.FlightSearchRequest, topN int, emit StreamEmitter) error {
+	startTime := time.Now()
+
+	// Generate search ID
+	req.ID = uuid.New()
+	req.CreatedAt = time.Now()
+	req.FlexibleDates = false
+
+	// Validate request
+	if err := s.validateSearchRequest(req); err != nil {
+		return fmt.Errorf("invalid search request: %w", err)
+	}
+	topN = s.streamTopN(topN)
+
+	stream := &searchStream{searchID: req.ID, emit: emit}
+	stream.send(StreamEventSearch, map[string]interface{}{"search_id": req.ID})
+
+	var arrived []providerFlights
+	base, err := s.providerResults(req, startTime, func(provider string, flights []models.Flight, err error) {
+		event := models.StreamProviderEvent{
+			Provider:    provider,
+			ResultCount: len(flights),
+			ElapsedMs:   time.Since(startTime).Milliseconds(),
+		}
+		if err != nil {
+			event.Error = err.Error()
+			stream.send(StreamEventProvider, event)
+			return
+		}
+
+		arrived = append(arrived, providerFlights{provider: provider, flights: flights})
+		stream.send(StreamEventProvider, event)
+		stream.send(StreamEventResults, s.runningResults(req, arrived, topN))
+	})
+	if err != nil {
+		return err
+	}
+
+	response := s.buildResponse(req, base, startTime)
+	go s.storeSearchHistory(req, response)
+
+	stream.send(StreamEventResults, models.StreamResultsEvent{
+		SearchID:     response.SearchID,
+		Flights:      response.Flights,
+		TotalResults: response.SearchMetadata.TotalResults,
+		Final:        true,
+	})
+	stream.send(StreamEventSummary, models.StreamSummaryEvent{
+		SearchID:       response.SearchID,
+		SearchMetadata: response.SearchMetadata,
+	})
+
+	return nil
+}
+
+// ResumeStream replays the final results of an earlier search from its cached result set
+func (s *SearchService) ResumeStream(searchID uuid.UUID, emit StreamEmitter) error {
+	response, err := s.loadResultSet(searchID)
+	if err != nil {
+		return err
+	}
+
+	if limit := response.SearchRequest.MaxResults; limit > 0 && len(response.Flights) > limit {
+		response.Flights = response.Flights[:limit]
+	}
+	response.SearchMetadata.CacheHit = true
+	response.SearchMetadata.FromCache = true
+	response.SearchMetadata.ResultsReturned = len(response.Flights)
+
+	stream := &searchStream{searchID: searchID, emit: emit}
+	stream.send(StreamEventSearch, map[string]interface{}{"search_id": searchID, "resumed": true})
+	stream.send(StreamEventResults, models.StreamResultsEvent{
+		SearchID:     searchID,
+		Flights:      response.Flights,
+		TotalResults: response.SearchMetadata.TotalResults,
+		Final:        true,
+	})
+	stream.send(StreamEventSummary, models.StreamSummaryEvent{
+		SearchID:       searchID,
+		SearchMetadata: response.SearchMetadata,
+		Resumed:        true,
+	})
+
+	return nil
+}
+
+// runningResults merges the providers that have answered so far and returns the current top flights
+func (s *SearchService) runningResults(req *models.FlightSearchRequest, arrived []providerFlights, topN int) models.StreamResultsEvent {
+	// Merge in priority order so a running result matches what the final merge will keep
+	ordered := make([]providerFlights, len(arrived))
+	copy(ordered, arrived)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return s.providers.Priority(ordered[i].provider) < s.providers.Priority(ordered[j].provider)
+	})
+
+	var flights []models.Flight
+	for _, result := range ordered {
+		flights = append(flights, result.flights...)
+	}
+
+	merged := mergeFlights(s.localizeFlights(flights, s.displayCurrency(req.Currency)))
+	s.priceTrips(req, merged.flights)
+	filtered := s.applyFilters(merged.flights, req)
+	s.scoreFlights(req, filtered)
+	sorted := s.applySorting(filtered, req.SortBy, req.SortOrder)
+	tagParetoFrontier(sorted)
+
+	top := sorted
+	if len(top) > topN {
+		top = top[:topN]
+	}
+
+	return models.StreamResultsEvent{
+		SearchID:     req.ID,
+		Flights:      top,
+		TotalResults: len(merged.flights),
+	}
+}
+
+// streamTopN bounds the number of flights sent with each running update
+func (s *SearchService) streamTopN(topN int) int {
+	if topN <= 0 {
+		topN = s.cfg.StreamTopResults
+	}
+	if topN > s.cfg.MaxResultsLimit {
+		topN = s.cfg.MaxResultsLimit
+	}
+	return topN
+}
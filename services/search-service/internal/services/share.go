@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"spontra/search-service/internal/analytics"
+	"spontra/search-service/internal/config"
+	"spontra/search-service/internal/models"
+	"spontra/search-service/internal/repository"
+	"spontra/search-service/internal/sharing"
+)
+
+// Share actions recorded on flight_shared events
+const (
+	ShareActionCreated  = "created"
+	ShareActionResolved = "resolved"
+)
+
+// ErrFlightNotFound is returned when a flight is not part of a search's results
+var ErrFlightNotFound = errors.New("flight not found in search results")
+
+// ShareService mints and resolves signed links to searches and flights
+type ShareService struct {
+	cfg    *config.Config
+	search *SearchService
+	signer *sharing.Signer
+	events *analytics.Publisher
+}
+
+// NewShareService creates a new share service
+func NewShareService(cfg *config.Config, searchService *SearchService, events *analytics.Publisher) *ShareService {
+	return &ShareService{
+		cfg:    cfg,
+		search: searchService,
+		signer: sharing.NewSigner(cfg.ShareTokenSecret),
+		events: events,
+	}
+}
+
+// CreateShareLink mints a link to a search, or to one of its flights. The result set is
+// snapshotted for at least the link's lifetime so it resolves after the cache expires.
+func (s *ShareService) CreateShareLink(req *models.ShareLinkRequest, userID *uuid.UUID, sessionID string, eventContext analytics.EventContext) (*models.ShareLink, error) {
+	resultSet, err := s.sharedResultSet(req.SearchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flight *models.Flight
+	if req.FlightID != nil {
+		if flight = findFlight(resultSet.Flights, *req.FlightID); flight == nil {
+			return nil, ErrFlightNotFound
+		}
+	}
+
+	expiresAt := time.Now().Add(s.cfg.ShareTokenTTL).Truncate(time.Second)
+	if err := s.search.historyRepo.SaveResultSnapshot(resultSet, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to persist shared results: %w", err)
+	}
+
+	token := s.signer.Sign(sharing.Claims{
+		SearchID:  req.SearchID,
+		FlightID:  req.FlightID,
+		ExpiresAt: expiresAt,
+	})
+
+	s.trackShare(ShareActionCreated, resultSet, flight, userID, sessionID, eventContext)
+
+	return &models.ShareLink{
+		Token:     token,
+		URL:       s.cfg.ShareBaseURL + token,
+		SearchID:  req.SearchID,
+		FlightID:  req.FlightID,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ResolveShareLink returns what a share token points at. It needs no authentication, so
+// the sharer's user and session are removed from the returned search.
+func (s *ShareService) ResolveShareLink(token string, userID *uuid.UUID, sessionID string, eventContext analytics.EventContext) (*models.SharedSearch, error) {
+	claims, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet, err := s.sharedResultSet(claims.SearchID)
+	if err != nil {
+		return nil, err
+	}
+	resultSet.SearchRequest.UserID = nil
+	resultSet.SearchRequest.SearchSessionID = ""
+
+	shared := &models.SharedSearch{
+		SearchID:  claims.SearchID,
+		FlightID:  claims.FlightID,
+		ExpiresAt: claims.ExpiresAt,
+	}
+
+	var flight *models.Flight
+	if claims.FlightID != nil {
+		if flight = findFlight(resultSet.Flights, *claims.FlightID); flight == nil {
+			return nil, ErrFlightNotFound
+		}
+		shared.Flight = flight
+		shared.Request = &resultSet.SearchRequest
+	} else {
+		shared.Search = resultSet
+	}
+
+	s.trackShare(ShareActionResolved, resultSet, flight, userID, sessionID, eventContext)
+
+	return shared, nil
+}
+
+// sharedResultSet loads a search's result set from the cache or its snapshot. Shared
+// snapshots are read even when automatic snapshots are disabled.
+func (s *ShareService) sharedResultSet(searchID uuid.UUID) (*models.FlightSearchResponse, error) {
+	resultSet, err := s.search.cachedResultSet(searchID)
+	if !errors.Is(err, ErrSearchNotFound) {
+		return resultSet, err
+	}
+
+	resultSet, err = s.search.historyRepo.GetResultSnapshot(searchID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSnapshotNotFound) {
+			return nil, ErrSearchNotFound
+		}
+		return nil, err
+	}
+	resultSet.SearchMetadata.FromSnapshot = true
+	return resultSet, nil
+}
+
+// trackShare records a flight_shared event with the properties analytics-service expects
+// of flight events
+func (s *ShareService) trackShare(action string, resultSet *models.FlightSearchResponse, flight *models.Flight, userID *uuid.UUID, sessionID string, eventContext analytics.EventContext) {
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	event := analytics.NewEvent(analytics.EventFlightShared, userID, sessionID)
+	event.Context = eventContext
+	event.Properties["share_action"] = action
+	event.Properties["search_id"] = resultSet.SearchID.String()
+	event.Properties["origin"] = resultSet.SearchRequest.OriginAirport
+	event.Properties["destination"] = resultSet.SearchRequest.DestinationAirport
+
+	if flight != nil {
+		price, _ := flight.Price.Float64()
+		event.Properties["flight_id"] = flight.ID.String()
+		event.Properties["airline"] = flight.Airline
+		event.Properties["flight_number"] = flight.FlightNumber
+		event.Properties["origin"] = flight.OriginAirport
+		event.Properties["destination"] = flight.DestinationAirport
+		event.Properties["departure_time"] = flight.DepartureTime.Format(time.RFC3339)
+		event.Properties["arrival_time"] = flight.ArrivalTime.Format(time.RFC3339)
+		event.Properties["price"] = price
+		event.Properties["currency"] = flight.Currency
+		event.Properties["cabin_class"] = flight.CabinClass
+		event.Properties["duration_minutes"] = flight.Duration
+		event.Properties["stops"] = flight.Stops
+	}
+
+	s.events.Track(event)
+}
+
+// findFlight returns the flight with the given ID
+func findFlight(flights []models.Flight, flightID uuid.UUID) *models.Flight {
+	for i := range flights {
+		if flights[i].ID == flightID {
+			return &flights[i]
+		}
+	}
+	return nil
+}
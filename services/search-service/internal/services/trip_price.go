@@ -0,0 +1,105 @@
+package services
+
+import (
+	"log"
+
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/models"
+)
+
+// priceTrips sets every flight's trip price when the request declares travel needs. Flights
+// must already be in the display currency, so the trip prices of a result set compare.
+func (s *SearchService) priceTrips(req *models.FlightSearchRequest, flights []models.Flight) {
+	if req.TravelNeeds == nil {
+		return
+	}
+
+	passengers := req.PassengerCount
+	if passengers < 1 {
+		passengers = 1
+	}
+
+	for i := range flights {
+		flights[i].TripPrice = s.tripPrice(&flights[i], req.TravelNeeds, passengers)
+	}
+}
+
+// tripPrice adds the extras the passengers need beyond what the fare includes to the fare
+func (s *SearchService) tripPrice(flight *models.Flight, needs *models.TravelNeeds, passengers int) *models.TripPrice {
+	family := fareFamilyOf(flight)
+	trip := &models.TripPrice{Fare: flight.Price, Total: flight.Price}
+
+	addExtra := func(ancillaryType string, perPassenger int) {
+		if perPassenger <= 0 {
+			return
+		}
+		unitPrice, estimated, ok := s.ancillaryPrice(flight, ancillaryType)
+		if !ok {
+			trip.Incomplete = true
+			return
+		}
+		quantity := perPassenger * passengers
+		extra := models.TripExtra{
+			Type:      ancillaryType,
+			Quantity:  quantity,
+			Price:     unitPrice.Mul(decimal.NewFromInt(int64(quantity))),
+			Estimated: estimated,
+		}
+		trip.Extras = append(trip.Extras, extra)
+		trip.Total = trip.Total.Add(extra.Price)
+		trip.Estimated = trip.Estimated || estimated
+	}
+
+	addExtra(models.AncillaryCheckedBag, needs.CheckedBags-family.CheckedBags)
+	addExtra(models.AncillaryCabinBag, needs.CabinBags-family.CabinBags)
+	if needs.SeatSelection && family.SeatSelection != models.FareConditionIncluded {
+		addExtra(models.AncillarySeat, 1)
+	}
+	if needs.Changes && family.Changes != models.FareConditionIncluded {
+		addExtra(models.AncillaryChange, 1)
+	}
+
+	return trip
+}
+
+// fareFamilyOf returns what a flight's fare includes. Providers without fare families only
+// report whether a checked bag is included; a single cabin bag is assumed for them.
+func fareFamilyOf(flight *models.Flight) models.FareFamily {
+	if flight.FareFamily != nil {
+		return *flight.FareFamily
+	}
+
+	family := models.FareFamily{CabinBags: 1}
+	if flight.BaggageIncluded {
+		family.CheckedBags = 1
+	}
+	return family
+}
+
+// ancillaryPrice returns the per-passenger price of an extra in the flight's currency and
+// whether it was estimated. Extras the provider did not price are estimated from the configured
+// defaults; ok is false when such an estimate cannot be converted to the flight's currency.
+func (s *SearchService) ancillaryPrice(flight *models.Flight, ancillaryType string) (price decimal.Decimal, estimated bool, ok bool) {
+	for _, ancillary := range flight.Ancillaries {
+		if ancillary.Type == ancillaryType {
+			return ancillary.Price, false, true
+		}
+	}
+
+	estimate := decimal.NewFromFloat(s.cfg.AncillaryEstimates[ancillaryType])
+	price, err := s.converter.Convert(estimate, s.converter.Base(), s.displayCurrency(flight.Currency))
+	if err != nil {
+		log.Printf("Failed to convert %s estimate for flight %s, leaving it out of the trip price: %v", ancillaryType, flight.ID, err)
+		return decimal.Zero, true, false
+	}
+	return price, true, true
+}
+
+// effectivePrice is the price flights are ranked and filtered by: the trip price when the search
+// declares travel needs, otherwise the fare
+func effectivePrice(flight *models.Flight) decimal.Decimal {
+	if flight.TripPrice != nil {
+		return flight.TripPrice.Total
+	}
+	return flight.Price
+}
@@ -0,0 +1,111 @@
+package sharing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tokenVersion prefixes every payload so the format can change without accepting old tokens
+const tokenVersion byte = 1
+
+// signatureSize is the number of HMAC-SHA256 bytes kept in a token
+const signatureSize = 16
+
+var (
+	// ErrInvalidToken is returned for malformed tokens and tokens with a bad signature
+	ErrInvalidToken = errors.New("invalid share token")
+	// ErrTokenExpired is returned for well-formed tokens past their expiry
+	ErrTokenExpired = errors.New("share token has expired")
+)
+
+// Claims identify what a share token points at
+type Claims struct {
+	SearchID  uuid.UUID
+	FlightID  *uuid.UUID // nil when the whole search is shared
+	ExpiresAt time.Time
+}
+
+// Signer mints and verifies share tokens
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner creates a signer for the given secret
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign encodes the claims into a URL-safe token: a binary payload of version, search ID,
+// optional flight ID and expiry, followed by a truncated HMAC of the payload
+func (s *Signer) Sign(claims Claims) string {
+	payload := make([]byte, 0, 1+16+16+8)
+	payload = append(payload, tokenVersion)
+	payload = append(payload, claims.SearchID[:]...)
+	if claims.FlightID != nil {
+		payload = append(payload, claims.FlightID[:]...)
+	}
+	payload = binary.BigEndian.AppendUint64(payload, uint64(claims.ExpiresAt.Unix()))
+
+	return encode(payload) + "." + encode(s.sign(payload))
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func (s *Signer) Verify(token string) (*Claims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := decode(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := decode(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return nil, ErrInvalidToken
+	}
+
+	if len(payload) == 0 || payload[0] != tokenVersion {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	switch len(payload) {
+	case 1 + 16 + 8:
+	case 1 + 16 + 16 + 8:
+		flightID, _ := uuid.FromBytes(payload[17:33])
+		claims.FlightID = &flightID
+	default:
+		return nil, ErrInvalidToken
+	}
+	claims.SearchID, _ = uuid.FromBytes(payload[1:17])
+	claims.ExpiresAt = time.Unix(int64(binary.BigEndian.Uint64(payload[len(payload)-8:])), 0)
+
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// sign returns the truncated HMAC-SHA256 of a payload
+func (s *Signer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureSize]
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(data)
+}
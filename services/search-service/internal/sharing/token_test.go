@@ -0,0 +1,126 @@
+package sharing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSignVerify(t *testing.T) {
+	signer := NewSigner("secret")
+	searchID := uuid.New()
+	flightID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name   string
+		claims Claims
+	}{
+		{"search", Claims{SearchID: searchID, ExpiresAt: expiresAt}},
+		{"flight", Claims{SearchID: searchID, FlightID: &flightID, ExpiresAt: expiresAt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(signer.Sign(tt.claims))
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if claims.SearchID != tt.claims.SearchID {
+				t.Errorf("SearchID = %s, want %s", claims.SearchID, tt.claims.SearchID)
+			}
+			if (claims.FlightID == nil) != (tt.claims.FlightID == nil) {
+				t.Fatalf("FlightID = %v, want %v", claims.FlightID, tt.claims.FlightID)
+			}
+			if claims.FlightID != nil && *claims.FlightID != *tt.claims.FlightID {
+				t.Errorf("FlightID = %s, want %s", *claims.FlightID, *tt.claims.FlightID)
+			}
+			if !claims.ExpiresAt.Equal(tt.claims.ExpiresAt) {
+				t.Errorf("ExpiresAt = %s, want %s", claims.ExpiresAt, tt.claims.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestSignPayloadLength(t *testing.T) {
+	signer := NewSigner("secret")
+	flightID := uuid.New()
+
+	tests := []struct {
+		name     string
+		flightID *uuid.UUID
+		want     int
+	}{
+		{"search", nil, 1 + 16 + 8},
+		{"flight", &flightID, 1 + 16 + 16 + 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signer.Sign(Claims{SearchID: uuid.New(), FlightID: tt.flightID, ExpiresAt: time.Now().Add(time.Hour)})
+			encodedPayload, encodedSignature, _ := strings.Cut(token, ".")
+
+			payload, err := decode(encodedPayload)
+			if err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			if len(payload) != tt.want {
+				t.Errorf("payload length = %d, want %d", len(payload), tt.want)
+			}
+
+			signature, err := decode(encodedSignature)
+			if err != nil {
+				t.Fatalf("decode signature: %v", err)
+			}
+			if len(signature) != signatureSize {
+				t.Errorf("signature length = %d, want %d", len(signature), signatureSize)
+			}
+		})
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	signer := NewSigner("secret")
+	valid := signer.Sign(Claims{SearchID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)})
+	encodedPayload, encodedSignature, _ := strings.Cut(valid, ".")
+
+	payload, _ := decode(encodedPayload)
+	tampered := append([]byte{}, payload...)
+	tampered[1] ^= 0xff
+
+	wrongVersion := append([]byte{}, payload...)
+	wrongVersion[0] = tokenVersion + 1
+
+	truncated := payload[:len(payload)-1]
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"empty", "", ErrInvalidToken},
+		{"no separator", encodedPayload, ErrInvalidToken},
+		{"bad encoding", "!!!." + encodedSignature, ErrInvalidToken},
+		{"tampered payload", encode(tampered) + "." + encodedSignature, ErrInvalidToken},
+		{"tampered signature", encodedPayload + "." + encode(make([]byte, signatureSize)), ErrInvalidToken},
+		{"other secret", NewSigner("other").Sign(Claims{SearchID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}), ErrInvalidToken},
+		{"wrong version", encode(wrongVersion) + "." + encode(signer.sign(wrongVersion)), ErrInvalidToken},
+		{"wrong length", encode(truncated) + "." + encode(signer.sign(truncated)), ErrInvalidToken},
+		{"expired", signer.Sign(Claims{SearchID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}), ErrTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+			if claims != nil {
+				t.Errorf("Verify() claims = %+v, want nil", claims)
+			}
+		})
+	}
+}
@@ -2,21 +2,32 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"spontra/search-service/internal/analytics"
+	"spontra/search-service/internal/auth"
 	"spontra/search-service/internal/cache"
 	"spontra/search-service/internal/config"
 	"spontra/search-service/internal/database"
 	"spontra/search-service/internal/elasticsearch"
+	"spontra/search-service/internal/handlers"
+	"spontra/search-service/internal/metrics"
+	"spontra/search-service/internal/middleware"
 	"spontra/search-service/internal/models"
 	"spontra/search-service/internal/repository"
 	"spontra/search-service/internal/services"
+	"spontra/search-service/internal/sharing"
+	"spontra/shared/fx"
 )
 
 var (
@@ -25,11 +36,27 @@ var (
 	redisClient         *cache.RedisClient
 	elasticsearchClient *elasticsearch.Client
 	searchService       *services.SearchService
+	exploreService      *services.ExploreService
+	suggestService      *services.AirportSuggestService
+	shareService        *services.ShareService
+	eventPublisher      *analytics.Publisher
 	sessionRepo         *repository.SessionRepository
 	historyRepo         *repository.HistoryRepository
+	auditRepo           *repository.AuditRepository
 	httpClient          *http.Client
 )
 
+// rateLimitPolicies assigns routes a budget other than the default. Searches fan out to
+// providers, while autocomplete is called on every keystroke.
+var rateLimitPolicies = map[string]string{
+	"POST /api/v1/search/flights":                          middleware.PolicySearch,
+	"GET /api/v1/search/flights/stream":                    middleware.PolicySearch,
+	"POST /api/v1/search/history/:searchId/replay":         middleware.PolicySearch,
+	"POST /api/v1/search/history/session/:searchId/replay": middleware.PolicySearch,
+	"GET /api/v1/search/suggestions/airports":              middleware.PolicyAutocomplete,
+	"POST /api/v1/search/suggestions/airports/pick":        middleware.PolicyAutocomplete,
+}
+
 func main() {
 	// Load configuration
 	var err error
@@ -66,22 +93,44 @@ func main() {
 	// Initialize repositories
 	sessionRepo = repository.NewSessionRepository(db.DB)
 	historyRepo = repository.NewHistoryRepository(db.DB)
+	auditRepo = repository.NewAuditRepository(db.DB)
 	durationRepo := repository.NewDurationRepository(db.DB)
+	durationRepo.SetEstimator(services.NewDurationEstimator(cfg, elasticsearchClient))
 
 	// Initialize services
 	searchService = services.NewSearchService(cfg, db, redisClient, elasticsearchClient, sessionRepo, historyRepo)
+	exploreService = services.NewExploreService(cfg, redisClient, elasticsearchClient, durationRepo)
+
+	// Initialize analytics
+	eventPublisher = analytics.NewPublisher(cfg)
+	defer eventPublisher.Close()
+	shareService = services.NewShareService(cfg, searchService, eventPublisher)
+	suggestService = services.NewAirportSuggestService(cfg, redisClient, elasticsearchClient, eventPublisher)
 
 	// Initialize HTTP client
 	httpClient = &http.Client{
 		Timeout: cfg.ProviderTimeout,
 	}
 
+	// Expire old search history and result snapshots
+	go runHistoryRetention()
+
+	// Roll over and expire flight indices
+	go runFlightIndexLifecycle()
+
+	// Keep airport suggestions ranked by current traffic and picks
+	go runAirportSuggestionRebuild()
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.Default()
+	// Client IPs key the rate limiter, so only believe forwarding headers from known proxies
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -91,29 +140,63 @@ func main() {
 		})
 	})
 
+	// Access tokens are issued by user-service
+	verifier := auth.NewVerifier(cfg.JWTSecret)
+	sessions := auth.NewSessionSigner(cfg.SessionSecret)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.NewRateLimiter(cfg, redisClient, verifier, rateLimitPolicies).Middleware())
 	{
-		// Flight search routes
-		search := v1.Group("/search")
+		// Flight search routes, attributed to the verified user and the signed search session
+		search := v1.Group("/search", middleware.SearchSession(sessions), middleware.OptionalAuth(verifier))
 		{
 			search.POST("/flights", searchFlights)
+			search.GET("/flights/stream", streamSearchFlights)
 			search.GET("/flights/:searchId", getSearchResults)
 			search.POST("/flights/filter", filterFlights)
+			search.GET("/history", middleware.RequireAuth(verifier), listSearchHistory)
+			search.POST("/history/claim", middleware.RequireAuth(verifier), claimSearchHistory)
+			search.POST("/history/:searchId/replay", middleware.RequireAuth(verifier), replaySearch)
+			search.GET("/history/:searchId/diff/:replayId", middleware.RequireAuth(verifier), diffSearch)
+			search.GET("/history/session", listSearchHistory)
+			search.POST("/history/session/:searchId/replay", replaySearch)
+			search.GET("/history/session/:searchId/diff/:replayId", diffSearch)
+			search.POST("/share", createShareLink)
 			search.GET("/suggestions/airports", getAirportSuggestions)
+			search.POST("/suggestions/airports/pick", recordAirportPick)
+			search.GET("/providers", getProviderStatus)
+		}
+
+		// Public share link resolution
+		share := v1.Group("/share", middleware.SearchSession(sessions), middleware.OptionalAuth(verifier))
+		{
+			share.GET("/:token", resolveShareLink)
 		}
 
-		// Cache management routes
+		// Cache statistics
 		cache := v1.Group("/cache")
 		{
-			cache.DELETE("/clear", clearCache)
 			cache.GET("/stats", getCacheStats)
 		}
 
+		// Admin routes, restricted to user-service tokens with the admin role. Audit runs ahead of
+		// the role check so denied attempts are audited as well.
+		admin := v1.Group("/admin")
+		requireAdmin := middleware.RequireRole(verifier, auth.RoleAdmin)
+		{
+			sh := handlers.NewSearchHandler(searchService, elasticsearchClient, metrics.NewMetrics())
+			admin.DELETE("/cache", middleware.Audit(auditRepo, "cache.clear"), requireAdmin, clearCache)
+			admin.POST("/indices/optimize", middleware.Audit(auditRepo, "indices.optimize"), requireAdmin, sh.OptimizeIndices)
+			admin.POST("/indices/warmup", middleware.Audit(auditRepo, "indices.warmup"), requireAdmin, sh.WarmupCaches)
+			admin.GET("/audit", middleware.Audit(auditRepo, "audit.list"), requireAdmin, listAdminAudit)
+		}
+
 		// Destination exploration routes (proxy to data-ingestion-service)
 		explore := v1.Group("/explore")
 		{
 			explore.POST("/destinations", exploreDestinations)
+			explore.GET("/anywhere", exploreAnywhere)
 			explore.GET("/destinations/:airport/insights", getDestinationInsights)
 			explore.GET("/destinations/:airport/similar", findSimilarDestinations)
 		}
@@ -135,11 +218,12 @@ func main() {
 		// Flight durations (Postgres)
 		durations := v1.Group("/durations")
 		{
-			dh := handlers.NewDurationHandler(durationRepo)
+			dh := handlers.NewDurationHandler(durationRepo, services.NewConnectionBuilder(cfg, durationRepo))
 			durations.GET("/route", dh.GetRouteDuration)
 			durations.GET("/origin/:origin", dh.ListByOrigin)
 			durations.GET("/direct", dh.GetDirect)
 			durations.GET("/range", dh.ListByRange)
+			durations.GET("/paths", dh.Paths)
 			durations.GET("/popular", dh.PopularDestinations)
 			durations.GET("/stats/routes", dh.RouteStats)
 			durations.GET("/stats/connectivity/:airport", dh.Connectivity)
@@ -152,6 +236,65 @@ func main() {
 	}
 }
 
+// runHistoryRetention periodically deletes search history and result snapshots past their expiry
+func runHistoryRetention() {
+	if cfg.HistoryCleanupInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.HistoryCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := historyRepo.DeleteExpiredHistory()
+		if err != nil {
+			log.Printf("Search history retention failed: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d expired search history records", deleted)
+		}
+	}
+}
+
+// runFlightIndexLifecycle rolls the flight index over and deletes fully expired flight indices,
+// once at startup and then periodically
+func runFlightIndexLifecycle() {
+	if !cfg.EnableIndexLifecycle || cfg.IndexLifecycleInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.IndexLifecycleInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := elasticsearchClient.RunFlightIndexLifecycle(); err != nil {
+			log.Printf("Flight index lifecycle failed: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// runAirportSuggestionRebuild rebuilds the airport suggestion index at startup and then periodically
+func runAirportSuggestionRebuild() {
+	if cfg.AirportSuggestionRefresh <= 0 {
+		if _, err := suggestService.Rebuild(); err != nil {
+			log.Printf("Airport suggestion rebuild failed: %v", err)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(cfg.AirportSuggestionRefresh)
+	defer ticker.Stop()
+
+	for {
+		if _, err := suggestService.Rebuild(); err != nil {
+			log.Printf("Airport suggestion rebuild failed: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
 // Flight search handlers
 func searchFlights(c *gin.Context) {
 	var req models.FlightSearchRequest
@@ -177,20 +320,8 @@ func searchFlights(c *gin.Context) {
 		req.CabinClass = "economy"
 	}
 
-	// Get or create session
-	sessionID := c.GetHeader("X-Session-ID")
-	if sessionID == "" {
-		sessionID = uuid.New().String()
-	}
-	req.SearchSessionID = sessionID
-
-	// Get user ID from header if authenticated
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr != "" {
-		if userID, err := uuid.Parse(userIDStr); err == nil {
-			req.UserID = &userID
-		}
-	}
+	userID, sessionID := searchOwner(c)
+	req.UserID, req.SearchSessionID = userID, sessionID
 
 	// Perform search
 	response, err := searchService.SearchFlights(&req)
@@ -227,6 +358,20 @@ func getSearchResults(c *gin.Context) {
 		return
 	}
 
+	// Serve the full result set while it is cached or snapshotted
+	response, err := searchService.GetSearchResults(searchUUID, c.Query("currency"))
+	if err == nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if errors.Is(err, fx.ErrUnsupportedCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported currency", "details": err.Error()})
+		return
+	}
+	if !errors.Is(err, services.ErrSearchNotFound) {
+		log.Printf("Failed to load results of search %s: %v", searchUUID, err)
+	}
+
 	// Get search history
 	history, err := historyRepo.GetSearchHistory(searchUUID)
 	if err != nil {
@@ -247,14 +392,346 @@ func filterFlights(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement flight filtering logic
-	// This would apply filters to cached search results
+	response, err := searchService.FilterResults(&filter)
+	if err != nil {
+		if errors.Is(err, services.ErrSearchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Search results not found or expired"})
+			return
+		}
+		if errors.Is(err, fx.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported currency", "details": err.Error()})
+			return
+		}
+		log.Printf("Filter failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Filter failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Search history handlers
+func listSearchHistory(c *gin.Context) {
+	userID, sessionID := historyOwner(c)
+	if userID == nil && sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An X-Session-ID token issued by this service is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(cfg.DefaultMaxResults)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	histories, err := searchService.ListSearchHistory(userID, sessionID, limit, offset)
+	if err != nil {
+		log.Printf("Failed to list search history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list search history",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Filter applied",
-		"filter":  filter,
+		"searches": histories,
+		"limit":    limit,
+		"offset":   offset,
 	})
 }
 
+// claimSearchHistory moves the session's searches to the user of the verified access token. A
+// caller can only claim history for themselves, and only of a session whose token they hold.
+func claimSearchHistory(c *gin.Context) {
+	claims := middleware.ClaimsFromContext(c)
+	sessionID := middleware.PresentedSession(c)
+	if claims == nil || sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An X-Session-ID token issued by this service and an access token are required"})
+		return
+	}
+
+	claimed, err := searchService.ClaimSessionHistory(sessionID, claims.UserID)
+	if err != nil {
+		log.Printf("Failed to claim search history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to claim search history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"claimed": claimed})
+}
+
+func replaySearch(c *gin.Context) {
+	searchUUID, err := uuid.Parse(c.Param("searchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search ID format"})
+		return
+	}
+
+	userID, sessionID := historyOwner(c)
+	replay, err := searchService.ReplaySearch(searchUUID, userID, sessionID)
+	if err != nil {
+		searchHistoryError(c, "Replay failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, replay)
+}
+
+func diffSearch(c *gin.Context) {
+	searchUUID, err := uuid.Parse(c.Param("searchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search ID format"})
+		return
+	}
+	replayUUID, err := uuid.Parse(c.Param("replayId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replay ID format"})
+		return
+	}
+
+	userID, sessionID := historyOwner(c)
+	diff, err := searchService.DiffSearches(searchUUID, replayUUID, userID, sessionID)
+	if err != nil {
+		searchHistoryError(c, "Diff failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// historyOwner scopes history routes to the user of the verified access token. The anonymous
+// /history/session routes run without RequireAuth and are scoped to the search session the
+// client presented a signed token for, never to one the request has just started.
+func historyOwner(c *gin.Context) (*uuid.UUID, string) {
+	if claims := middleware.ClaimsFromContext(c); claims != nil {
+		return &claims.UserID, ""
+	}
+	return nil, middleware.PresentedSession(c)
+}
+
+// searchOwner returns the user of the request's verified access token, nil for anonymous
+// requests, and the request's search session, new or presented
+func searchOwner(c *gin.Context) (*uuid.UUID, string) {
+	var userID *uuid.UUID
+	if claims := middleware.ClaimsFromContext(c); claims != nil {
+		userID = &claims.UserID
+	}
+	return userID, middleware.SessionFromContext(c)
+}
+
+// searchHistoryError maps replay and diff errors to responses
+func searchHistoryError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrSearchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Search not found or expired"})
+	case errors.Is(err, services.ErrReplayExpired):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Search can no longer be replayed",
+			"details": err.Error(),
+		})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// Share link handlers
+func createShareLink(c *gin.Context) {
+	var req models.ShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, sessionID := searchOwner(c)
+	link, err := shareService.CreateShareLink(&req, userID, sessionID, analyticsEventContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSearchNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Search results not found or expired"})
+		case errors.Is(err, services.ErrFlightNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found in search results"})
+		default:
+			log.Printf("Failed to create share link: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to create share link",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+func resolveShareLink(c *gin.Context) {
+	userID, sessionID := searchOwner(c)
+	shared, err := shareService.ResolveShareLink(c.Param("token"), userID, sessionID, analyticsEventContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, sharing.ErrInvalidToken):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		case errors.Is(err, sharing.ErrTokenExpired):
+			c.JSON(http.StatusGone, gin.H{"error": "Share link has expired"})
+		case errors.Is(err, services.ErrSearchNotFound), errors.Is(err, services.ErrFlightNotFound):
+			c.JSON(http.StatusGone, gin.H{"error": "Shared results are no longer available"})
+		default:
+			log.Printf("Failed to resolve share link: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to resolve share link",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shared)
+}
+
+// analyticsEventContext captures the request context recorded on analytics events
+func analyticsEventContext(c *gin.Context) analytics.EventContext {
+	return analytics.EventContext{
+		UserAgent: c.GetHeader("User-Agent"),
+		IPAddress: c.ClientIP(),
+		Referrer:  c.GetHeader("Referer"),
+	}
+}
+
+func streamSearchFlights(c *gin.Context) {
+	topN, _ := strconv.Atoi(c.DefaultQuery("top", "0"))
+
+	started := false
+	emit := func(event string, data interface{}) error {
+		if !started {
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no")
+			started = true
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	}
+
+	// Resume a finished search from its cached results
+	if searchID := c.Query("search_id"); searchID != "" {
+		searchUUID, err := uuid.Parse(searchID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search ID format"})
+			return
+		}
+		if err := searchService.ResumeStream(searchUUID, emit); err != nil {
+			streamSearchError(c, started, err)
+		}
+		return
+	}
+
+	req, err := parseStreamSearchRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, sessionID := searchOwner(c)
+	req.UserID, req.SearchSessionID = userID, sessionID
+
+	if err := searchService.StreamSearch(req, topN, emit); err != nil {
+		log.Printf("Streamed search failed: %v", err)
+		streamSearchError(c, started, err)
+		return
+	}
+
+	go func() {
+		if err := sessionRepo.IncrementSearchCount(sessionID); err != nil {
+			log.Printf("Failed to increment search count: %v", err)
+		}
+	}()
+}
+
+// streamSearchError reports an error as JSON before the stream starts, or as an event after
+func streamSearchError(c *gin.Context, started bool, err error) {
+	if started {
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+		return
+	}
+	if errors.Is(err, services.ErrSearchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Search results not found or expired"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Search failed",
+		"details": err.Error(),
+	})
+}
+
+// parseStreamSearchRequest builds a search request from the stream endpoint's query parameters
+func parseStreamSearchRequest(c *gin.Context) (*models.FlightSearchRequest, error) {
+	departureDate, err := time.Parse("2006-01-02", c.Query("departure_date"))
+	if err != nil {
+		return nil, fmt.Errorf("departure_date must be a YYYY-MM-DD date")
+	}
+
+	passengers, err := strconv.Atoi(c.DefaultQuery("passengers", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("passengers must be a number")
+	}
+
+	req := &models.FlightSearchRequest{
+		OriginAirport:      c.Query("origin"),
+		DestinationAirport: c.Query("destination"),
+		DepartureDate:      departureDate,
+		PassengerCount:     passengers,
+		CabinClass:         c.DefaultQuery("cabin_class", "economy"),
+		TripType:           c.DefaultQuery("trip_type", "oneway"),
+		SortBy:             c.DefaultQuery("sort_by", "price"),
+		SortOrder:          c.DefaultQuery("sort_order", "asc"),
+		MaxResults:         cfg.DefaultMaxResults,
+		DirectFlightsOnly:  c.Query("direct_only") == "true",
+	}
+
+	if returnDate := c.Query("return_date"); returnDate != "" {
+		parsed, err := time.Parse("2006-01-02", returnDate)
+		if err != nil {
+			return nil, fmt.Errorf("return_date must be a YYYY-MM-DD date")
+		}
+		req.ReturnDate = &parsed
+		if c.Query("trip_type") == "" {
+			req.TripType = "return"
+		}
+	}
+	if maxResults := c.Query("max_results"); maxResults != "" {
+		if req.MaxResults, err = strconv.Atoi(maxResults); err != nil {
+			return nil, fmt.Errorf("max_results must be a number")
+		}
+	}
+	if maxStops := c.Query("max_stops"); maxStops != "" {
+		stops, err := strconv.Atoi(maxStops)
+		if err != nil {
+			return nil, fmt.Errorf("max_stops must be a number")
+		}
+		req.MaxStops = &stops
+	}
+	if airlines := c.Query("airlines"); airlines != "" {
+		req.PreferredAirlines = strings.Split(airlines, ",")
+	}
+
+	return req, nil
+}
+
 func getAirportSuggestions(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
@@ -268,19 +745,12 @@ func getAirportSuggestions(c *gin.Context) {
 		limit = 10
 	}
 
-	// Check cache first
-	cacheKey := cache.NewCacheKeyBuilder("airport").AirportSuggestions(query)
-	var suggestions []models.AirportSuggestion
-	if err := redisClient.Get(cacheKey, &suggestions); err == nil {
-		c.JSON(http.StatusOK, gin.H{
-			"suggestions": suggestions,
-			"from_cache":  true,
-		})
-		return
+	suggestions, fromCache, err := suggestService.Suggest(query, limit)
+	if err != nil {
+		// Fall back to plain airport search while the suggestion index is unavailable
+		log.Printf("Airport suggestions failed, falling back to airport search: %v", err)
+		suggestions, err = elasticsearchClient.SearchAirports(query, limit)
 	}
-
-	// Search Elasticsearch
-	suggestions, err = elasticsearchClient.SearchAirports(query, limit)
 	if err != nil {
 		log.Printf("Airport search failed: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -290,16 +760,65 @@ func getAirportSuggestions(c *gin.Context) {
 		return
 	}
 
-	// Cache results for 1 hour
-	go func() {
-		if err := redisClient.Set(cacheKey, suggestions, cfg.AirportCacheTTL); err != nil {
-			log.Printf("Failed to cache airport suggestions: %v", err)
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+		"from_cache":  fromCache,
+	})
+}
+
+// recordAirportPick records the autocomplete suggestion a user picked, which ranks it higher
+func recordAirportPick(c *gin.Context) {
+	var req models.AirportPickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, sessionID := searchOwner(c)
+	if err := suggestService.RecordPick(&req, userID, sessionID, analyticsEventContext(c)); err != nil {
+		if errors.Is(err, services.ErrUnknownSuggestionType) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid suggestion type",
+				"details": err.Error(),
+			})
+			return
 		}
-	}()
+		if errors.Is(err, services.ErrUnknownSuggestion) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Unknown suggestion",
+				"details": err.Error(),
+			})
+			return
+		}
+		log.Printf("Failed to record airport pick: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to record airport pick",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func getProviderStatus(c *gin.Context) {
+	statuses := searchService.ProviderStatus(c.Request.Context())
+
+	healthy := 0
+	for _, status := range statuses {
+		if status.Healthy {
+			healthy++
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"suggestions": suggestions,
-		"from_cache":  false,
+		"providers": statuses,
+		"enabled":   cfg.EnabledProviders,
+		"healthy":   healthy,
+		"count":     len(statuses),
 	})
 }
 
@@ -322,6 +841,44 @@ func clearCache(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Cache cleared successfully"})
 }
 
+// listAdminAudit lists admin audit log entries, newest first, optionally filtered by actor_id
+func listAdminAudit(c *gin.Context) {
+	var actorID *uuid.UUID
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		parsed, err := uuid.Parse(actorIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id"})
+			return
+		}
+		actorID = &parsed
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(cfg.DefaultMaxResults)))
+	if limit <= 0 || limit > cfg.MaxResultsLimit {
+		limit = cfg.DefaultMaxResults
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := auditRepo.ListAdminActions(actorID, limit, offset)
+	if err != nil {
+		log.Printf("Failed to list admin audit log: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list admin audit log",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
 func getCacheStats(c *gin.Context) {
 	stats, err := redisClient.GetStats()
 	if err != nil {
@@ -335,6 +892,85 @@ func getCacheStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// exploreAnywhere lists the cheapest destinations reachable from an origin within a budget
+func exploreAnywhere(c *gin.Context) {
+	req, err := parseExploreRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid explore request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := exploreService.ExploreAnywhere(req)
+	if errors.Is(err, services.ErrInvalidExploreRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid explore request",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Explore anywhere failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Explore failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseExploreRequest builds an explore request from query parameters
+func parseExploreRequest(c *gin.Context) (*models.ExploreRequest, error) {
+	origin := c.Query("origin")
+	if origin == "" {
+		return nil, fmt.Errorf("origin is required")
+	}
+
+	budget, err := decimal.NewFromString(c.Query("max_budget"))
+	if err != nil {
+		return nil, fmt.Errorf("max_budget must be a number")
+	}
+
+	req := &models.ExploreRequest{
+		OriginAirport: origin,
+		MaxBudget:     budget,
+		Currency:      c.Query("currency"),
+		DirectOnly:    c.Query("direct_only") == "true",
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		if req.DateFrom, err = time.Parse("2006-01-02", dateFrom); err != nil {
+			return nil, fmt.Errorf("date_from must be a YYYY-MM-DD date")
+		}
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		if req.DateTo, err = time.Parse("2006-01-02", dateTo); err != nil {
+			return nil, fmt.Errorf("date_to must be a YYYY-MM-DD date")
+		}
+	}
+	if minDuration := c.Query("min_duration"); minDuration != "" {
+		if req.MinDurationMinutes, err = strconv.Atoi(minDuration); err != nil {
+			return nil, fmt.Errorf("min_duration must be a number of minutes")
+		}
+	}
+	if maxDuration := c.Query("max_duration"); maxDuration != "" {
+		if req.MaxDurationMinutes, err = strconv.Atoi(maxDuration); err != nil {
+			return nil, fmt.Errorf("max_duration must be a number of minutes")
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if req.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, fmt.Errorf("limit must be a number")
+		}
+	}
+
+	return req, nil
+}
+
 // Destination exploration handlers (proxy to data-ingestion-service)
 
 // exploreDestinations proxies destination discovery requests
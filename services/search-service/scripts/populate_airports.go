@@ -11,170 +11,178 @@ import (
 
 // Airport represents airport data for Elasticsearch
 type Airport struct {
-	Code        string `json:"code"`
-	Name        string `json:"name"`
-	City        string `json:"city"`
-	Country     string `json:"country"`
-	CountryCode string `json:"country_code"`
-	Type        string `json:"type"`
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	City        string   `json:"city"`
+	Country     string   `json:"country"`
+	CountryCode string   `json:"country_code"`
+	Type        string   `json:"type"`
+	CityCode    string   `json:"city_code"` // IATA metropolitan code, or the airport code itself
+	Coordinates GeoPoint `json:"coordinates"`
+}
+
+// GeoPoint is an Elasticsearch geo_point
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 // European airports data for search suggestions
 var europeanAirports = []Airport{
 	// UK & Ireland
-	{"LHR", "London Heathrow Airport", "London", "United Kingdom", "GB", "airport"},
-	{"LGW", "London Gatwick Airport", "London", "United Kingdom", "GB", "airport"},
-	{"STN", "London Stansted Airport", "London", "United Kingdom", "GB", "airport"},
-	{"LTN", "London Luton Airport", "London", "United Kingdom", "GB", "airport"},
-	{"MAN", "Manchester Airport", "Manchester", "United Kingdom", "GB", "airport"},
-	{"EDI", "Edinburgh Airport", "Edinburgh", "United Kingdom", "GB", "airport"},
-	{"GLA", "Glasgow Airport", "Glasgow", "United Kingdom", "GB", "airport"},
-	{"BHX", "Birmingham Airport", "Birmingham", "United Kingdom", "GB", "airport"},
-	{"LPL", "Liverpool John Lennon Airport", "Liverpool", "United Kingdom", "GB", "airport"},
-	{"NCL", "Newcastle Airport", "Newcastle", "United Kingdom", "GB", "airport"},
-	{"DUB", "Dublin Airport", "Dublin", "Ireland", "IE", "airport"},
-	{"ORK", "Cork Airport", "Cork", "Ireland", "IE", "airport"},
+	{"LHR", "London Heathrow Airport", "London", "United Kingdom", "GB", "airport", "LON", GeoPoint{51.4700, -0.4543}},
+	{"LGW", "London Gatwick Airport", "London", "United Kingdom", "GB", "airport", "LON", GeoPoint{51.1537, -0.1821}},
+	{"STN", "London Stansted Airport", "London", "United Kingdom", "GB", "airport", "LON", GeoPoint{51.8860, 0.2389}},
+	{"LTN", "London Luton Airport", "London", "United Kingdom", "GB", "airport", "LON", GeoPoint{51.8747, -0.3683}},
+	{"MAN", "Manchester Airport", "Manchester", "United Kingdom", "GB", "airport", "MAN", GeoPoint{53.3537, -2.2750}},
+	{"EDI", "Edinburgh Airport", "Edinburgh", "United Kingdom", "GB", "airport", "EDI", GeoPoint{55.9500, -3.3725}},
+	{"GLA", "Glasgow Airport", "Glasgow", "United Kingdom", "GB", "airport", "GLA", GeoPoint{55.8719, -4.4331}},
+	{"BHX", "Birmingham Airport", "Birmingham", "United Kingdom", "GB", "airport", "BHX", GeoPoint{52.4539, -1.7480}},
+	{"LPL", "Liverpool John Lennon Airport", "Liverpool", "United Kingdom", "GB", "airport", "LPL", GeoPoint{53.3336, -2.8497}},
+	{"NCL", "Newcastle Airport", "Newcastle", "United Kingdom", "GB", "airport", "NCL", GeoPoint{55.0375, -1.6917}},
+	{"DUB", "Dublin Airport", "Dublin", "Ireland", "IE", "airport", "DUB", GeoPoint{53.4213, -6.2701}},
+	{"ORK", "Cork Airport", "Cork", "Ireland", "IE", "airport", "ORK", GeoPoint{51.8413, -8.4911}},
 	
 	// France
-	{"CDG", "Charles de Gaulle Airport", "Paris", "France", "FR", "airport"},
-	{"ORY", "Paris Orly Airport", "Paris", "France", "FR", "airport"},
-	{"NCE", "Nice Côte d'Azur Airport", "Nice", "France", "FR", "airport"},
-	{"LYS", "Lyon Saint-Exupéry Airport", "Lyon", "France", "FR", "airport"},
-	{"MRS", "Marseille Provence Airport", "Marseille", "France", "FR", "airport"},
-	{"TLS", "Toulouse-Blagnac Airport", "Toulouse", "France", "FR", "airport"},
-	{"NTE", "Nantes Atlantique Airport", "Nantes", "France", "FR", "airport"},
-	{"BOD", "Bordeaux-Mérignac Airport", "Bordeaux", "France", "FR", "airport"},
-	{"LIL", "Lille Airport", "Lille", "France", "FR", "airport"},
-	{"SXB", "Strasbourg Airport", "Strasbourg", "France", "FR", "airport"},
+	{"CDG", "Charles de Gaulle Airport", "Paris", "France", "FR", "airport", "PAR", GeoPoint{49.0097, 2.5479}},
+	{"ORY", "Paris Orly Airport", "Paris", "France", "FR", "airport", "PAR", GeoPoint{48.7262, 2.3652}},
+	{"NCE", "Nice Côte d'Azur Airport", "Nice", "France", "FR", "airport", "NCE", GeoPoint{43.6584, 7.2159}},
+	{"LYS", "Lyon Saint-Exupéry Airport", "Lyon", "France", "FR", "airport", "LYS", GeoPoint{45.7256, 5.0811}},
+	{"MRS", "Marseille Provence Airport", "Marseille", "France", "FR", "airport", "MRS", GeoPoint{43.4393, 5.2214}},
+	{"TLS", "Toulouse-Blagnac Airport", "Toulouse", "France", "FR", "airport", "TLS", GeoPoint{43.6291, 1.3638}},
+	{"NTE", "Nantes Atlantique Airport", "Nantes", "France", "FR", "airport", "NTE", GeoPoint{47.1532, -1.6107}},
+	{"BOD", "Bordeaux-Mérignac Airport", "Bordeaux", "France", "FR", "airport", "BOD", GeoPoint{44.8283, -0.7156}},
+	{"LIL", "Lille Airport", "Lille", "France", "FR", "airport", "LIL", GeoPoint{50.5633, 3.0869}},
+	{"SXB", "Strasbourg Airport", "Strasbourg", "France", "FR", "airport", "SXB", GeoPoint{48.5383, 7.6282}},
 	
 	// Germany
-	{"FRA", "Frankfurt Airport", "Frankfurt", "Germany", "DE", "airport"},
-	{"MUC", "Munich Airport", "Munich", "Germany", "DE", "airport"},
-	{"BER", "Berlin Brandenburg Airport", "Berlin", "Germany", "DE", "airport"},
-	{"DUS", "Düsseldorf Airport", "Düsseldorf", "Germany", "DE", "airport"},
-	{"HAM", "Hamburg Airport", "Hamburg", "Germany", "DE", "airport"},
-	{"CGN", "Cologne Bonn Airport", "Cologne", "Germany", "DE", "airport"},
-	{"STR", "Stuttgart Airport", "Stuttgart", "Germany", "DE", "airport"},
-	{"HAJ", "Hannover Airport", "Hannover", "Germany", "DE", "airport"},
-	{"NUE", "Nuremberg Airport", "Nuremberg", "Germany", "DE", "airport"},
-	{"LEJ", "Leipzig/Halle Airport", "Leipzig", "Germany", "DE", "airport"},
+	{"FRA", "Frankfurt Airport", "Frankfurt", "Germany", "DE", "airport", "FRA", GeoPoint{50.0379, 8.5622}},
+	{"MUC", "Munich Airport", "Munich", "Germany", "DE", "airport", "MUC", GeoPoint{48.3538, 11.7861}},
+	{"BER", "Berlin Brandenburg Airport", "Berlin", "Germany", "DE", "airport", "BER", GeoPoint{52.3667, 13.5033}},
+	{"DUS", "Düsseldorf Airport", "Düsseldorf", "Germany", "DE", "airport", "DUS", GeoPoint{51.2895, 6.7668}},
+	{"HAM", "Hamburg Airport", "Hamburg", "Germany", "DE", "airport", "HAM", GeoPoint{53.6304, 9.9882}},
+	{"CGN", "Cologne Bonn Airport", "Cologne", "Germany", "DE", "airport", "CGN", GeoPoint{50.8659, 7.1427}},
+	{"STR", "Stuttgart Airport", "Stuttgart", "Germany", "DE", "airport", "STR", GeoPoint{48.6899, 9.2220}},
+	{"HAJ", "Hannover Airport", "Hannover", "Germany", "DE", "airport", "HAJ", GeoPoint{52.4611, 9.6851}},
+	{"NUE", "Nuremberg Airport", "Nuremberg", "Germany", "DE", "airport", "NUE", GeoPoint{49.4987, 11.0669}},
+	{"LEJ", "Leipzig/Halle Airport", "Leipzig", "Germany", "DE", "airport", "LEJ", GeoPoint{51.4324, 12.2416}},
 	
 	// Spain
-	{"MAD", "Madrid-Barajas Airport", "Madrid", "Spain", "ES", "airport"},
-	{"BCN", "Barcelona-El Prat Airport", "Barcelona", "Spain", "ES", "airport"},
-	{"PMI", "Palma de Mallorca Airport", "Palma", "Spain", "ES", "airport"},
-	{"SVQ", "Sevilla Airport", "Seville", "Spain", "ES", "airport"},
-	{"VLC", "Valencia Airport", "Valencia", "Spain", "ES", "airport"},
-	{"BIO", "Bilbao Airport", "Bilbao", "Spain", "ES", "airport"},
-	{"AGP", "Málaga Airport", "Málaga", "Spain", "ES", "airport"},
-	{"LPA", "Las Palmas Airport", "Las Palmas", "Spain", "ES", "airport"},
-	{"TFS", "Tenerife South Airport", "Tenerife", "Spain", "ES", "airport"},
-	{"ALC", "Alicante Airport", "Alicante", "Spain", "ES", "airport"},
+	{"MAD", "Madrid-Barajas Airport", "Madrid", "Spain", "ES", "airport", "MAD", GeoPoint{40.4983, -3.5676}},
+	{"BCN", "Barcelona-El Prat Airport", "Barcelona", "Spain", "ES", "airport", "BCN", GeoPoint{41.2974, 2.0833}},
+	{"PMI", "Palma de Mallorca Airport", "Palma", "Spain", "ES", "airport", "PMI", GeoPoint{39.5517, 2.7388}},
+	{"SVQ", "Sevilla Airport", "Seville", "Spain", "ES", "airport", "SVQ", GeoPoint{37.4180, -5.8931}},
+	{"VLC", "Valencia Airport", "Valencia", "Spain", "ES", "airport", "VLC", GeoPoint{39.4893, -0.4816}},
+	{"BIO", "Bilbao Airport", "Bilbao", "Spain", "ES", "airport", "BIO", GeoPoint{43.3011, -2.9106}},
+	{"AGP", "Málaga Airport", "Málaga", "Spain", "ES", "airport", "AGP", GeoPoint{36.6749, -4.4991}},
+	{"LPA", "Las Palmas Airport", "Las Palmas", "Spain", "ES", "airport", "LPA", GeoPoint{27.9319, -15.3866}},
+	{"TFS", "Tenerife South Airport", "Tenerife", "Spain", "ES", "airport", "TCI", GeoPoint{28.0445, -16.5725}},
+	{"ALC", "Alicante Airport", "Alicante", "Spain", "ES", "airport", "ALC", GeoPoint{38.2822, -0.5582}},
 	
 	// Italy
-	{"FCO", "Rome Fiumicino Airport", "Rome", "Italy", "IT", "airport"},
-	{"MXP", "Milan Malpensa Airport", "Milan", "Italy", "IT", "airport"},
-	{"LIN", "Milan Linate Airport", "Milan", "Italy", "IT", "airport"},
-	{"NAP", "Naples Airport", "Naples", "Italy", "IT", "airport"},
-	{"VCE", "Venice Marco Polo Airport", "Venice", "Italy", "IT", "airport"},
-	{"BGY", "Milan Bergamo Airport", "Bergamo", "Italy", "IT", "airport"},
-	{"BLQ", "Bologna Airport", "Bologna", "Italy", "IT", "airport"},
-	{"FLR", "Florence Airport", "Florence", "Italy", "IT", "airport"},
-	{"PSA", "Pisa Airport", "Pisa", "Italy", "IT", "airport"},
-	{"CTA", "Catania Airport", "Catania", "Italy", "IT", "airport"},
+	{"FCO", "Rome Fiumicino Airport", "Rome", "Italy", "IT", "airport", "ROM", GeoPoint{41.8003, 12.2389}},
+	{"MXP", "Milan Malpensa Airport", "Milan", "Italy", "IT", "airport", "MIL", GeoPoint{45.6306, 8.7281}},
+	{"LIN", "Milan Linate Airport", "Milan", "Italy", "IT", "airport", "MIL", GeoPoint{45.4451, 9.2767}},
+	{"NAP", "Naples Airport", "Naples", "Italy", "IT", "airport", "NAP", GeoPoint{40.8860, 14.2908}},
+	{"VCE", "Venice Marco Polo Airport", "Venice", "Italy", "IT", "airport", "VCE", GeoPoint{45.5053, 12.3519}},
+	{"BGY", "Milan Bergamo Airport", "Bergamo", "Italy", "IT", "airport", "MIL", GeoPoint{45.6739, 9.7042}},
+	{"BLQ", "Bologna Airport", "Bologna", "Italy", "IT", "airport", "BLQ", GeoPoint{44.5354, 11.2887}},
+	{"FLR", "Florence Airport", "Florence", "Italy", "IT", "airport", "FLR", GeoPoint{43.8100, 11.2051}},
+	{"PSA", "Pisa Airport", "Pisa", "Italy", "IT", "airport", "PSA", GeoPoint{43.6839, 10.3927}},
+	{"CTA", "Catania Airport", "Catania", "Italy", "IT", "airport", "CTA", GeoPoint{37.4668, 15.0664}},
 	
 	// Netherlands
-	{"AMS", "Amsterdam Schiphol Airport", "Amsterdam", "Netherlands", "NL", "airport"},
-	{"EIN", "Eindhoven Airport", "Eindhoven", "Netherlands", "NL", "airport"},
-	{"RTM", "Rotterdam The Hague Airport", "Rotterdam", "Netherlands", "NL", "airport"},
-	{"GRQ", "Groningen Airport Eelde", "Groningen", "Netherlands", "NL", "airport"},
+	{"AMS", "Amsterdam Schiphol Airport", "Amsterdam", "Netherlands", "NL", "airport", "AMS", GeoPoint{52.3105, 4.7683}},
+	{"EIN", "Eindhoven Airport", "Eindhoven", "Netherlands", "NL", "airport", "EIN", GeoPoint{51.4501, 5.3745}},
+	{"RTM", "Rotterdam The Hague Airport", "Rotterdam", "Netherlands", "NL", "airport", "RTM", GeoPoint{51.9569, 4.4372}},
+	{"GRQ", "Groningen Airport Eelde", "Groningen", "Netherlands", "NL", "airport", "GRQ", GeoPoint{53.1197, 6.5794}},
 	
 	// Belgium
-	{"BRU", "Brussels Airport", "Brussels", "Belgium", "BE", "airport"},
-	{"CRL", "Brussels South Charleroi Airport", "Charleroi", "Belgium", "BE", "airport"},
-	{"ANR", "Antwerp Airport", "Antwerp", "Belgium", "BE", "airport"},
-	{"LGG", "Liège Airport", "Liège", "Belgium", "BE", "airport"},
+	{"BRU", "Brussels Airport", "Brussels", "Belgium", "BE", "airport", "BRU", GeoPoint{50.9014, 4.4844}},
+	{"CRL", "Brussels South Charleroi Airport", "Charleroi", "Belgium", "BE", "airport", "CRL", GeoPoint{50.4592, 4.4538}},
+	{"ANR", "Antwerp Airport", "Antwerp", "Belgium", "BE", "airport", "ANR", GeoPoint{51.1894, 4.4603}},
+	{"LGG", "Liège Airport", "Liège", "Belgium", "BE", "airport", "LGG", GeoPoint{50.6374, 5.4432}},
 	
 	// Switzerland
-	{"ZUR", "Zurich Airport", "Zurich", "Switzerland", "CH", "airport"},
-	{"GVA", "Geneva Airport", "Geneva", "Switzerland", "CH", "airport"},
-	{"BSL", "Basel-Mulhouse-Freiburg Airport", "Basel", "Switzerland", "CH", "airport"},
-	{"BRN", "Bern Airport", "Bern", "Switzerland", "CH", "airport"},
+	{"ZUR", "Zurich Airport", "Zurich", "Switzerland", "CH", "airport", "ZUR", GeoPoint{47.4647, 8.5492}},
+	{"GVA", "Geneva Airport", "Geneva", "Switzerland", "CH", "airport", "GVA", GeoPoint{46.2381, 6.1090}},
+	{"BSL", "Basel-Mulhouse-Freiburg Airport", "Basel", "Switzerland", "CH", "airport", "BSL", GeoPoint{47.5896, 7.5299}},
+	{"BRN", "Bern Airport", "Bern", "Switzerland", "CH", "airport", "BRN", GeoPoint{46.9141, 7.4971}},
 	
 	// Austria
-	{"VIE", "Vienna International Airport", "Vienna", "Austria", "AT", "airport"},
-	{"SZG", "Salzburg Airport", "Salzburg", "Austria", "AT", "airport"},
-	{"INN", "Innsbruck Airport", "Innsbruck", "Austria", "AT", "airport"},
-	{"GRZ", "Graz Airport", "Graz", "Austria", "AT", "airport"},
+	{"VIE", "Vienna International Airport", "Vienna", "Austria", "AT", "airport", "VIE", GeoPoint{48.1103, 16.5697}},
+	{"SZG", "Salzburg Airport", "Salzburg", "Austria", "AT", "airport", "SZG", GeoPoint{47.7933, 13.0043}},
+	{"INN", "Innsbruck Airport", "Innsbruck", "Austria", "AT", "airport", "INN", GeoPoint{47.2602, 11.3440}},
+	{"GRZ", "Graz Airport", "Graz", "Austria", "AT", "airport", "GRZ", GeoPoint{46.9911, 15.4396}},
 	
 	// Scandinavia
-	{"ARN", "Stockholm Arlanda Airport", "Stockholm", "Sweden", "SE", "airport"},
-	{"CPH", "Copenhagen Airport", "Copenhagen", "Denmark", "DK", "airport"},
-	{"OSL", "Oslo Gardermoen Airport", "Oslo", "Norway", "NO", "airport"},
-	{"HEL", "Helsinki-Vantaa Airport", "Helsinki", "Finland", "FI", "airport"},
-	{"GOT", "Gothenburg-Landvetter Airport", "Gothenburg", "Sweden", "SE", "airport"},
-	{"STO", "Stockholm Bromma Airport", "Stockholm", "Sweden", "SE", "airport"},
-	{"AAL", "Aalborg Airport", "Aalborg", "Denmark", "DK", "airport"},
-	{"BGO", "Bergen Airport", "Bergen", "Norway", "NO", "airport"},
-	{"TRD", "Trondheim Airport", "Trondheim", "Norway", "NO", "airport"},
-	{"TMP", "Tampere-Pirkkala Airport", "Tampere", "Finland", "FI", "airport"},
+	{"ARN", "Stockholm Arlanda Airport", "Stockholm", "Sweden", "SE", "airport", "STO", GeoPoint{59.6498, 17.9238}},
+	{"CPH", "Copenhagen Airport", "Copenhagen", "Denmark", "DK", "airport", "CPH", GeoPoint{55.6180, 12.6508}},
+	{"OSL", "Oslo Gardermoen Airport", "Oslo", "Norway", "NO", "airport", "OSL", GeoPoint{60.1976, 11.1004}},
+	{"HEL", "Helsinki-Vantaa Airport", "Helsinki", "Finland", "FI", "airport", "HEL", GeoPoint{60.3172, 24.9633}},
+	{"GOT", "Gothenburg-Landvetter Airport", "Gothenburg", "Sweden", "SE", "airport", "GOT", GeoPoint{57.6628, 12.2798}},
+	{"BMA", "Stockholm Bromma Airport", "Stockholm", "Sweden", "SE", "airport", "STO", GeoPoint{59.3544, 17.9417}},
+	{"AAL", "Aalborg Airport", "Aalborg", "Denmark", "DK", "airport", "AAL", GeoPoint{57.0928, 9.8492}},
+	{"BGO", "Bergen Airport", "Bergen", "Norway", "NO", "airport", "BGO", GeoPoint{60.2934, 5.2181}},
+	{"TRD", "Trondheim Airport", "Trondheim", "Norway", "NO", "airport", "TRD", GeoPoint{63.4578, 10.9240}},
+	{"TMP", "Tampere-Pirkkala Airport", "Tampere", "Finland", "FI", "airport", "TMP", GeoPoint{61.4141, 23.6044}},
 	
 	// Eastern Europe
-	{"WAW", "Warsaw Chopin Airport", "Warsaw", "Poland", "PL", "airport"},
-	{"KRK", "Kraków Airport", "Kraków", "Poland", "PL", "airport"},
-	{"GDN", "Gdańsk Airport", "Gdańsk", "Poland", "PL", "airport"},
-	{"WRO", "Wrocław Airport", "Wrocław", "Poland", "PL", "airport"},
-	{"PRG", "Prague Václav Havel Airport", "Prague", "Czech Republic", "CZ", "airport"},
-	{"BUD", "Budapest Ferenc Liszt Airport", "Budapest", "Hungary", "HU", "airport"},
-	{"OTP", "Bucharest Henri Coandă Airport", "Bucharest", "Romania", "RO", "airport"},
-	{"CLJ", "Cluj-Napoca Airport", "Cluj-Napoca", "Romania", "RO", "airport"},
-	{"BTS", "Bratislava Airport", "Bratislava", "Slovakia", "SK", "airport"},
-	{"SOF", "Sofia Airport", "Sofia", "Bulgaria", "BG", "airport"},
+	{"WAW", "Warsaw Chopin Airport", "Warsaw", "Poland", "PL", "airport", "WAW", GeoPoint{52.1657, 20.9671}},
+	{"KRK", "Kraków Airport", "Kraków", "Poland", "PL", "airport", "KRK", GeoPoint{50.0777, 19.7848}},
+	{"GDN", "Gdańsk Airport", "Gdańsk", "Poland", "PL", "airport", "GDN", GeoPoint{54.3776, 18.4662}},
+	{"WRO", "Wrocław Airport", "Wrocław", "Poland", "PL", "airport", "WRO", GeoPoint{51.1027, 16.8858}},
+	{"PRG", "Prague Václav Havel Airport", "Prague", "Czech Republic", "CZ", "airport", "PRG", GeoPoint{50.1008, 14.2600}},
+	{"BUD", "Budapest Ferenc Liszt Airport", "Budapest", "Hungary", "HU", "airport", "BUD", GeoPoint{47.4298, 19.2611}},
+	{"OTP", "Bucharest Henri Coandă Airport", "Bucharest", "Romania", "RO", "airport", "BUH", GeoPoint{44.5711, 26.0850}},
+	{"CLJ", "Cluj-Napoca Airport", "Cluj-Napoca", "Romania", "RO", "airport", "CLJ", GeoPoint{46.7852, 23.6862}},
+	{"BTS", "Bratislava Airport", "Bratislava", "Slovakia", "SK", "airport", "BTS", GeoPoint{48.1702, 17.2127}},
+	{"SOF", "Sofia Airport", "Sofia", "Bulgaria", "BG", "airport", "SOF", GeoPoint{42.6967, 23.4114}},
 	
 	// Portugal
-	{"LIS", "Lisbon Portela Airport", "Lisbon", "Portugal", "PT", "airport"},
-	{"OPO", "Porto Airport", "Porto", "Portugal", "PT", "airport"},
-	{"FAO", "Faro Airport", "Faro", "Portugal", "PT", "airport"},
-	{"FNC", "Madeira Airport", "Funchal", "Portugal", "PT", "airport"},
+	{"LIS", "Lisbon Portela Airport", "Lisbon", "Portugal", "PT", "airport", "LIS", GeoPoint{38.7742, -9.1342}},
+	{"OPO", "Porto Airport", "Porto", "Portugal", "PT", "airport", "OPO", GeoPoint{41.2481, -8.6814}},
+	{"FAO", "Faro Airport", "Faro", "Portugal", "PT", "airport", "FAO", GeoPoint{37.0144, -7.9659}},
+	{"FNC", "Madeira Airport", "Funchal", "Portugal", "PT", "airport", "FNC", GeoPoint{32.6979, -16.7745}},
 	
 	// Greece
-	{"ATH", "Athens Eleftherios Venizelos Airport", "Athens", "Greece", "GR", "airport"},
-	{"SKG", "Thessaloniki Airport", "Thessaloniki", "Greece", "GR", "airport"},
-	{"HER", "Heraklion Airport", "Heraklion", "Greece", "GR", "airport"},
-	{"RHO", "Rhodes Airport", "Rhodes", "Greece", "GR", "airport"},
-	{"CFU", "Corfu Airport", "Corfu", "Greece", "GR", "airport"},
-	{"JTR", "Santorini Airport", "Santorini", "Greece", "GR", "airport"},
+	{"ATH", "Athens Eleftherios Venizelos Airport", "Athens", "Greece", "GR", "airport", "ATH", GeoPoint{37.9364, 23.9445}},
+	{"SKG", "Thessaloniki Airport", "Thessaloniki", "Greece", "GR", "airport", "SKG", GeoPoint{40.5197, 22.9709}},
+	{"HER", "Heraklion Airport", "Heraklion", "Greece", "GR", "airport", "HER", GeoPoint{35.3397, 25.1803}},
+	{"RHO", "Rhodes Airport", "Rhodes", "Greece", "GR", "airport", "RHO", GeoPoint{36.4054, 28.0862}},
+	{"CFU", "Corfu Airport", "Corfu", "Greece", "GR", "airport", "CFU", GeoPoint{39.6019, 19.9117}},
+	{"JTR", "Santorini Airport", "Santorini", "Greece", "GR", "airport", "JTR", GeoPoint{36.3992, 25.4793}},
 	
 	// Turkey (European part)
-	{"IST", "Istanbul Airport", "Istanbul", "Turkey", "TR", "airport"},
-	{"SAW", "Sabiha Gökçen Airport", "Istanbul", "Turkey", "TR", "airport"},
+	{"IST", "Istanbul Airport", "Istanbul", "Turkey", "TR", "airport", "IST", GeoPoint{41.2753, 28.7519}},
+	{"SAW", "Sabiha Gökçen Airport", "Istanbul", "Turkey", "TR", "airport", "IST", GeoPoint{40.8986, 29.3092}},
 	
 	// Croatia
-	{"ZAG", "Zagreb Airport", "Zagreb", "Croatia", "HR", "airport"},
-	{"SPU", "Split Airport", "Split", "Croatia", "HR", "airport"},
-	{"DBV", "Dubrovnik Airport", "Dubrovnik", "Croatia", "HR", "airport"},
-	{"PUY", "Pula Airport", "Pula", "Croatia", "HR", "airport"},
+	{"ZAG", "Zagreb Airport", "Zagreb", "Croatia", "HR", "airport", "ZAG", GeoPoint{45.7429, 16.0688}},
+	{"SPU", "Split Airport", "Split", "Croatia", "HR", "airport", "SPU", GeoPoint{43.5389, 16.2980}},
+	{"DBV", "Dubrovnik Airport", "Dubrovnik", "Croatia", "HR", "airport", "DBV", GeoPoint{42.5614, 18.2682}},
+	{"PUY", "Pula Airport", "Pula", "Croatia", "HR", "airport", "PUY", GeoPoint{44.8935, 13.9222}},
 	
 	// Slovenia
-	{"LJU", "Ljubljana Jože Pučnik Airport", "Ljubljana", "Slovenia", "SI", "airport"},
-	{"MBX", "Maribor Airport", "Maribor", "Slovenia", "SI", "airport"},
+	{"LJU", "Ljubljana Jože Pučnik Airport", "Ljubljana", "Slovenia", "SI", "airport", "LJU", GeoPoint{46.2237, 14.4576}},
+	{"MBX", "Maribor Airport", "Maribor", "Slovenia", "SI", "airport", "MBX", GeoPoint{46.4799, 15.6862}},
 	
 	// Baltic States
-	{"RIX", "Riga Airport", "Riga", "Latvia", "LV", "airport"},
-	{"TLL", "Tallinn Airport", "Tallinn", "Estonia", "EE", "airport"},
-	{"VNO", "Vilnius Airport", "Vilnius", "Lithuania", "LT", "airport"},
-	{"KUN", "Kaunas Airport", "Kaunas", "Lithuania", "LT", "airport"},
+	{"RIX", "Riga Airport", "Riga", "Latvia", "LV", "airport", "RIX", GeoPoint{56.9236, 23.9711}},
+	{"TLL", "Tallinn Airport", "Tallinn", "Estonia", "EE", "airport", "TLL", GeoPoint{59.4133, 24.8328}},
+	{"VNO", "Vilnius Airport", "Vilnius", "Lithuania", "LT", "airport", "VNO", GeoPoint{54.6341, 25.2858}},
+	{"KUN", "Kaunas Airport", "Kaunas", "Lithuania", "LT", "airport", "KUN", GeoPoint{54.9639, 24.0848}},
 	
 	// Iceland
-	{"KEF", "Keflavík International Airport", "Reykjavik", "Iceland", "IS", "airport"},
-	{"RKV", "Reykjavik Airport", "Reykjavik", "Iceland", "IS", "airport"},
+	{"KEF", "Keflavík International Airport", "Reykjavik", "Iceland", "IS", "airport", "REK", GeoPoint{63.9850, -22.6056}},
+	{"RKV", "Reykjavik Airport", "Reykjavik", "Iceland", "IS", "airport", "REK", GeoPoint{64.1300, -21.9406}},
 	
 	// Malta
-	{"MLA", "Malta International Airport", "Valletta", "Malta", "MT", "airport"},
+	{"MLA", "Malta International Airport", "Valletta", "Malta", "MT", "airport", "MLA", GeoPoint{35.8575, 14.4775}},
 	
 	// Cyprus
-	{"LCA", "Larnaca Airport", "Larnaca", "Cyprus", "CY", "airport"},
-	{"PFO", "Paphos Airport", "Paphos", "Cyprus", "CY", "airport"},
+	{"LCA", "Larnaca Airport", "Larnaca", "Cyprus", "CY", "airport", "LCA", GeoPoint{34.8751, 33.6249}},
+	{"PFO", "Paphos Airport", "Paphos", "Cyprus", "CY", "airport", "PFO", GeoPoint{34.7180, 32.4857}},
 }
 
 func main() {
@@ -223,11 +231,19 @@ func createAirportIndex(client *elastic.Client, indexName string) error {
 	}
 
 	if exists {
-		log.Printf("Index %s already exists, deleting...", indexName)
-		_, err := client.DeleteIndex(indexName).Do(context.Background())
+		// The search service serves the index through an alias, so delete the versioned
+		// indices behind it; the next start or cmd/reindex moves this index behind the alias
+		indices, err := client.IndexGet(indexName).Do(context.Background())
 		if err != nil {
 			return err
 		}
+		for concrete := range indices {
+			log.Printf("Index %s already exists, deleting...", concrete)
+			_, err := client.DeleteIndex(concrete).Do(context.Background())
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Create index with mapping
@@ -252,7 +268,9 @@ func createAirportIndex(client *elastic.Client, indexName string) error {
 				"city": {"type": "text", "analyzer": "standard_folding"},
 				"country": {"type": "text", "analyzer": "standard_folding"},
 				"country_code": {"type": "keyword"},
-				"type": {"type": "keyword"}
+				"type": {"type": "keyword"},
+				"city_code": {"type": "keyword"},
+				"coordinates": {"type": "geo_point"}
 			}
 		}
 	}`
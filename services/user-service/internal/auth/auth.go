@@ -11,10 +11,17 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// User roles carried in the role claim
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // Claims represents the JWT claims
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
+	Role   string    `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -48,10 +55,15 @@ func (s *AuthService) VerifyPassword(password, hash string) bool {
 }
 
 // GenerateAccessToken generates a JWT access token
-func (s *AuthService) GenerateAccessToken(userID uuid.UUID, email string) (string, error) {
+func (s *AuthService) GenerateAccessToken(userID uuid.UUID, email, role string) (string, error) {
+	if role == "" {
+		role = RoleUser
+	}
+
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
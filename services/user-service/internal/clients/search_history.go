@@ -0,0 +1,46 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SearchHistoryClient talks to search-service about a user's search history
+type SearchHistoryClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewSearchHistoryClient creates a new search history client
+func NewSearchHistoryClient(baseURL string) *SearchHistoryClient {
+	return &SearchHistoryClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// ClaimSessionHistory moves the anonymous searches of a search session onto the user the
+// access token was issued to. search-service takes the user from the verified token and only
+// accepts a session token it signed itself.
+func (c *SearchHistoryClient) ClaimSessionHistory(sessionID, accessToken string) error {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v1/search/history/claim", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create claim request: %w", err)
+	}
+	req.Header.Set("X-Session-ID", sessionID)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to claim search history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("search history claim returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -31,6 +31,9 @@ type Config struct {
 	
 	// Redis settings (for session storage)
 	RedisURL string
+	
+	// Search service (for claiming anonymous search history on login)
+	SearchServiceURL string
 }
 
 // Load loads configuration from environment variables
@@ -61,6 +64,9 @@ func Load() (*Config, error) {
 		
 		// Redis
 		RedisURL: getEnv("REDIS_URL", "redis://localhost:6379"),
+		
+		// Search service
+		SearchServiceURL: getEnv("SEARCH_SERVICE_URL", "http://localhost:8081"),
 	}
 	
 	// JWT expiry
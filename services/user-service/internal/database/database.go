@@ -99,6 +99,11 @@ func (db *DB) Migrate() error {
 	CREATE INDEX IF NOT EXISTS idx_user_preferences_user_id ON user_preferences(user_id);
 	`
 
+	// Roles are granted directly in the database; tokens carry them as the role claim
+	addUserRoleColumn := `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+	`
+
 	// Create trigger for updating updated_at timestamps
 	createUpdateTrigger := `
 	CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -128,6 +133,7 @@ func (db *DB) Migrate() error {
 		createPreferencesTable,
 		createSessionsTable,
 		createIndexes,
+		addUserRoleColumn,
 		createUpdateTrigger,
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"spontra/user-service/internal/auth"
+	"spontra/user-service/internal/clients"
 	"spontra/user-service/internal/middleware"
 	"spontra/user-service/internal/models"
 	"spontra/user-service/internal/repository"
@@ -19,6 +20,7 @@ type AuthHandler struct {
 	authService       *auth.AuthService
 	userRepo          *repository.UserRepository
 	sessionRepo       *repository.SessionRepository
+	searchHistory     *clients.SearchHistoryClient
 }
 
 // NewAuthHandler creates a new authentication handler
@@ -26,11 +28,13 @@ func NewAuthHandler(
 	authService *auth.AuthService,
 	userRepo *repository.UserRepository,
 	sessionRepo *repository.SessionRepository,
+	searchHistory *clients.SearchHistoryClient,
 ) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
+		authService:   authService,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		searchHistory: searchHistory,
 	}
 }
 
@@ -93,7 +97,7 @@ func (h *AuthHandler) RegisterUser(c *gin.Context) {
 	}
 
 	// Generate tokens
-	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email)
+	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "token_generation_failed",
@@ -180,7 +184,7 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 	}
 
 	// Generate tokens
-	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email)
+	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "token_generation_failed",
@@ -217,6 +221,15 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
+	// Claim the anonymous search history of this browser session
+	if req.SearchSessionID != "" && h.searchHistory != nil {
+		go func(sessionID string, userID uuid.UUID, accessToken string) {
+			if err := h.searchHistory.ClaimSessionHistory(sessionID, accessToken); err != nil {
+				fmt.Printf("Failed to claim search history for user %s: %v\n", userID, err)
+			}
+		}(req.SearchSessionID, user.ID, accessToken)
+	}
+
 	// Remove password hash from response
 	user.PasswordHash = ""
 
@@ -286,7 +299,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// Generate new access token
-	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email)
+	accessToken, err := h.authService.GenerateAccessToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "token_generation_failed",
@@ -16,6 +16,7 @@ type User struct {
 	PhoneNumber  *string   `json:"phone_number,omitempty" db:"phone_number"`
 	ProfileImage *string   `json:"profile_image,omitempty" db:"profile_image"`
 	IsVerified   bool      `json:"is_verified" db:"is_verified"`
+	Role         string    `json:"role" db:"role"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
@@ -62,6 +63,10 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	
+	// SearchSessionID is the X-Session-ID token search-service issued to the client; the history
+	// of that anonymous search session is claimed on login
+	SearchSessionID string `json:"search_session_id,omitempty"`
 }
 
 // LoginResponse represents the successful login response
@@ -26,7 +26,7 @@ func (r *UserRepository) CreateUser(user *models.User) error {
 	query := `
 		INSERT INTO users (id, email, password_hash, first_name, last_name, date_of_birth, phone_number, profile_image, is_verified)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING created_at, updated_at`
+		RETURNING created_at, updated_at, role`
 	
 	err := r.db.QueryRow(
 		query,
@@ -39,7 +39,7 @@ func (r *UserRepository) CreateUser(user *models.User) error {
 		user.PhoneNumber,
 		user.ProfileImage,
 		user.IsVerified,
-	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.CreatedAt, &user.UpdatedAt, &user.Role)
 	
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
@@ -56,7 +56,7 @@ func (r *UserRepository) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, email, password_hash, first_name, last_name, date_of_birth, 
-		       phone_number, profile_image, is_verified, created_at, updated_at, last_login_at
+		       phone_number, profile_image, is_verified, created_at, updated_at, last_login_at, role
 		FROM users 
 		WHERE id = $1`
 	
@@ -73,6 +73,7 @@ func (r *UserRepository) GetUserByID(userID uuid.UUID) (*models.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
+		&user.Role,
 	)
 	
 	if err != nil {
@@ -90,7 +91,7 @@ func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 	user := &models.User{}
 	query := `
 		SELECT id, email, password_hash, first_name, last_name, date_of_birth, 
-		       phone_number, profile_image, is_verified, created_at, updated_at, last_login_at
+		       phone_number, profile_image, is_verified, created_at, updated_at, last_login_at, role
 		FROM users 
 		WHERE email = $1`
 	
@@ -107,6 +108,7 @@ func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
+		&user.Role,
 	)
 	
 	if err != nil {
@@ -169,7 +171,7 @@ func (r *UserRepository) UpdateUser(userID uuid.UUID, updates *models.UpdateUser
 		SET %s, updated_at = CURRENT_TIMESTAMP
 		%s
 		RETURNING id, email, password_hash, first_name, last_name, date_of_birth, 
-		          phone_number, profile_image, is_verified, created_at, updated_at, last_login_at`,
+		          phone_number, profile_image, is_verified, created_at, updated_at, last_login_at, role`,
 		joinStrings(setParts, ", "), whereClause)
 	
 	user := &models.User{}
@@ -186,6 +188,7 @@ func (r *UserRepository) UpdateUser(userID uuid.UUID, updates *models.UpdateUser
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
+		&user.Role,
 	)
 	
 	if err != nil {
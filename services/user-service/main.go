@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"spontra/user-service/internal/auth"
+	"spontra/user-service/internal/clients"
 	"spontra/user-service/internal/config"
 	"spontra/user-service/internal/database"
 	"spontra/user-service/internal/handlers"
@@ -65,7 +66,8 @@ func main() {
 	sessionRepo := repository.NewSessionRepository(db)
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, userRepo, sessionRepo)
+	searchHistoryClient := clients.NewSearchHistoryClient(cfg.SearchServiceURL)
+	authHandler := handlers.NewAuthHandler(authService, userRepo, sessionRepo, searchHistoryClient)
 	userHandler := handlers.NewUserHandler(userRepo)
 
 	// Create router
@@ -2,7 +2,6 @@ package circuit
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sync"
 	"time"
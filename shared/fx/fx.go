@@ -0,0 +1,136 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrUnsupportedCurrency is returned when no rate is known for a currency
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrRatesUnavailable is returned when no rate source could provide rates
+	ErrRatesUnavailable = errors.New("exchange rates unavailable")
+)
+
+// Rates holds exchange rates against a base currency: one unit of Base buys Rates[code] units
+// of code
+type Rates struct {
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	UpdatedAt time.Time          `json:"updated_at,omitempty"`
+}
+
+// rate returns the number of units of currency one unit of the base buys
+func (r *Rates) rate(currency string) (decimal.Decimal, bool) {
+	if currency == r.Base {
+		return decimal.NewFromInt(1), true
+	}
+	rate, ok := r.Rates[currency]
+	if !ok || rate <= 0 {
+		return decimal.Zero, false
+	}
+	return decimal.NewFromFloat(rate), true
+}
+
+// Converter converts amounts between currencies. Rates are fetched from the source when first
+// needed and refreshed once they are older than the refresh interval; if a refresh fails the
+// previous rates are kept.
+type Converter struct {
+	source    RateSource
+	base      string
+	refresh   time.Duration
+	mu        sync.Mutex
+	rates     *Rates
+	fetchedAt time.Time
+}
+
+// NewConverter creates a converter that asks the source for rates against the base currency
+func NewConverter(source RateSource, base string, refresh time.Duration) *Converter {
+	return &Converter{
+		source:  source,
+		base:    NormalizeCurrency(base),
+		refresh: refresh,
+	}
+}
+
+// Base returns the currency amounts are normalised to before they are compared
+func (c *Converter) Base() string {
+	return c.base
+}
+
+// Supports reports whether amounts can be converted to and from a currency
+func (c *Converter) Supports(currency string) bool {
+	rates, err := c.current()
+	if err != nil {
+		return NormalizeCurrency(currency) == c.base
+	}
+	_, ok := rates.rate(NormalizeCurrency(currency))
+	return ok
+}
+
+// Rate returns the number of units of to that one unit of from buys
+func (c *Converter) Rate(from, to string) (decimal.Decimal, error) {
+	from, to = NormalizeCurrency(from), NormalizeCurrency(to)
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rates, err := c.current()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, from)
+	}
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, to)
+	}
+
+	return toRate.Div(fromRate), nil
+}
+
+// Convert converts an amount between currencies, rounded to cents
+func (c *Converter) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(rate).Round(2), nil
+}
+
+// current returns the current rates, refreshing them from the source when they are stale
+func (c *Converter) current() (*Rates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && time.Since(c.fetchedAt) < c.refresh {
+		return c.rates, nil
+	}
+
+	rates, err := c.source.Rates(c.base)
+	if err != nil {
+		if c.rates != nil {
+			// Keep converting with the last rates until the next refresh is due
+			c.fetchedAt = time.Now()
+			return c.rates, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRatesUnavailable, err)
+	}
+
+	c.rates = rates
+	c.fetchedAt = time.Now()
+	return rates, nil
+}
+
+// NormalizeCurrency upper-cases and trims an ISO 4217 currency code
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
@@ -0,0 +1,107 @@
+package fx
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultRates are reference rates shipped with the code, used when no rates file is configured
+//
+//go:embed rates.json
+var defaultRates []byte
+
+// RateSource provides exchange rates against a base currency. A source may answer with rates
+// against another base; the converter derives cross rates.
+type RateSource interface {
+	Rates(base string) (*Rates, error)
+}
+
+// Cache is the part of a service's Redis client the cache source needs
+type Cache interface {
+	Get(key string, dest interface{}) error
+}
+
+// CacheSource reads the rates data-ingestion caches with CacheExchangeRates
+type CacheSource struct {
+	cache Cache
+}
+
+// NewCacheSource creates a source reading rates cached by data-ingestion
+func NewCacheSource(cache Cache) *CacheSource {
+	return &CacheSource{cache: cache}
+}
+
+// Rates returns the cached rates against the base currency
+func (s *CacheSource) Rates(base string) (*Rates, error) {
+	var rates map[string]float64
+	if err := s.cache.Get(IngestionRatesKey(base), &rates); err != nil {
+		return nil, fmt.Errorf("failed to load cached rates for %s: %w", base, err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no cached rates for %s", base)
+	}
+	return &Rates{Base: base, Rates: rates}, nil
+}
+
+// IngestionRatesKey is the key data-ingestion caches the rates against a base currency under
+func IngestionRatesKey(base string) string {
+	return fmt.Sprintf("ingestion:rates:%s", base)
+}
+
+// FileSource reads rates from a JSON file in the Rates format. Without a path it serves the
+// reference rates shipped with this package.
+type FileSource struct {
+	path string
+}
+
+// NewFileSource creates a source reading the rates file at path
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// Rates returns the rates in the file, whatever their base
+func (s *FileSource) Rates(base string) (*Rates, error) {
+	data := defaultRates
+	if s.path != "" {
+		var err error
+		if data, err = os.ReadFile(s.path); err != nil {
+			return nil, fmt.Errorf("failed to read rates file: %w", err)
+		}
+	}
+
+	var rates Rates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file: %w", err)
+	}
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return nil, fmt.Errorf("rates file has no base or rates")
+	}
+
+	rates.Base = NormalizeCurrency(rates.Base)
+	normalized := make(map[string]float64, len(rates.Rates))
+	for currency, rate := range rates.Rates {
+		normalized[NormalizeCurrency(currency)] = rate
+	}
+	rates.Rates = normalized
+
+	return &rates, nil
+}
+
+// ChainSource asks each source in turn and returns the first rates found
+type ChainSource []RateSource
+
+// Rates returns the rates of the first source that has them
+func (c ChainSource) Rates(base string) (*Rates, error) {
+	var failures []string
+	for _, source := range c {
+		rates, err := source.Rates(base)
+		if err == nil {
+			return rates, nil
+		}
+		failures = append(failures, err.Error())
+	}
+	return nil, fmt.Errorf("no rate source succeeded: %s", strings.Join(failures, "; "))
+}
@@ -0,0 +1,8 @@
+module spontra/shared
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/shopspring/decimal v1.3.1
+)